@@ -46,14 +46,24 @@ package main
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
 
 	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/codegen"
 	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/owl"
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
 )
 
 func main() {
 	var err error
+	if len(os.Args) == 4 && os.Args[1] == "-c" {
+		err = convert(os.Args[2], os.Args[3])
+		if err != nil {
+			fmt.Println("Error: " + err.Error())
+		}
+		return
+	}
 	if len(os.Args) != 5 {
 		fmt.Println("Error: Wrong number of command line arguments")
 		return
@@ -81,7 +91,15 @@ func main() {
 			fmt.Println("Error: " + err.Error())
 			return
 		}
-		on, err = owl.ExtractOntology(file)
+		format := rdf.FormatFromExtension(ontLoc)
+		if format == "" {
+			format = rdf.FormatTurtle
+		}
+		base := ontLoc
+		if abs, errAbs := filepath.Abs(ontLoc); errAbs == nil {
+			base = (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+		}
+		on, err = owl.ExtractOntologyFormat(file, format, rdf.DecodeOptions{Base: base})
 		if err != nil {
 			fmt.Println("Error: " + err.Error())
 			return
@@ -132,3 +150,31 @@ func main() {
 		return
 	}
 }
+
+// convert reads a rdf document and writes it as pretty printed ttl document
+func convert(input, output string) (err error) {
+	format := rdf.FormatFromExtension(input)
+	if format == "" {
+		format = rdf.FormatTurtle
+	}
+	in, err := os.Open(input)
+	if err != nil {
+		return
+	}
+	defer in.Close()
+	triples, err := rdf.Decode(in, format)
+	if err != nil {
+		return
+	}
+	out, err := os.Create(output)
+	if err != nil {
+		return
+	}
+	err = rdf.EncodeTTLPretty(triples, out)
+	if err != nil {
+		out.Close()
+		return
+	}
+	err = out.Close()
+	return
+}
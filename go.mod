@@ -2,4 +2,4 @@ module git.rwth-aachen.de/acs/public/ontology/owl/owl2go
 
 require github.com/piprate/json-gold v0.3.0
 
-go 1.13
+go 1.16
@@ -47,8 +47,10 @@ THE SOFTWARE.
 package codegen
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/codegen/template"
@@ -184,6 +186,9 @@ func generateModel(mod *owl.GoModel) (ret string) {
 
 	ret += strings.Replace(template.ModelStruct, "###objectMaps###", objectMaps, -1)
 
+	// model options
+	ret += template.ModelOptions
+
 	// New Model
 	makeMaps := ""
 	for i := range mod.Class {
@@ -194,12 +199,18 @@ func generateModel(mod *owl.GoModel) (ret string) {
 	// model exist
 	ret += template.ModelExists
 
+	// encode options
+	ret += template.ModelEncodeOptions
+
 	// ttl to model
 	ret += template.ModelNewFromTTL
 
 	// jsonld to model
 	ret += template.ModelNewFromJSONLD
 
+	// store to model
+	ret += template.ModelNewFromStore
+
 	// graph to model
 	newObjects := ""
 	for i := range mod.Class {
@@ -213,12 +224,22 @@ func generateModel(mod *owl.GoModel) (ret string) {
 	// model to graph
 	ret += template.ModelToGraph
 
+	// model to store
+	ret += template.ModelToStore
+
 	// model to ttl
 	ret += template.ModelToTTL
 
 	// model to jsonld
 	ret += template.ModelToJSONLD
 
+	// jsonld context
+	context, _ := json.Marshal(mod.JSONLDContext())
+	ret += strings.Replace(template.ModelJSONLDContext, "###jsonldContext###",
+		strconv.Quote(string(context)), -1)
+	ret += template.ModelToJSONLDCompact
+	ret += template.ModelToJSONLDFramed
+
 	// delete object
 	deleteFromMaps := ""
 	for i := range mod.Class {
@@ -288,13 +309,36 @@ func generateProperties(mod *owl.GoModel) (str, man, ser, ifc string) {
 				mod.Class[i].Property[j].BaseTyp[0] == "bool" {
 				manImport["strconv"] = ""
 			}
+			prop := mod.Class[i].Property[j]
+			if imp := typeImport(prop.Typ[0]); imp != "" {
+				strImport[imp] = ""
+			}
+			if imp := typeImport(prop.BaseTyp[0]); imp != "" {
+				manImport[imp] = ""
+				ifcImport[imp] = ""
+			}
+			if len(prop.AllowedTyp) > 1 || prop.AllowedTyp[0] != prop.BaseTyp {
+				for k := range prop.AllowedTyp {
+					if imp := typeImport(prop.AllowedTyp[k][0]); imp != "" {
+						manImport[imp] = ""
+					}
+				}
+			}
+			if isValueType(prop.Typ[0]) && prop.Inverse == "" {
+				manImport["git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"] = ""
+				if imp := typeImport(prop.Typ[0]); imp != "" {
+					manImport[imp] = ""
+				}
+			}
 			// if mod.Class[i].Property[j].Typ[0] == "time.Duration" {
 			// 	manImport[mod.Module+"/internal/helper"] = ""
 			// }
 		}
 
 	}
+	strImport["git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"] = ""
 	manImport["git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/owl"] = ""
+	manImport["git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"] = ""
 	serImport["git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/owl"] = ""
 	serImport["git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"] = ""
 	serImport["fmt"] = ""
@@ -380,6 +424,50 @@ func generateProperties(mod *owl.GoModel) (str, man, ser, ifc string) {
 	return
 }
 
+// isValueType returns true for literal types that are converted with the datatype registry of the
+// rdf package (e.g. *big.Int, rdf.Decimal, []byte)
+func isValueType(goType string) (ok bool) {
+	switch goType {
+	case "string", "bool", "int", "float64", "interface{}", "time.Duration":
+	default:
+		ok = owl.IsLiteralType(goType)
+	}
+	return
+}
+
+// typeImport returns the package that has to be imported to use a Go type (empty if none)
+func typeImport(goType string) (imp string) {
+	switch goType {
+	case "*big.Int":
+		imp = "math/big"
+	case "*url.URL":
+		imp = "net/url"
+	default:
+		if strings.HasPrefix(goType, "rdf.") {
+			imp = "git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+		}
+	}
+	return
+}
+
+// goTypeName returns the name of a Go type without package that can be used in identifiers
+func goTypeName(goType string) (name string) {
+	switch goType {
+	case "interface{}":
+		name = "interface"
+	case "[]byte":
+		name = "Bytes"
+	case "*big.Int":
+		name = "BigInt"
+	case "rdf.Time":
+		name = "XsdTime"
+	default:
+		temp := strings.Split(goType, ".")
+		name = temp[len(temp)-1]
+	}
+	return
+}
+
 // generatePropertyName generates the name of a property based on the type, basetype and allowed
 // types
 func generatePropertyName(prop owl.GoProperty) (ret string) {
@@ -388,22 +476,16 @@ func generatePropertyName(prop owl.GoProperty) (ret string) {
 		ret += "GoTime"
 	} else if prop.BaseTyp[0] == "time.Duration" {
 		ret += "GoDuration"
-	} else if prop.BaseTyp[0] == "interface{}" {
-		ret += "interface"
 	} else {
-		temp := strings.Split(prop.BaseTyp[0], ".")
-		ret += temp[len(temp)-1]
+		ret += goTypeName(prop.BaseTyp[0])
 	}
 	ret += "Type"
 	if prop.Typ[0] == "time.Time" {
 		ret += "GoTime"
 	} else if prop.Typ[0] == "time.Duration" {
 		ret += "GoDuration"
-	} else if prop.Typ[0] == "interface{}" {
-		ret += "interface"
 	} else {
-		temp := strings.Split(prop.Typ[0], ".")
-		ret += temp[len(temp)-1]
+		ret += goTypeName(prop.Typ[0])
 	}
 	if prop.Multi {
 		ret += "Multiple"
@@ -415,11 +497,8 @@ func generatePropertyName(prop owl.GoProperty) (ret string) {
 			ret += "GoTime"
 		} else if prop.AllowedTyp[i][0] == "time.Duration" {
 			ret += "GoDuration"
-		} else if prop.AllowedTyp[i][0] == "interface{}" {
-			ret += "interface"
 		} else {
-			temp := strings.Split(prop.AllowedTyp[i][0], ".")
-			ret += temp[len(temp)-1]
+			ret += goTypeName(prop.AllowedTyp[i][0])
 		}
 	}
 	return
@@ -429,9 +508,7 @@ func generatePropertyName(prop owl.GoProperty) (ret string) {
 func generatePropertyStruct(prop owl.GoProperty) (ret string) {
 	propName := generatePropertyName(prop)
 	if prop.Multi {
-		if prop.Typ[0] == "time.Time" || prop.Typ[0] == "time.Duration" ||
-			prop.Typ[0] == "float64" || prop.Typ[0] == "string" || prop.Typ[0] == "int" ||
-			prop.Typ[0] == "interface{}" {
+		if owl.IsLiteralType(prop.Typ[0]) {
 			ret += strings.Replace(strings.Replace(strings.Replace(strings.Replace(
 				template.PropertyStructMultipleLiteral, "###propName###", prop.Name, -1),
 				"###propType###", prop.Typ[0], -1),
@@ -456,14 +533,7 @@ func generatePropertyStruct(prop owl.GoProperty) (ret string) {
 
 // generatePropertyInterface generates the interface that belongs to a property
 func generatePropertyInterface(prop owl.GoProperty) (ret string) {
-	baseTypeNoImp := prop.BaseTyp[0]
-	temp := strings.Split(prop.BaseTyp[0], ".")
-	if len(temp) > 0 {
-		baseTypeNoImp = temp[len(temp)-1]
-	}
-	if baseTypeNoImp == "interface{}" {
-		baseTypeNoImp = "interface"
-	}
+	baseTypeNoImp := goTypeName(prop.BaseTyp[0])
 	if prop.Multi {
 		ret = strings.Replace(strings.Replace(strings.Replace(strings.Replace(strings.Replace(
 			template.PropertyInterfaceMultiple, "###propCapital###", prop.Capital, -1),
@@ -486,14 +556,17 @@ func generatePropertyInterface(prop owl.GoProperty) (ret string) {
 func generatePropertyManipulator(prop owl.GoProperty) (ret string) {
 	propName := generatePropertyName(prop)
 	if prop.Multi {
-		if prop.Typ[0] == "time.Time" || prop.Typ[0] == "time.Duration" ||
-			prop.Typ[0] == "float64" || prop.Typ[0] == "string" || prop.Typ[0] == "int" ||
-			prop.Typ[0] == "interface{}" {
+		if owl.IsLiteralType(prop.Typ[0]) {
 			if prop.Inverse != "" {
 				ret = template.PropertyGetMultipleLiteral
 			} else {
 				ret = template.PropertyGetMultipleLiteral + template.PropertySetMultipleLiteral +
-					template.PropertyAddLiteral + template.PropertyDelLiteral
+					template.PropertyAddLiteral
+				if isValueType(prop.Typ[0]) {
+					ret += template.PropertyDelValue
+				} else {
+					ret += template.PropertyDelLiteral
+				}
 			}
 		} else {
 			if prop.Inverse != "" {
@@ -523,9 +596,7 @@ func generatePropertyManipulator(prop owl.GoProperty) (ret string) {
 		}
 	} else {
 		ret += template.PropertyGetSingle
-		if prop.Typ[0] == "time.Time" || prop.Typ[0] == "time.Duration" ||
-			prop.Typ[0] == "float64" || prop.Typ[0] == "string" || prop.Typ[0] == "int" ||
-			prop.Typ[0] == "interface{}" && prop.Inverse == "" {
+		if owl.IsLiteralType(prop.Typ[0]) {
 			if len(prop.AllowedTyp) == 1 && prop.AllowedTyp[0] == prop.BaseTyp {
 				ret += template.PropertySetSingleLiteral
 			} else {
@@ -575,36 +646,6 @@ func generatePropertyManipulator(prop owl.GoProperty) (ret string) {
 			mult = template.MultiplicitySingle
 		}
 		switch prop.Typ[0] {
-		case "time.Time":
-			switch prop.XSDTyp {
-			case "http://www.w3.org/2001/XMLSchema#time":
-				initProp = strings.Replace(template.PropertyInitLiteral, "###PropInit###",
-					template.PropInitTime, -1)
-			case "http://www.w3.org/2001/XMLSchema#dateTime":
-				initProp = strings.Replace(template.PropertyInitLiteral, "###PropInit###",
-					template.PropInitDateTime, -1)
-			case "http://www.w3.org/2001/XMLSchema#date":
-				initProp = strings.Replace(template.PropertyInitLiteral, "###PropInit###",
-					template.PropInitDate, -1)
-			case "http://www.w3.org/2001/XMLSchema#dateTimeStamp":
-				initProp = strings.Replace(template.PropertyInitLiteral, "###PropInit###",
-					template.PropInitDateTimeStamp, -1)
-			case "http://www.w3.org/2001/XMLSchema#gYear":
-				initProp = strings.Replace(template.PropertyInitLiteral, "###PropInit###",
-					template.PropInitGYear, -1)
-			case "http://www.w3.org/2001/XMLSchema#gDay":
-				initProp = strings.Replace(template.PropertyInitLiteral, "###PropInit###",
-					template.PropInitGDay, -1)
-			case "http://www.w3.org/2001/XMLSchema#gYearMonth":
-				initProp = strings.Replace(template.PropertyInitLiteral, "###PropInit###",
-					template.PropInitGYearMonth, -1)
-			case "http://www.w3.org/2001/XMLSchema#gMonth":
-				initProp = strings.Replace(template.PropertyInitLiteral, "###PropInit###",
-					template.PropInitGMonth, -1)
-			}
-		case "time.Duration":
-			initProp = strings.Replace(template.PropertyInitLiteral, "###PropInit###",
-				template.PropInitDuration, -1)
 		case "int":
 			initProp = strings.Replace(template.PropertyInitLiteral, "###PropInit###",
 				template.PropInitInt, -1)
@@ -620,9 +661,17 @@ func generatePropertyManipulator(prop owl.GoProperty) (ret string) {
 		case "interface{}":
 			initProp = strings.Replace(template.PropertyInitLiteral, "###PropInit###",
 				template.PropInitInterface, -1)
+		case "rdf.LangString":
+			initProp = strings.Replace(template.PropertyInitTerm, "###PropInit###",
+				template.PropInitLangString, -1)
 		default:
 			tempSp := strings.Split(prop.BaseTyp[0], ".")
-			if prop.BaseTyp[0] == "owl.Thing" {
+			if owl.IsLiteralType(prop.Typ[0]) {
+				initProp = strings.Replace(strings.Replace(strings.Replace(
+					template.PropertyInitLiteral, "###PropInit###", template.PropInitValue, -1),
+					"###xsdType###", prop.XSDTyp, -1),
+					"###propType###", prop.Typ[0], -1)
+			} else if prop.BaseTyp[0] == "owl.Thing" {
 				initProp = strings.Replace(template.PropertyInitClass, "###PropInit###",
 					template.PropInitClassBaseThing, -1)
 			} else if len(tempSp) > 1 {
@@ -643,9 +692,7 @@ func generatePropertyManipulator(prop owl.GoProperty) (ret string) {
 			"###propBaseType###", baseType, -1),
 			"###propCapital###", prop.Capital, -1)
 
-		if prop.Typ[0] == "time.Time" || prop.Typ[0] == "time.Duration" ||
-			prop.Typ[0] == "float64" || prop.Typ[0] == "string" || prop.Typ[0] == "int" &&
-			prop.Inverse == "" || prop.Typ[0] == "bool" || prop.Typ[0] == "interface{}" {
+		if owl.IsLiteralType(prop.Typ[0]) {
 			return
 		}
 		if prop.Multi {
@@ -685,29 +732,12 @@ func generatePropertySerializer(prop owl.GoProperty) (ret string) {
 	case "interface{}":
 		graphProp = template.GraphPropInterface
 		stringProp = template.StringPropInterface
-	case "time.Time", "time.Duration":
-		stringProp = template.StringPropTime
-		switch prop.XSDTyp {
-		case "http://www.w3.org/2001/XMLSchema#time":
-			graphProp = template.GraphPropSTime
-		case "http://www.w3.org/2001/XMLSchema#dateTime":
-			graphProp = template.GraphPropSDateTime
-		case "http://www.w3.org/2001/XMLSchema#date":
-			graphProp = template.GraphPropSDate
-		case "http://www.w3.org/2001/XMLSchema#duration":
-			graphProp = template.GraphPropSDuration
-		case "http://www.w3.org/2001/XMLSchema#dateTimeStamp":
-			graphProp = template.GraphPropSDateTimeStamp
-		case "http://www.w3.org/2001/XMLSchema#gYear":
-			graphProp = template.GraphPropSGYear
-		case "http://www.w3.org/2001/XMLSchema#gDay":
-			graphProp = template.GraphPropSGDay
-		case "http://www.w3.org/2001/XMLSchema#gYearMonth":
-			graphProp = template.GraphPropSGYearMonth
-		case "http://www.w3.org/2001/XMLSchema#gMonth":
-			graphProp = template.GraphPropSGMonth
-		}
 	default:
+		if owl.IsLiteralType(prop.Typ[0]) {
+			graphProp = template.GraphPropValue
+			stringProp = template.StringPropInterface
+			break
+		}
 		graphProp = template.GraphPropClass
 		if prop.Multi {
 			stringProp = template.StringPropClassMultiple
@@ -735,6 +765,7 @@ func generatePropertySerializer(prop owl.GoProperty) (ret string) {
 		"###propBaseType###", prop.BaseTyp[0], -1),
 		"###propLongName###", propName, -1),
 		"###propCapital###", prop.Capital, -1)
+	ret = strings.Replace(ret, "###xsdType###", prop.XSDTyp, -1)
 	return
 }
 
@@ -824,14 +855,7 @@ func generateClass(class owl.GoClass, mod *owl.GoModel) (ret string) {
 			if class.Property[i].Multi {
 				multi = "Multiple"
 			}
-			baseTypeNoImp := prop.BaseTyp[0]
-			temp := strings.Split(prop.BaseTyp[0], ".")
-			if len(temp) > 0 {
-				baseTypeNoImp = temp[len(temp)-1]
-			}
-			if baseTypeNoImp == "interface{}" {
-				baseTypeNoImp = "interface"
-			}
+			baseTypeNoImp := goTypeName(prop.BaseTyp[0])
 			interfaceMethods += strings.Replace(strings.Replace(strings.Replace(
 				template.InterfaceInterface, "###propName###", prop.Name, -1),
 				"###propBaseTypeNoImp###", baseTypeNoImp, -1),
@@ -910,9 +934,7 @@ func generateClass(class owl.GoClass, mod *owl.GoModel) (ret string) {
 		if isParentProp {
 			continue
 		}
-		if prop.Multi && !(prop.Typ[0] == "time.Time" || prop.Typ[0] == "time.Duration" ||
-			prop.Typ[0] == "float64" || prop.Typ[0] == "string" || prop.Typ[0] == "int" ||
-			prop.Typ[0] == "interface{}") {
+		if prop.Multi && !owl.IsLiteralType(prop.Typ[0]) {
 			newMakeMaps += strings.Replace(strings.Replace(template.NewMakeMap,
 				"###propName###", prop.Name, -1),
 				"###propType###", prop.Typ[0], -1)
@@ -928,7 +950,6 @@ func generateClass(class owl.GoClass, mod *owl.GoModel) (ret string) {
 						newInitProps += strings.Replace(template.NewInitPropLiteralSingle,
 							"###value###", "\""+prop.Individual[j]+"\"", -1)
 					}
-				case "time.Time", "time.Duration":
 				case "float64", "int":
 					if prop.Multi {
 						newInitProps += template.NewInitPropLiteralMultiple
@@ -936,6 +957,9 @@ func generateClass(class owl.GoClass, mod *owl.GoModel) (ret string) {
 						newInitProps += template.NewInitPropLiteralSingle
 					}
 				default:
+					if owl.IsLiteralType(prop.Typ[0]) {
+						continue
+					}
 					if prop.Multi {
 						newInitProps += template.NewInitPropClassMultiple
 					} else {
@@ -997,10 +1021,7 @@ func generateClass(class owl.GoClass, mod *owl.GoModel) (ret string) {
 		if isParentProp {
 			continue
 		}
-		if prop.BaseTyp[0] == "string" || prop.BaseTyp[0] == "float64" ||
-			prop.BaseTyp[0] == "int" || prop.BaseTyp[0] == "time.Time" ||
-			prop.BaseTyp[0] == "time.Duration" || prop.BaseTyp[0] == "bool" ||
-			prop.BaseTyp[0] == "interface{}" {
+		if owl.IsLiteralType(prop.BaseTyp[0]) {
 			continue
 		}
 		if prop.Inverse == "" {
@@ -1065,8 +1086,7 @@ func generateClass(class owl.GoClass, mod *owl.GoModel) (ret string) {
 			propName := generatePropertyName(prop)
 			temp := ""
 			if class.Property[i].Multi {
-				if prop.Typ[0] == "time.Time" || prop.Typ[0] == "time.Duration" ||
-					prop.Typ[0] == "float64" || prop.Typ[0] == "string" {
+				if owl.IsLiteralType(prop.Typ[0]) {
 					ret = ""
 					continue
 				}
@@ -1153,9 +1173,11 @@ func generateClass(class owl.GoClass, mod *owl.GoModel) (ret string) {
 		temp := strings.Replace(template.InitSwitchProp, "###propIRI###", prop.IRI, -1)
 		if prop.Inverse == "" {
 			propName := generatePropertyName(prop)
-			if prop.Typ[0] == "time.Time" || prop.Typ[0] == "int" || prop.Typ[0] == "string" ||
-				prop.Typ[0] == "float64" || prop.Typ[0] == "bool" ||
-				prop.Typ[0] == "time.Duration" || prop.Typ[0] == "interface{}" {
+			if prop.Typ[0] == "rdf.LangString" {
+				initSwitchProps += strings.Replace(strings.Replace(temp,
+					"###PropInit###", template.PropInitTermNonInverse, -1),
+					"###propLongName###", propName, -1)
+			} else if owl.IsLiteralType(prop.Typ[0]) {
 				initSwitchProps += strings.Replace(strings.Replace(temp,
 					"###PropInit###", template.PropInitLiteralNonInverse, -1),
 					"###propLongName###", propName, -1)
@@ -1172,27 +1194,6 @@ func generateClass(class owl.GoClass, mod *owl.GoModel) (ret string) {
 				mult = template.MultiplicitySingle
 			}
 			switch prop.Typ[0] {
-			case "time.Time":
-				switch prop.XSDTyp {
-				case "http://www.w3.org/2001/XMLSchema#time":
-					temp = strings.Replace(temp, "###PropInit###", template.PropTime, -1)
-				case "http://www.w3.org/2001/XMLSchema#dateTime":
-					temp = strings.Replace(temp, "###PropInit###", template.PropDateTime, -1)
-				case "http://www.w3.org/2001/XMLSchema#date":
-					temp = strings.Replace(temp, "###PropInit###", template.PropDate, -1)
-				case "http://www.w3.org/2001/XMLSchema#dateTimeStamp":
-					temp = strings.Replace(temp, "###PropInit###", template.PropDateTimeStamp, -1)
-				case "http://www.w3.org/2001/XMLSchema#gYear":
-					temp = strings.Replace(temp, "###PropInit###", template.PropGYear, -1)
-				case "http://www.w3.org/2001/XMLSchema#gDay":
-					temp = strings.Replace(temp, "###PropInit###", template.PropGDay, -1)
-				case "http://www.w3.org/2001/XMLSchema#gYearMonth":
-					temp = strings.Replace(temp, "###PropInit###", template.PropGYearMonth, -1)
-				case "http://www.w3.org/2001/XMLSchema#gMonth":
-					temp = strings.Replace(temp, "###PropInit###", template.PropGMonth, -1)
-				}
-			case "time.Duration":
-				temp = strings.Replace(temp, "###PropInit###", template.PropDuration, -1)
 			case "int":
 				temp = strings.Replace(temp, "###PropInit###", template.PropInt, -1)
 			case "float64":
@@ -1201,9 +1202,16 @@ func generateClass(class owl.GoClass, mod *owl.GoModel) (ret string) {
 				temp = strings.Replace(temp, "###PropInit###", template.PropBool, -1)
 			case "string":
 				temp = strings.Replace(temp, "###PropInit###", template.PropString, -1)
+			case "rdf.LangString":
+				temp = strings.Replace(temp, "###PropInit###", template.PropLangString, -1)
 			default:
 				tempSp := strings.Split(prop.BaseTyp[0], ".")
-				if prop.BaseTyp[0] == "owl.Thing" {
+				if owl.IsLiteralType(prop.Typ[0]) {
+					temp = strings.Replace(strings.Replace(strings.Replace(temp,
+						"###PropInit###", template.PropValue, -1),
+						"###xsdType###", prop.XSDTyp, -1),
+						"###propType###", prop.Typ[0], -1)
+				} else if prop.BaseTyp[0] == "owl.Thing" {
 					temp = strings.Replace(temp, "###PropInit###", template.PropClassBaseThing, -1)
 				} else if len(tempSp) > 1 {
 					imName := strings.TrimPrefix(tempSp[0], "im")
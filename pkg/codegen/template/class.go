@@ -83,7 +83,7 @@ var ClassNew = "// New###className### creates a new ###className###\n" +
 	"\t\treturn\n" +
 	"\t}\n" +
 	"\tres := &s###className###{}\n" +
-	"\tpc := propCommon{iri: iri, typ: \"###className###\", model: mod}\n" +
+	"\tpc := propCommon{iri: iri, term: rdf.NewIRI(iri), typ: \"###className###\", model: mod}\n" +
 	"\tres.propCommon = pc\n" +
 	"\tmod.add###className###(res)\n" +
 	"\tres.makeMaps()\n" +
@@ -330,6 +330,7 @@ var ClassInverseSingleSingle = "// Set###propCapital### is setter of ###comment#
 // ClassInit template
 var ClassInit = "// InitFromNode initializes the resource from a graph node\n" +
 	"func (res *s###className###) InitFromNode(node *rdf.Node) (err error) {\n" +
+	"\tres.term = node.Term\n" +
 	"\tfor i := range node.Edge {\n" +
 	"\t\tres.propsInit(node.Edge[i])\n" +
 	"\t}\n" +
@@ -357,6 +358,9 @@ var PropInitClassNonInverse = "\t\tres.###propLongName###.init(res.model, pred.O
 // PropInitLiteralNonInverse template
 var PropInitLiteralNonInverse = "\t\tres.###propLongName###.init(pred.Object.Term.String())\n"
 
+// PropInitTermNonInverse template
+var PropInitTermNonInverse = "\t\tres.###propLongName###.init(pred.Object.Term)\n"
+
 // PropClassBaseThing template
 var PropClassBaseThing = "\t\tif obj, ok := res.model.mThing[pred.Object.Term.String()]; ok {\n" +
 	"\t\t\tres.###Multiplicity######propCapital###(obj)\n" +
@@ -376,51 +380,6 @@ var PropClassImport = "\t\tif temp := res.model.###capImportName######propBaseTy
 	"\t\t\t}\n" +
 	"\t\t}\n"
 
-// PropTime template
-var PropTime = "\t\tif obj, err := time.Parse(\"15:04:05Z07:00\", pred.Object.Term.String()); err == nil {\n" +
-	"\t\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t\t}\n"
-
-// PropDate template
-var PropDate = "\t\tif obj, err := time.Parse(\"2006-01-02Z07:00\", pred.Object.Term.String()); err == nil {\n" +
-	"\t\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t\t}\n"
-
-// PropDateTime template
-var PropDateTime = "\t\tif obj, err := time.Parse(time.RFC3339, pred.Object.Term.String()); err == nil {\n" +
-	"\t\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t\t}\n"
-
-// PropDateTimeStamp template
-var PropDateTimeStamp = "\t\tif obj, err := time.Parse(time.RFC3339, pred.Object.Term.String()); err == nil {\n" +
-	"\t\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t\t}\n"
-
-// PropGDay template
-var PropGDay = "\t\tif obj, err := time.Parse(\"---02\", pred.Object.Term.String()); err == nil {\n" +
-	"\t\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t\t}\n"
-
-// PropGMonth template
-var PropGMonth = "\t\tif obj, err := time.Parse(\"--01\", pred.Object.Term.String()); err == nil {\n" +
-	"\t\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t\t}\n"
-
-// PropGYear template
-var PropGYear = "\t\tif obj, err := time.Parse(\"2006\", pred.Object.Term.String()); err == nil {\n" +
-	"\t\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t\t}\n"
-
-// PropGYearMonth template
-var PropGYearMonth = "\t\tif obj, err := time.Parse(\"2006-01\", pred.Object.Term.String()); err == nil {\n" +
-	"\t\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t\t}\n"
-
-// PropDuration template
-var PropDuration = "\t\tif obj, err := owl.ParseXsdDuration(pred.Object.Term.String()); err == nil {\n" +
-	"\t\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t\t}\n"
-
 // PropFloat template
 var PropFloat = "\t\tif obj, err := strconv.ParseFloat(pred.Object.Term.String(), 32); err == nil {\n" +
 	"\t\t\tres.###Multiplicity######propCapital###(float64(obj))\n" +
@@ -436,6 +395,18 @@ var PropBool = "\t\tif obj, err := strconv.ParseBool(in); err == nil {\n" +
 	"\t\t\tres.###Multiplicity######propCapital###(obj)\n" +
 	"\t\t}\n"
 
+// PropValue template
+var PropValue = "\t\tif obj, err := rdf.NewTypedLiteral(pred.Object.Term.String(), \"###xsdType###\").Parse(); err == nil {\n" +
+	"\t\t\tif v, ok := obj.(###propType###); ok {\n" +
+	"\t\t\t\tres.###Multiplicity######propCapital###(v)\n" +
+	"\t\t\t}\n" +
+	"\t\t}\n"
+
+// PropLangString template
+var PropLangString = "\t\tif obj, ok := pred.Object.Term.(rdf.Literal); ok && obj.Lang() != \"\" {\n" +
+	"\t\t\tres.###Multiplicity######propCapital###(rdf.LangString{Text: obj.String(), Lang: obj.Lang()})\n" +
+	"\t\t}\n"
+
 // PropString template
 var PropString = "\t\tres.###Multiplicity######propCapital###()\n"
 
@@ -51,6 +51,9 @@ var ModelHeader = "package ###pkgName###\n\n" +
 	// "\t\"git-ce.rwth-aachen.de/acs/private/research/ensure/owl/owl.git/pkg/graph\"\n" +
 	"\t\"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf\"\n" +
 	"\t\"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/owl\"\n" +
+	"\t\"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/reasoner\"\n" +
+	"\t\"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/shacl\"\n" +
+	"\t\"encoding/json\"\n" +
 	"\t\"io\"\n" +
 	")\n\n"
 
@@ -58,9 +61,17 @@ var ModelHeader = "package ###pkgName###\n\n" +
 var ModelStruct = "// Model holds all objects\n" +
 	"type Model struct {\n" +
 	"\tmThing map[string]owl.Thing\n" +
+	"\toptions rdf.EncodeOptions\n" +
 	"###objectMaps###" +
 	"}\n\n"
 
+// ModelOptions template
+var ModelOptions = "// ModelOptions are options for creating a model from a graph\n" +
+	"type ModelOptions struct {\n" +
+	"\tReasoner *reasoner.Reasoner // materializes the entailments of the graph before the model is created\n" +
+	"\tShapes   *shacl.Shapes      // validates the graph (after reasoning) before the model is created\n" +
+	"}\n\n"
+
 // StructMap template
 var StructMap = "\tm###className### map[string]###className###\n"
 
@@ -82,6 +93,17 @@ var NewObjectMap = "\tmod.m###className### = make(map[string]###className###)\n"
 // NewImport template
 var NewImport = "\tmod.model###importName### = im###importName###.NewModel()\n"
 
+// ModelEncodeOptions template
+var ModelEncodeOptions = "// EncodeOptions returns the prefixes and base iri used by ToTTL and ToJSONLD\n" +
+	"func (mod *Model) EncodeOptions() (opt rdf.EncodeOptions) {\n" +
+	"\topt = mod.options\n" +
+	"\treturn\n" +
+	"}\n\n" +
+	"// SetEncodeOptions sets the prefixes and base iri used by ToTTL and ToJSONLD\n" +
+	"func (mod *Model) SetEncodeOptions(opt rdf.EncodeOptions) {\n" +
+	"\tmod.options = opt\n" +
+	"}\n\n"
+
 // ModelExists template
 var ModelExists = "// Exist checks for the existance of a resource by the given iri\n" +
 	"func (mod *Model) Exist(iri string) (ret bool) {\n" +
@@ -92,22 +114,24 @@ var ModelExists = "// Exist checks for the existance of a resource by the given
 // ModelNewFromTTL template
 var ModelNewFromTTL = "// NewModelFromTTL creates a new model from a ttl io reader\n" +
 	"func NewModelFromTTL(input io.Reader) (mod *Model, err error) {\n" +
-	"\ttriples, err := rdf.DecodeTTL(input)\n" +
+	"\tdec := rdf.NewDecoder(input)\n" +
+	"\tg, err := dec.DecodeGraph()\n" +
 	"\tif err != nil {\n" +
 	"\t\treturn\n" +
 	"\t}\n" +
-	"\tg, err := rdf.NewGraph(triples)\n" +
+	"\tmod, err = NewModelFromGraph(&g)\n" +
 	"\tif err != nil {\n" +
 	"\t\treturn\n" +
 	"\t}\n" +
-	"\tmod, err = NewModelFromGraph(g)\n" +
+	"\tmod.options = rdf.EncodeOptions{Prefixes: dec.Prefixes(), Base: dec.Base()}\n" +
 	"\treturn\n" +
 	"}\n\n"
 
 // ModelNewFromJSONLD template
-var ModelNewFromJSONLD = "// NewModelFromJSONLD creates a new model from a jsonld io reader\n" +
-	"func NewModelFromJSONLD(input io.Reader) (mod *Model, err error) {\n" +
-	"\ttriples, err := rdf.DecodeJSONLD(input)\n" +
+var ModelNewFromJSONLD = "// NewModelFromJSONLD creates a new model from a jsonld io reader (optional decode options\n" +
+	"// set the loader for remote contexts)\n" +
+	"func NewModelFromJSONLD(input io.Reader, opts ...rdf.DecodeOptions) (mod *Model, err error) {\n" +
+	"\ttriples, err := rdf.DecodeJSONLD(input, opts...)\n" +
 	"\tif err != nil {\n" +
 	"\t\treturn\n" +
 	"\t}\n" +
@@ -115,25 +139,58 @@ var ModelNewFromJSONLD = "// NewModelFromJSONLD creates a new model from a jsonl
 	"\tif err != nil {\n" +
 	"\t\treturn\n" +
 	"\t}\n" +
-	"\tmod, err = NewModelFromGraph(g)\n" +
+	"\tmod, err = NewModelFromGraph(&g)\n" +
+	"\treturn\n" +
+	"}\n\n"
+
+// ModelNewFromStore template
+var ModelNewFromStore = "// NewModelFromStore creates a new model from the triples of a store\n" +
+	"func NewModelFromStore(store rdf.Store) (mod *Model, err error) {\n" +
+	"\tg := rdf.Graph{}\n" +
+	"\tfor it := store.Match(nil, nil, nil); it.Next(); {\n" +
+	"\t\tg.AddTriple(it.Triple())\n" +
+	"\t}\n" +
+	"\tmod, err = NewModelFromGraph(&g)\n" +
 	"\treturn\n" +
 	"}\n\n"
 
 // ModelNewFromGraph template
-var ModelNewFromGraph = "// NewModelFromGraph creates a new model from a owl graph\n" +
-	"func NewModelFromGraph(g rdf.Graph) (mod *Model, err error) {\n" +
+var ModelNewFromGraph = "// NewModelFromGraph creates a new model from a owl graph; if a reasoner is set in the options,\n" +
+	"// the entailments are added to a copy of the graph; if shapes are set, an error is returned if\n" +
+	"// the graph violates them\n" +
+	"func NewModelFromGraph(g *rdf.Graph, opts ...ModelOptions) (mod *Model, err error) {\n" +
+	"\tfor _, opt := range opts {\n" +
+	"\t\tif opt.Reasoner != nil {\n" +
+	"\t\t\tinferred := g.Copy()\n" +
+	"\t\t\tg = &inferred\n" +
+	"\t\t\tif _, err = opt.Reasoner.Materialize(g); err != nil {\n" +
+	"\t\t\t\treturn\n" +
+	"\t\t\t}\n" +
+	"\t\t}\n" +
+	"\t\tif opt.Shapes != nil {\n" +
+	"\t\t\tif err = opt.Shapes.Validate(g).Err(); err != nil {\n" +
+	"\t\t\t\treturn\n" +
+	"\t\t\t}\n" +
+	"\t\t}\n" +
+	"\t}\n" +
 	"\tmod = NewModel()\n" +
-	"\tfor i := range g.Nodes {\n" +
-	"\t\tfor j := range g.Nodes[i].Edge {\n" +
-	"\t\t\tif g.Nodes[i].Edge[j].Pred.String() == \"http://www.w3.org/1999/02/22-rdf-syntax-ns#type\" {\n" +
-	"\t\t\t\tswitch g.Nodes[i].Edge[j].Object.Term.String() {\n" +
+	"\ttyp := rdf.NewIRI(\"http://www.w3.org/1999/02/22-rdf-syntax-ns#type\")\n" +
+	"\t// asserted types first, so that inferred super classes do not replace them\n" +
+	"\tfor _, inferred := range []bool{false, true} {\n" +
+	"\t\tfor it := g.Match(nil, typ, nil); it.Next(); {\n" +
+	"\t\t\tif it.Edge().Inferred != inferred {\n" +
+	"\t\t\t\tcontinue\n" +
+	"\t\t\t}\n" +
+	"\t\t\tswitch it.Edge().Object.Term.String() {\n" +
 	"###newObjects###" +
-	"\t\t\t\tdefault:\n" +
-	"\t\t\t\t}\n" +
+	"\t\t\tdefault:\n" +
 	"\t\t\t}\n" +
 	"\t\t}\n" +
 	"\t}\n" +
 	"\tfor i := range g.Nodes {\n" +
+	"\t\tif g.Nodes[i].Term.Type() == rdf.TermLiteral {\n" +
+	"\t\t\tcontinue\n" +
+	"\t\t}\n" +
 	"\t\tif res, ok := mod.mThing[g.Nodes[i].Term.String()]; ok {\n" +
 	"\t\t\tres.InitFromNode(g.Nodes[i])\n" +
 	"\t\t}\n" +
@@ -142,8 +199,8 @@ var ModelNewFromGraph = "// NewModelFromGraph creates a new model from a owl gra
 	"}\n\n"
 
 // NewObject template
-var NewObject = "\t\t\t\tcase \"###classIRI###\":\n" +
-	"\t\t\t\t\tmod.New###capImportName######className###(g.Nodes[i].Term.String())\n"
+var NewObject = "\t\t\tcase \"###classIRI###\":\n" +
+	"\t\t\t\tmod.New###capImportName######className###(it.Edge().Subject.Term.String())\n"
 
 // ModelToGraph template
 var ModelToGraph = "// ToGraph extracts an owl graph from an existing model\n" +
@@ -156,12 +213,35 @@ var ModelToGraph = "// ToGraph extracts an owl graph from an existing model\n" +
 	"\treturn\n" +
 	"}\n\n"
 
+// ModelToStore template
+var ModelToStore = "// ToStore replaces the triples of the store with the triples of the model in one transaction;\n" +
+	"// only the differences are written\n" +
+	"func (mod *Model) ToStore(store rdf.Store) (err error) {\n" +
+	"\told := rdf.Graph{}\n" +
+	"\tfor it := store.Match(nil, nil, nil); it.Next(); {\n" +
+	"\t\told.AddTriple(it.Triple())\n" +
+	"\t}\n" +
+	"\tadded, removed := rdf.Diff(&old, mod.ToGraph())\n" +
+	"\tif err = store.Begin(); err != nil {\n" +
+	"\t\treturn\n" +
+	"\t}\n" +
+	"\tif _, err = store.Remove(removed...); err == nil {\n" +
+	"\t\t_, err = store.Add(added...)\n" +
+	"\t}\n" +
+	"\tif err != nil {\n" +
+	"\t\tstore.Abort()\n" +
+	"\t\treturn\n" +
+	"\t}\n" +
+	"\terr = store.Commit()\n" +
+	"\treturn\n" +
+	"}\n\n"
+
 // ModelToTTL template
 var ModelToTTL = "// ToTTL writes a ttl file from an existing model\n" +
 	"func (mod* Model) ToTTL(output io.Writer) (err error) {\n" +
 	"\tg := mod.ToGraph()\n" +
 	"\tnewTriples := g.ToTriples()\n" +
-	"\terr = rdf.EncodeTTL(newTriples, output)\n" +
+	"\terr = rdf.EncodeTTLPretty(newTriples, output, mod.options)\n" +
 	// "\toutput.Close()\n" +
 	"\treturn\n" +
 	"}\n\n"
@@ -171,11 +251,44 @@ var ModelToJSONLD = "// ToJSONLD writes a jsonld file from an existing model\n"
 	"func (mod* Model) ToJSONLD(output io.Writer) (err error) {\n" +
 	"\tg := mod.ToGraph()\n" +
 	"\tnewTriples := g.ToTriples()\n" +
-	"\terr = rdf.EncodeJSONLD(newTriples, output)\n" +
+	"\terr = rdf.EncodeJSONLD(newTriples, output, mod.options)\n" +
 	// "\toutput.Close()\n" +
 	"\treturn\n" +
 	"}\n\n"
 
+// ModelJSONLDContext template
+var ModelJSONLDContext = "// jsonldContext is the jsonld context of the ontology\n" +
+	"var jsonldContext = ###jsonldContext###\n\n" +
+	"// JSONLDContext returns the jsonld context of the ontology\n" +
+	"func JSONLDContext() (context map[string]interface{}) {\n" +
+	"\tjson.Unmarshal([]byte(jsonldContext), &context)\n" +
+	"\treturn\n" +
+	"}\n\n"
+
+// ModelToJSONLDCompact template
+var ModelToJSONLDCompact = "// ToJSONLDCompact writes a jsonld file compacted with the context of the ontology\n" +
+	"func (mod* Model) ToJSONLDCompact(output io.Writer) (err error) {\n" +
+	"\tg := mod.ToGraph()\n" +
+	"\tnewTriples := g.ToTriples()\n" +
+	"\topt := mod.options\n" +
+	"\topt.Context = JSONLDContext()\n" +
+	"\terr = rdf.EncodeJSONLD(newTriples, output, opt)\n" +
+	"\treturn\n" +
+	"}\n\n"
+
+// ModelToJSONLDFramed template
+var ModelToJSONLDFramed = "// ToJSONLDFramed writes a jsonld file framed with the specified frame (the context of the\n" +
+	"// ontology is used if the frame has no context)\n" +
+	"func (mod* Model) ToJSONLDFramed(output io.Writer, frame map[string]interface{}) (err error) {\n" +
+	"\tg := mod.ToGraph()\n" +
+	"\tnewTriples := g.ToTriples()\n" +
+	"\topt := mod.options\n" +
+	"\topt.Context = JSONLDContext()\n" +
+	"\topt.Frame = frame\n" +
+	"\terr = rdf.EncodeJSONLD(newTriples, output, opt)\n" +
+	"\treturn\n" +
+	"}\n\n"
+
 // ModelDeleteObject template
 var ModelDeleteObject = "// DeleteObject deletes an object from the model along with its references\n" +
 	"func (mod *Model) DeleteObject(obj owl.Thing) (err error) {\n" +
@@ -183,7 +296,7 @@ var ModelDeleteObject = "// DeleteObject deletes an object from the model along
 	"\t\treturn\n" +
 	"\t}\n" +
 	"\tg := mod.ToGraph()\n" +
-	"\tn := g.Nodes[obj.IRI()]\n" +
+	"\tn := owl.GetObjectNode(g, obj)\n" +
 	"\tfor i := range n.InverseEdge {\n" +
 	"\t\tif subj, ok := mod.mThing[n.InverseEdge[i].Subject.Term.String()]; ok {\n" +
 	"\t\t\tsubj.RemoveObject(obj, n.InverseEdge[i].Pred.String())\n" +
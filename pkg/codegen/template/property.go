@@ -51,6 +51,7 @@ var PropertyHeader = "package ###pkgName###\n\n" +
 // PropertyStructCommon template
 var PropertyStructCommon = "type propCommon struct {\n" +
 	"\tiri string // resource iri\n" +
+	"\tterm rdf.Term // iri or blank node of the resource\n" +
 	"\ttyp string // type of resource\n" +
 	"\tmodel *Model // pointer to model\n" +
 	"}\n\n"
@@ -94,6 +95,11 @@ var PropertyIRI = "// IRI is the resource iri\n" +
 	"func (res *propCommon) IRI() (out string) {\n" +
 	"\tout = res.iri\n" +
 	"\treturn\n" +
+	"}\n\n" +
+	"// Term is the iri or blank node of the resource\n" +
+	"func (res *propCommon) Term() (out rdf.Term) {\n" +
+	"\tout = res.term\n" +
+	"\treturn\n" +
 	"}\n\n"
 
 // PropertyInitClass template
@@ -110,6 +116,13 @@ var PropertyInitLiteral = "// init initializes the property\n" +
 	"\treturn\n" +
 	"}\n\n"
 
+// PropertyInitTerm template
+var PropertyInitTerm = "// init initializes the property\n" +
+	"func (res *###propLongName###) init(in rdf.Term) {\n" +
+	"###PropInit###" +
+	"\treturn\n" +
+	"}\n\n"
+
 // PropInitClassBaseThing template
 var PropInitClassBaseThing = "\tif obj, ok := model.mThing[in]; ok {\n" +
 	"\t\tres.###Multiplicity######propCapital###(obj)\n" +
@@ -129,54 +142,9 @@ var PropInitClassImport = "\tif temp := model.###capImportName######propBaseType
 	"\t\t}\n" +
 	"\t}\n"
 
-// PropInitTime template
-var PropInitTime = "\tif obj, err := time.Parse(\"15:04:05Z07:00\", in); err == nil {\n" +
-	"\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t}\n"
-
-// PropInitDate template
-var PropInitDate = "\tif obj, err := time.Parse(\"2006-01-02Z07:00\", in); err == nil {\n" +
-	"\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t}\n"
-
-// PropInitDateTime template
-var PropInitDateTime = "\tif obj, err := time.Parse(time.RFC3339, in); err == nil {\n" +
-	"\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t}\n"
-
-// PropInitDateTimeStamp template
-var PropInitDateTimeStamp = "\tif obj, err := time.Parse(time.RFC3339, in); err == nil {\n" +
-	"\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t}\n"
-
-// PropInitGDay template
-var PropInitGDay = "\tif obj, err := time.Parse(\"---02\", in); err == nil {\n" +
-	"\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t}\n"
-
-// PropInitGMonth template
-var PropInitGMonth = "\tif obj, err := time.Parse(\"--01\", in); err == nil {\n" +
-	"\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t}\n"
-
-// PropInitGYear template
-var PropInitGYear = "\tif obj, err := time.Parse(\"2006\", in); err == nil {\n" +
-	"\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t}\n"
-
-// PropInitGYearMonth template
-var PropInitGYearMonth = "\tif obj, err := time.Parse(\"2006-01\", in); err == nil {\n" +
-	"\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t}\n"
-
-// PropInitDuration template
-var PropInitDuration = "\tif obj, err := owl.ParseXsdDuration(in); err == nil {\n" +
-	"\t\tres.###Multiplicity######propCapital###(obj)\n" +
-	"\t}\n"
-
 // PropInitFloat template
-var PropInitFloat = "\tif obj, err := strconv.ParseFloat(in, 32); err == nil {\n" +
-	"\t\tres.###Multiplicity######propCapital###(float64(obj))\n" +
+var PropInitFloat = "\tif obj, err := strconv.ParseFloat(in, 64); err == nil {\n" +
+	"\t\tres.###Multiplicity######propCapital###(obj)\n" +
 	"\t}\n"
 
 // PropInitInt template
@@ -189,6 +157,18 @@ var PropInitBool = "\tif obj, err := strconv.ParseBool(in); err == nil {\n" +
 	"\t\tres.###Multiplicity######propCapital###(obj)\n" +
 	"\t}\n"
 
+// PropInitValue template
+var PropInitValue = "\tif obj, err := rdf.NewTypedLiteral(in, \"###xsdType###\").Parse(); err == nil {\n" +
+	"\t\tif v, ok := obj.(###propType###); ok {\n" +
+	"\t\t\tres.###Multiplicity######propCapital###(v)\n" +
+	"\t\t}\n" +
+	"\t}\n"
+
+// PropInitLangString template
+var PropInitLangString = "\tif obj, ok := in.(rdf.Literal); ok && obj.Lang() != \"\" {\n" +
+	"\t\tres.###Multiplicity######propCapital###(rdf.LangString{Text: obj.String(), Lang: obj.Lang()})\n" +
+	"\t}\n"
+
 // PropInitString template
 var PropInitString = "\tres.###Multiplicity######propCapital###(in)\n"
 
@@ -326,7 +306,21 @@ var PropertyDelLiteral = "// Del###propCapital### deletes ###comment###\n" +
 	"\tfor i := range in {\n" +
 	"\t\tfor j := range res.###propName### {\n" +
 	"\t\t\tif in[i] == res.###propName###[j] {\n" +
-	"\t\t\t\tres.###propName### = append(res.###propName###[:j], res.###propName###[j:]...)\n" +
+	"\t\t\t\tres.###propName### = append(res.###propName###[:j], res.###propName###[j+1:]...)\n" +
+	"\t\t\t\tbreak\n" +
+	"\t\t\t}\n" +
+	"\t\t}\n" +
+	"\t}\n" +
+	"\treturn\n" +
+	"}\n\n"
+
+// PropertyDelValue template
+var PropertyDelValue = "// Del###propCapital### deletes ###comment###\n" +
+	"func (res *###propLongName###) Del###propCapital###(in ...###propBaseType###) (err error) {\n" +
+	"\tfor i := range in {\n" +
+	"\t\tfor j := range res.###propName### {\n" +
+	"\t\t\tif rdf.EqualValues(in[i], res.###propName###[j]) {\n" +
+	"\t\t\t\tres.###propName### = append(res.###propName###[:j], res.###propName###[j+1:]...)\n" +
 	"\t\t\t\tbreak\n" +
 	"\t\t\t}\n" +
 	"\t\t}\n" +
@@ -414,32 +408,8 @@ var GraphPropBool = "###indent###\towl.AddBoolPropertyToGraph(g, \"###propIRI###
 // GraphPropInterface template
 var GraphPropInterface = "###indent###\towl.AddInterfacePropertyToGraph(g, \"###propIRI###\", node, res.###propName######array###)\n"
 
-// GraphPropSTime template
-var GraphPropSTime = "###indent###\towl.AddTimePropertyToGraph(g, \"###propIRI###\", node, res.###propName######array###)\n"
-
-// GraphPropSDateTime template
-var GraphPropSDateTime = "###indent###\towl.AddDateTimePropertyToGraph(g, \"###propIRI###\", node, res.###propName######array###)\n"
-
-// GraphPropSDate template
-var GraphPropSDate = "###indent###\towl.AddDatePropertyToGraph(g, \"###propIRI###\", node, res.###propName######array###)\n"
-
-// GraphPropSDuration template
-var GraphPropSDuration = "###indent###\towl.AddDurationPropertyToGraph(g, \"###propIRI###\", node, res.###propName######array###)\n"
-
-// GraphPropSDateTimeStamp template
-var GraphPropSDateTimeStamp = "###indent###\towl.AddDateTimeStampPropertyToGraph(g, \"###propIRI###\", node, res.###propName######array###)\n"
-
-// GraphPropSGYear template
-var GraphPropSGYear = "###indent###\towl.AddGYearPropertyToGraph(g, \"###propIRI###\", node, res.###propName######array###)\n"
-
-// GraphPropSGDay template
-var GraphPropSGDay = "###indent###\towl.AddGDayPropertyToGraph(g, \"###propIRI###\", node, res.###propName######array###)\n"
-
-// GraphPropSGYearMonth template
-var GraphPropSGYearMonth = "###indent###\towl.AddGYearMonthPropertyToGraph(g, \"###propIRI###\", node, res.###propName######array###)\n"
-
-// GraphPropSGMonth template
-var GraphPropSGMonth = "###indent###\towl.AddGMonthPropertyToGraph(g, \"###propIRI###\", node, res.###propName######array###)\n"
+// GraphPropValue template
+var GraphPropValue = "###indent###\towl.AddLiteralPropertyToGraph(g, \"###propIRI###\", node, res.###propName######array###, \"###xsdType###\")\n"
 
 // GraphPropClass template
 var GraphPropClass = "###indent###\towl.AddClassPropertyToGraph(g, \"###propIRI###\", node, res.###propName######array###)\n"
@@ -479,9 +449,6 @@ var StringPropBool = "###indent###\tret += fmt.Sprintf(\"%v\", res.###propName##
 // StringPropInterface template
 var StringPropInterface = "###indent###\tret += fmt.Sprintf(\"%v\", res.###propName######array###) + \", \"\n"
 
-// StringPropTime template
-var StringPropTime = "###indent###\tret += res.###propName######array###.String() + \", \"\n"
-
 // StringPropClassSingle template
 var StringPropClassSingle = "\tif res.###propName### != nil {\n" +
 	"\t\tret += res.###propName###.IRI() + \", \"\n" +
@@ -0,0 +1,144 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package testsuite reads test manifests in the format of the W3C test suites for the tests of the
+// rdf, sparql and shacl packages.
+package testsuite
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// vocabularies of the test manifests
+const (
+	RDF = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	MF  = "http://www.w3.org/2001/sw/DataAccess/tests/test-manifest#"
+)
+
+// Entry is a test of a manifest
+type Entry struct {
+	Node   rdf.Term // node of the test in the manifest graph
+	Name   string   // name of the test
+	Type   string   // local name of the test type (e.g. TestTurtleEval)
+	Action rdf.Term // action of the test
+	Result rdf.Term // expected result of the test (nil: no result)
+}
+
+// ReadManifest decodes the manifest file with the base iri and returns its graph and the entries of
+// its entry list
+func ReadManifest(t *testing.T, file string, base string) (graph *rdf.Graph, entries []Entry) {
+	graph = DecodeTTL(t, file, base)
+	lists := graph.Objects(nil, rdf.NewIRI(MF+"entries"))
+	if len(lists) != 1 {
+		t.Fatalf("%s: %d entry lists", file, len(lists))
+	}
+	for list := lists[0].Term; list.String() != RDF+"nil"; {
+		node := Object(graph, list, RDF+"first")
+		entry := Entry{
+			Node:   node,
+			Name:   String(graph, node, MF+"name"),
+			Type:   String(graph, node, RDF+"type"),
+			Action: Object(graph, node, MF+"action"),
+			Result: Object(graph, node, MF+"result"),
+		}
+		entry.Type = entry.Type[strings.LastIndexAny(entry.Type, "#/")+1:]
+		entries = append(entries, entry)
+		list = Object(graph, list, RDF+"rest")
+	}
+	return
+}
+
+// DecodeTTLFile decodes the turtle file; relative iris are resolved against the base iri
+func DecodeTTLFile(t *testing.T, file string, base string) (trips []rdf.Triple, err error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := append([]byte("@base <"+base+"> .\n"), data...)
+	trips, err = rdf.DecodeTTL(bytes.NewReader(input))
+	return
+}
+
+// DecodeTTL decodes the turtle file with the base iri to a graph
+func DecodeTTL(t *testing.T, file string, base string) (graph *rdf.Graph) {
+	trips, err := DecodeTTLFile(t, file, base)
+	if err != nil {
+		t.Fatalf("%s: %v", file, err)
+	}
+	g, err := rdf.NewGraph(trips)
+	if err != nil {
+		t.Fatalf("%s: %v", file, err)
+	}
+	graph = &g
+	return
+}
+
+// Object returns the first object of the subject and predicate (nil: no object)
+func Object(graph *rdf.Graph, subj rdf.Term, pred string) (obj rdf.Term) {
+	if objs := graph.Objects(subj, rdf.NewIRI(pred)); len(objs) > 0 {
+		obj = objs[0].Term
+	}
+	return
+}
+
+// String returns the string of the first object of the subject and predicate (empty: no object)
+func String(graph *rdf.Graph, subj rdf.Term, pred string) (str string) {
+	if obj := Object(graph, subj, pred); obj != nil {
+		str = obj.String()
+	}
+	return
+}
+
+// File returns the path of an iri relative to the base iri (empty: no iri)
+func File(iri rdf.Term, base string) (file string) {
+	if iri != nil {
+		file = strings.TrimPrefix(iri.String(), base)
+	}
+	return
+}
@@ -57,33 +57,18 @@ func extractClasses(g *rdf.Graph) (classes map[string]*Class, err error) {
 	fmt.Println("\tExtract classes")
 	classes = make(map[string]*Class)
 	// detrmine all classes
-	for i := range g.Nodes {
-		for j := range g.Nodes[i].Edge {
-			if g.Nodes[i].Edge[j].Pred.String() ==
-				"http://www.w3.org/1999/02/22-rdf-syntax-ns#type" &&
-				g.Nodes[i].Edge[j].Object.Term.String() == "http://www.w3.org/2002/07/owl#Class" {
-				isDeprecated := false
-				for k := range g.Nodes[i].Edge {
-					if g.Nodes[i].Edge[k].Pred.String() ==
-						"http://www.w3.org/1999/02/22-rdf-syntax-ns#type" &&
-						g.Nodes[i].Edge[k].Object.Term.String() ==
-							"http://www.w3.org/2002/07/owl#DeprecatedClass" {
-						isDeprecated = true
-						break
-					}
-				}
-				if isDeprecated {
-					break
-				}
-				class := Class{
-					Node:    g.Nodes[i],
-					Name:    g.Nodes[i].Term.String(),
-					Comment: getComment(g.Nodes[i]),
-				}
-				classes[g.Nodes[i].Term.String()] = &class
-				break
-			}
+	typ := rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#type")
+	deprecated := rdf.NewIRI("http://www.w3.org/2002/07/owl#DeprecatedClass")
+	for _, node := range g.Subjects(typ, rdf.NewIRI("http://www.w3.org/2002/07/owl#Class")) {
+		if g.Match(node.Term, typ, deprecated).Next() {
+			continue
+		}
+		class := Class{
+			Node:    node,
+			Name:    node.Term.String(),
+			Comment: getComment(node),
 		}
+		classes[node.Term.String()] = &class
 	}
 	return
 }
@@ -63,13 +63,23 @@ func ExtractOntologyLink(link string) (on Ontology, err error) {
 	if err != nil {
 		return
 	}
-	on, err = ExtractOntology(resp.Body)
+	on, err = ExtractOntologyFormat(resp.Body, responseFormat(resp, link),
+		rdf.DecodeOptions{Base: responseURL(resp, link)})
 	resp.Body.Close()
 	return
 }
 
-// ExtractOntology extracts all classes, properties, individuals and imports
+// ExtractOntology extracts all classes, properties, individuals and imports from a ttl document
 func ExtractOntology(input io.Reader) (on Ontology, err error) {
+	on, err = ExtractOntologyFormat(input, rdf.FormatTurtle)
+	return
+}
+
+// ExtractOntologyFormat extracts all classes, properties, individuals and imports from a document
+// in the specified format (see rdf.Format*); optional decode options set the base iri (e.g. the
+// url of the document) against which relative iris are resolved
+func ExtractOntologyFormat(input io.Reader, format string, opts ...rdf.DecodeOptions) (on Ontology,
+	err error) {
 	fmt.Println("Extract ontology")
 	iri := ""
 	description := ""
@@ -81,7 +91,7 @@ func ExtractOntology(input io.Reader) (on Ontology, err error) {
 	on.Content = make(map[string][]byte)
 
 	var g rdf.Graph
-	g, iri, description, _, err = parseOntology(input)
+	g, iri, description, _, err = parseOntology(input, format, opts...)
 	if err != nil {
 		return
 	}
@@ -128,37 +138,36 @@ func ExtractOntology(input io.Reader) (on Ontology, err error) {
 }
 
 // parseOntology parses the specified ontology
-func parseOntology(input io.Reader) (g rdf.Graph, iri string, description string, content []byte,
-	err error) {
-	fmt.Println("\tParse ttl file")
-	g, err = readTTL(input)
+func parseOntology(input io.Reader, format string, opts ...rdf.DecodeOptions) (g rdf.Graph,
+	iri string, description string, content []byte, err error) {
+	fmt.Println("\tParse " + format + " document")
+	g, err = readGraph(input, format, opts...)
 	if err != nil {
 		err = errors.New("cannot parse ontology: " + err.Error())
 		return
 	}
 
 	// get ontology iri
-	isOnt := false
-	for i := range g.Edges {
-		if g.Edges[i].Pred.String() == "http://www.w3.org/1999/02/22-rdf-syntax-ns#type" &&
-			g.Edges[i].Object.Term.String() == "http://www.w3.org/2002/07/owl#Ontology" {
-			iri = g.Edges[i].Subject.Term.String()
-			fmt.Println("\t\tFound ontology " + iri)
-			isOnt = true
-		} else if g.Edges[i].Pred.String() == "http://purl.org/dc/terms/description" {
-			description = g.Edges[i].Object.Term.String()
-		}
-	}
-	if !isOnt {
+	ontologies := g.Subjects(rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#type"),
+		rdf.NewIRI("http://www.w3.org/2002/07/owl#Ontology"))
+	if len(ontologies) == 0 {
 		err = errors.New("no ontology")
+		return
+	}
+	ont := ontologies[len(ontologies)-1]
+	iri = ont.Term.String()
+	fmt.Println("\t\tFound ontology " + iri)
+	for _, desc := range g.Objects(ont.Term, rdf.NewIRI("http://purl.org/dc/terms/description")) {
+		description = desc.Term.String()
 	}
 	return
 }
 
-// readTTL reads a ttl file and returns a graph
-func readTTL(input io.Reader) (g rdf.Graph, err error) {
+// readGraph reads a document in the specified format and returns a graph
+func readGraph(input io.Reader, format string, opts ...rdf.DecodeOptions) (g rdf.Graph,
+	err error) {
 	var triples []rdf.Triple
-	triples, err = rdf.DecodeTTL(input)
+	triples, err = rdf.Decode(input, format, opts...)
 	if err != nil {
 		return
 	}
@@ -173,36 +182,35 @@ func (on *Ontology) parseImports(gIn *rdf.Graph) (err error) {
 	var gTemp rdf.Graph
 	gTemp.Nodes = make(map[string]*rdf.Node)
 	hasImport := false
-	for i := range gIn.Edges {
-		if gIn.Edges[i].Pred.String() == "http://www.w3.org/2002/07/owl#imports" {
-			iri := gIn.Edges[i].Subject.Term.String()
-			impIRI := gIn.Edges[i].Object.Term.String()
-			if _, ok := on.Imports[impIRI]; ok {
-				continue
-			}
-			hasImport = true
+	for it := gIn.Match(nil, rdf.NewIRI("http://www.w3.org/2002/07/owl#imports"), nil); it.Next(); {
+		iri := it.Edge().Subject.Term.String()
+		impIRI := it.Edge().Object.Term.String()
+		if _, ok := on.Imports[impIRI]; ok {
+			continue
+		}
+		hasImport = true
 
-			on.Imports[iri] = append(on.Imports[iri], impIRI)
+		on.Imports[iri] = append(on.Imports[iri], impIRI)
 
-			var resp *http.Response
-			resp, err = requestOntology(gIn.Edges[i].Object.Term.String())
-			if err != nil {
-				return
-			}
-			fmt.Println("\t\tFound import " + gIn.Edges[i].Object.Term.String())
-			var g rdf.Graph
-			var desc string
-			g, impIRI, desc, _, err = parseOntology(resp.Body)
-			if err != nil {
-				return
-			}
-			resp.Body.Close()
+		var resp *http.Response
+		resp, err = requestOntology(impIRI)
+		if err != nil {
+			return
+		}
+		fmt.Println("\t\tFound import " + impIRI)
+		var g rdf.Graph
+		var desc string
+		g, impIRI, desc, _, err = parseOntology(resp.Body, responseFormat(resp, impIRI),
+			rdf.DecodeOptions{Base: responseURL(resp, impIRI)})
+		if err != nil {
+			return
+		}
+		resp.Body.Close()
 
-			on.Description[impIRI] = desc
-			on.Imports[impIRI] = []string{}
+		on.Description[impIRI] = desc
+		on.Imports[impIRI] = []string{}
 
-			gTemp.Merge(&g)
-		}
+		gTemp.Merge(&g)
 	}
 	if hasImport {
 		err = on.parseImports(&gTemp)
@@ -257,12 +265,19 @@ func requestOntology(path string) (resp *http.Response, err error) {
 
 	var request *http.Request
 	request, err = http.NewRequest("GET", path, nil)
-	request.Header.Set("Content-Type", "text/turtle")
+	if err != nil {
+		return
+	}
+	request.Header.Set("Accept", acceptHeader)
 	resp, err = client.Do(request)
+	if err != nil {
+		return
+	}
 
 	if cont, ok := resp.Header["Content-Type"]; ok {
 		for i := range cont {
 			if strings.HasPrefix(cont[i], "text/html") {
+				resp.Body.Close()
 				resp, err = requestOntologyTTL(path)
 				break
 			}
@@ -280,8 +295,41 @@ func requestOntologyTTL(path string) (resp *http.Response, err error) {
 
 	var request *http.Request
 	request, err = http.NewRequest("GET", path+".ttl", nil)
-	request.Header.Set("Content-Type", "text/turtle")
+	if err != nil {
+		return
+	}
+	request.Header.Set("Accept", acceptHeader)
 	resp, err = client.Do(request)
 
 	return
 }
+
+// acceptHeader lists the rdf formats that can be used for ontologies
+const acceptHeader = "text/turtle, application/rdf+xml;q=0.9, application/n-triples;q=0.8, " +
+	"application/ld+json;q=0.7"
+
+// responseFormat determines the format of a http response from its content type or the extension
+// of the requested url (default: ttl)
+func responseFormat(resp *http.Response, link string) (format string) {
+	format = rdf.FormatFromMediaType(resp.Header.Get("Content-Type"))
+	if format == "" && resp.Request != nil && resp.Request.URL != nil {
+		format = rdf.FormatFromExtension(resp.Request.URL.Path)
+	}
+	if format == "" {
+		format = rdf.FormatFromExtension(link)
+	}
+	if format == "" {
+		format = rdf.FormatTurtle
+	}
+	return
+}
+
+// responseURL returns the url of the document of a http response after redirects (default: the
+// requested link)
+func responseURL(resp *http.Response, link string) (loc string) {
+	loc = link
+	if resp.Request != nil && resp.Request.URL != nil {
+		loc = resp.Request.URL.String()
+	}
+	return
+}
@@ -47,52 +47,32 @@ THE SOFTWARE.
 package owl
 
 import (
-	"fmt"
-	"strconv"
-	"strings"
+	"math/big"
+	"net/url"
 	"time"
 
 	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
-	"github.com/piprate/json-gold/ld"
 )
 
 // AddObjectToGraph adds the specified object to the graph
 func AddObjectToGraph(g *rdf.Graph, typeIRI string, res Thing) (node *rdf.Node) {
-	var ok bool
-	if node, ok = g.Nodes[res.IRI()]; !ok {
-		if isIRI(res.IRI()) {
-			node = &rdf.Node{Term: rdf.NewIRI(res.IRI())}
-		} else {
-			node = &rdf.Node{Term: rdf.NewBlankNode(res.IRI())}
-		}
-		g.Nodes[res.IRI()] = node
-	}
-	var typ *rdf.Node
-	if typ, ok = g.Nodes[typeIRI]; !ok {
-		typ = &rdf.Node{Term: rdf.NewIRI(typeIRI)}
-		g.Nodes[typeIRI] = typ
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#type"),
-		Subject: node,
-		Object:  typ,
-	}
-	g.Edges = append(g.Edges, pred)
-	node.Edge = append(node.Edge, pred)
-	typ.InverseEdge = append(typ.InverseEdge, pred)
-	var nInd *rdf.Node
-	if nInd, ok = g.Nodes["http://www.w3.org/2002/07/owl#NamedIndividual"]; !ok {
-		nInd = &rdf.Node{Term: rdf.NewIRI("http://www.w3.org/2002/07/owl#NamedIndividual")}
-		g.Nodes["http://www.w3.org/2002/07/owl#NamedIndividual"] = nInd
-	}
-	pred = &rdf.Edge{
-		Pred:    rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#type"),
-		Subject: node,
-		Object:  nInd,
-	}
-	g.Edges = append(g.Edges, pred)
-	node.Edge = append(node.Edge, pred)
-	nInd.InverseEdge = append(nInd.InverseEdge, pred)
+	node = g.AddNode(thingTerm(res))
+	g.AddTriple(rdf.Triple{
+		Sub:  node.Term,
+		Pred: rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#type"),
+		Obj:  rdf.NewIRI(typeIRI),
+	})
+	g.AddTriple(rdf.Triple{
+		Sub:  node.Term,
+		Pred: rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#type"),
+		Obj:  rdf.NewIRI("http://www.w3.org/2002/07/owl#NamedIndividual"),
+	})
+	return
+}
+
+// GetObjectNode returns the node of the specified object in the graph (nil if it is not contained)
+func GetObjectNode(g *rdf.Graph, res Thing) (node *rdf.Node) {
+	node = g.Node(thingTerm(res))
 	return
 }
 
@@ -101,61 +81,21 @@ func AddClassPropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node, o
 	if obj == nil {
 		return
 	}
-	objNode, ok := g.Nodes[obj.IRI()]
-	if !ok {
-		if isIRI(obj.IRI()) {
-			objNode = &rdf.Node{Term: rdf.NewIRI(obj.IRI())}
-		} else {
-			objNode = &rdf.Node{Term: rdf.NewBlankNode(obj.IRI())}
-		}
-		g.Nodes[obj.IRI()] = objNode
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
-	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, thingTerm(obj))
 	return
 }
 
 // AddIntPropertyToGraph adds the specified property to the graph
 func AddIntPropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node, obj int) {
 	lit, _ := rdf.NewLiteral(obj, "")
-	objNode, ok := g.Nodes[fmt.Sprintf("%v", obj)]
-	if !ok {
-		objNode = &rdf.Node{Term: lit}
-		g.Nodes[fmt.Sprintf("%v", obj)] = objNode
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
-	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, lit)
 	return
 }
 
 // AddFloatPropertyToGraph adds the specified property to the graph
 func AddFloatPropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node, obj float64) {
 	lit, _ := rdf.NewLiteral(obj, "")
-	objNode, ok := g.Nodes[fmt.Sprintf("%v", obj)]
-	if !ok {
-		objNode = &rdf.Node{Term: lit}
-		g.Nodes[fmt.Sprintf("%v", obj)] = objNode
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
-	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, lit)
 	return
 }
 
@@ -165,56 +105,51 @@ func AddStringPropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node,
 		return
 	}
 	lit, _ := rdf.NewLiteral(obj, "")
-	objNode, ok := g.Nodes[obj]
-	if !ok {
-		objNode = &rdf.Node{Term: lit}
-		g.Nodes[obj] = objNode
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
-	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, lit)
 	return
 }
 
 // AddBoolPropertyToGraph adds the specified property to the graph
 func AddBoolPropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node, obj bool) {
 	lit, _ := rdf.NewLiteral(obj, "")
-	objNode, ok := g.Nodes[fmt.Sprintf("%v", obj)]
-	if !ok {
-		objNode = &rdf.Node{Term: lit}
-		g.Nodes[fmt.Sprintf("%v", obj)] = objNode
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
-	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, lit)
 }
 
 // AddInterfacePropertyToGraph adds the specified property to the graph
 func AddInterfacePropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node, obj interface{}) {
 	lit, _ := rdf.NewLiteral(obj, "")
-	objNode, ok := g.Nodes[fmt.Sprintf("%v", obj)]
-	if !ok {
-		objNode = &rdf.Node{Term: lit}
-		g.Nodes[fmt.Sprintf("%v", obj)] = objNode
+	addPropertyToGraph(g, propIRI, subjNode, lit)
+}
+
+// AddLiteralPropertyToGraph adds the specified property with a literal of the given datatype to the
+// graph; unset values (nil pointers and slices, zero dates and durations) and values not matching
+// the datatype are skipped
+func AddLiteralPropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node, obj interface{},
+	datatype string) {
+	switch t := obj.(type) {
+	case *big.Int:
+		if t == nil {
+			return
+		}
+	case *url.URL:
+		if t == nil {
+			return
+		}
+	case []byte:
+		if t == nil {
+			return
+		}
+	case interface{ IsZero() bool }:
+		if t.IsZero() {
+			return
+		}
 	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
+	lit, err := rdf.NewLiteral(obj, datatype)
+	if err != nil {
+		return
 	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, lit)
+	return
 }
 
 // AddDurationPropertyToGraph adds the specified property to the graph
@@ -224,19 +159,7 @@ func AddDurationPropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node
 		return
 	}
 	lit, _ := rdf.NewLiteral(obj, "")
-	var objNode *rdf.Node
-	if objNode, ok := g.Nodes[lit.String()]; !ok {
-		objNode = &rdf.Node{Term: lit}
-		g.Nodes[lit.String()] = objNode
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
-	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, lit)
 }
 
 // AddTimePropertyToGraph adds the specified property to the graph
@@ -246,19 +169,7 @@ func AddTimePropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node, ob
 		return
 	}
 	lit, _ := rdf.NewLiteral(obj, rdf.XsdTime)
-	objNode, ok := g.Nodes[lit.String()]
-	if !ok {
-		objNode = &rdf.Node{Term: lit}
-		g.Nodes[lit.String()] = objNode
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
-	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, lit)
 }
 
 // AddDateTimePropertyToGraph adds the specified property to the graph
@@ -268,19 +179,7 @@ func AddDateTimePropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node
 		return
 	}
 	lit, _ := rdf.NewLiteral(obj, rdf.XsdDateTime)
-	objNode, ok := g.Nodes[lit.String()]
-	if !ok {
-		objNode = &rdf.Node{Term: lit}
-		g.Nodes[lit.String()] = objNode
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
-	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, lit)
 }
 
 // AddDatePropertyToGraph adds the specified property to the graph
@@ -290,19 +189,7 @@ func AddDatePropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node, ob
 		return
 	}
 	lit, _ := rdf.NewLiteral(obj, rdf.XsdDate)
-	objNode, ok := g.Nodes[lit.String()]
-	if !ok {
-		objNode = &rdf.Node{Term: lit}
-		g.Nodes[lit.String()] = objNode
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
-	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, lit)
 }
 
 // AddDateTimeStampPropertyToGraph adds the specified property to the graph
@@ -312,19 +199,7 @@ func AddDateTimeStampPropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf
 		return
 	}
 	lit, _ := rdf.NewLiteral(obj, rdf.XsdDateTimeStamp)
-	objNode, ok := g.Nodes[lit.String()]
-	if !ok {
-		objNode = &rdf.Node{Term: lit}
-		g.Nodes[lit.String()] = objNode
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
-	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, lit)
 }
 
 // AddGYearPropertyToGraph adds the specified property to the graph
@@ -334,19 +209,7 @@ func AddGYearPropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node, o
 		return
 	}
 	lit, _ := rdf.NewLiteral(obj, rdf.XsdYear)
-	objNode, ok := g.Nodes[lit.String()]
-	if !ok {
-		objNode = &rdf.Node{Term: lit}
-		g.Nodes[lit.String()] = objNode
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
-	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, lit)
 }
 
 // AddGDayPropertyToGraph adds the specified property to the graph
@@ -356,19 +219,7 @@ func AddGDayPropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node, ob
 		return
 	}
 	lit, _ := rdf.NewLiteral(obj, rdf.XsdDay)
-	objNode, ok := g.Nodes[lit.String()]
-	if !ok {
-		objNode = &rdf.Node{Term: lit}
-		g.Nodes[lit.String()] = objNode
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
-	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, lit)
 }
 
 // AddGYearMonthPropertyToGraph adds the specified property to the graph
@@ -378,19 +229,7 @@ func AddGYearMonthPropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.No
 		return
 	}
 	lit, _ := rdf.NewLiteral(obj, rdf.XsdYearMonth)
-	objNode, ok := g.Nodes[lit.String()]
-	if !ok {
-		objNode = &rdf.Node{Term: lit}
-		g.Nodes[lit.String()] = objNode
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
-	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, lit)
 }
 
 // AddGMonthPropertyToGraph adds the specified property to the graph
@@ -400,52 +239,30 @@ func AddGMonthPropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node,
 		return
 	}
 	lit, _ := rdf.NewLiteral(obj, rdf.XsdMonth)
-	objNode, ok := g.Nodes[lit.String()]
-	if !ok {
-		objNode = &rdf.Node{Term: lit}
-		g.Nodes[lit.String()] = objNode
-	}
-	pred := &rdf.Edge{
-		Pred:    rdf.NewIRI(propIRI),
-		Object:  objNode,
-		Subject: subjNode,
-	}
-	subjNode.Edge = append(subjNode.Edge, pred)
-	objNode.InverseEdge = append(objNode.InverseEdge, pred)
-	g.Edges = append(g.Edges, pred)
+	addPropertyToGraph(g, propIRI, subjNode, lit)
 }
 
-// ParseXsdDuration parses xsdDuration
-func ParseXsdDuration(in string) (out time.Duration, err error) {
-	p := strings.TrimPrefix(in, "P")
-	str := ""
-	h := strings.Split(p, "H")
-	if len(h) > 1 {
-		if n, err := strconv.Atoi(h[0]); err == nil && n > 0 {
-			str += h[0] + "h"
-		}
-	}
-	m := strings.Split(h[len(h)-1], "M")
-	if len(m) > 1 {
-		if n, err := strconv.Atoi(m[0]); err == nil && n > 0 {
-			str += m[0] + "m"
-		}
-	}
-	s := strings.Split(m[len(m)-1], "S")
-	if len(s) > 1 {
-		if n, err := strconv.ParseFloat(s[0], 32); err == nil && n > 0 {
-			str += s[0] + "s"
-		}
+// addPropertyToGraph adds an edge from the subject node to the object to the graph; identical
+// edges are added once
+func addPropertyToGraph(g *rdf.Graph, propIRI string, subjNode *rdf.Node, obj rdf.Term) {
+	g.AddTriple(rdf.Triple{Sub: subjNode.Term, Pred: rdf.NewIRI(propIRI), Obj: obj})
+}
+
+// thingTerm returns the iri or blank node of an object
+func thingTerm(res Thing) (term rdf.Term) {
+	term = res.Term()
+	if term == nil {
+		term = rdf.NewIRI(res.IRI())
 	}
-	out, err = time.ParseDuration(str)
 	return
 }
 
-// isIRI checks if string is valid iri
-func isIRI(iri string) (ok bool) {
-	ok = false
-	if ld.IsURL(iri) {
-		ok = true
+// ParseXsdDuration parses xsdDuration; durations with years or months cannot be parsed
+func ParseXsdDuration(in string) (out time.Duration, err error) {
+	d, err := rdf.ParseDuration(in)
+	if err != nil {
+		return
 	}
+	out, err = d.ToDuration()
 	return
 }
@@ -56,19 +56,22 @@ func extractIndividuals(g *rdf.Graph,
 	classes map[string]*Class) (individuals map[string]*Individual, err error) {
 	fmt.Println("\tExtract individuals")
 	individuals = make(map[string]*Individual)
-	// detrmine all individuals
-	for i := range g.Nodes {
-		for j := range g.Nodes[i].Edge {
-			if g.Nodes[i].Edge[j].Pred.String() ==
-				"http://www.w3.org/1999/02/22-rdf-syntax-ns#type" {
-				if class, ok := classes[g.Nodes[i].Edge[j].Object.Term.String()]; ok {
+	// detrmine all individuals (the first type that is a class is used)
+	typ := rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#type")
+	for i := range classes {
+		for _, node := range g.Subjects(typ, classes[i].Node.Term) {
+			if _, ok := individuals[node.Term.String()]; ok {
+				continue
+			}
+			for it := g.Match(node.Term, typ, nil); it.Next(); {
+				if class, ok := classes[it.Edge().Object.Term.String()]; ok {
 					ind := Individual{
-						Node:    g.Nodes[i],
-						Name:    g.Nodes[i].Term.String(),
-						Comment: getComment(g.Nodes[i]),
+						Node:    node,
+						Name:    node.Term.String(),
+						Comment: getComment(node),
 						Type:    class,
 					}
-					individuals[g.Nodes[i].Term.String()] = &ind
+					individuals[node.Term.String()] = &ind
 					break
 				}
 			}
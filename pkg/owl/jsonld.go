@@ -0,0 +1,134 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package owl
+
+import (
+	"sort"
+	"strings"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// contextTerm is a term of a jsonld context
+type contextTerm struct {
+	short  string      // short name (local name of iri)
+	long   string      // unique name (Go name) used if short name is ambiguous
+	iri    string      // iri of term
+	define interface{} // term definition
+}
+
+// JSONLDContext creates a jsonld context with short names for all classes and properties of the
+// model; object properties are coerced to @id and literal properties to their xsd type
+func (mod *GoModel) JSONLDContext() (context map[string]interface{}) {
+	var terms []contextTerm
+	defined := make(map[string]bool)
+	className := make([]string, 0, len(mod.Class))
+	for i := range mod.Class {
+		className = append(className, i)
+	}
+	sort.Strings(className)
+	for _, i := range className {
+		class := mod.Class[i]
+		if !defined[class.IRI] {
+			defined[class.IRI] = true
+			terms = append(terms, contextTerm{short: localName(class.IRI), long: class.Name,
+				iri: class.IRI, define: class.IRI})
+		}
+		for j := range class.Property {
+			prop := class.Property[j]
+			if defined[prop.IRI] {
+				continue
+			}
+			defined[prop.IRI] = true
+			def := map[string]interface{}{"@id": prop.IRI}
+			if typ := prop.jsonldType(); typ != "" {
+				def["@type"] = typ
+			}
+			if prop.Multi {
+				def["@container"] = "@set"
+			}
+			terms = append(terms, contextTerm{short: localName(prop.IRI), long: prop.Name,
+				iri: prop.IRI, define: def})
+		}
+	}
+
+	// short names must be unique
+	count := make(map[string]int)
+	for i := range terms {
+		count[terms[i].short]++
+	}
+	context = map[string]interface{}{"xsd": "http://www.w3.org/2001/XMLSchema#"}
+	for i := range terms {
+		name := terms[i].short
+		if count[name] > 1 || name == "" {
+			name = terms[i].long
+		}
+		context[name] = terms[i].define
+	}
+	return
+}
+
+// jsonldType returns the type coercion of a property (@id or xsd type; empty for strings and
+// language-tagged strings)
+func (prop *GoProperty) jsonldType() (typ string) {
+	switch prop.Typ[0] {
+	case "string", "interface{}", "rdf.LangString":
+	case "int":
+		typ = rdf.XsdInteger
+	default:
+		if !IsLiteralType(prop.Typ[0]) {
+			typ = "@id"
+		} else if prop.XSDTyp != "" {
+			typ = prop.XSDTyp
+		}
+	}
+	return
+}
+
+// localName returns the part of an iri after the last '#' or '/'
+func localName(iri string) (name string) {
+	name = iri[strings.LastIndexAny(iri, "#/")+1:]
+	return
+}
@@ -50,6 +50,8 @@ import (
 	"strconv"
 	"strings"
 	"unicode"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
 )
 
 // GoModel holds all classes
@@ -214,13 +216,10 @@ func (mod *GoModel) extractClass(class *Class, ont *Ontology, ontName string) (g
 					property.Typ = property.BaseTyp
 				}
 
-				//if property.Typ == "string" || property.Typ == "int" || property.Typ == "float64" ||
-				if property.Typ[0] == "time.Time" || property.Typ[0] == "time.Duration" {
-					if len(rest[j].Value) > 0 {
-						property.XSDTyp = rest[j].Value[0]
-					} else if len(restInv[i].Value) > 0 {
-						property.XSDTyp = restInv[i].Value[0]
-					} else {
+				if IsLiteralType(property.Typ[0]) && property.Typ[0] != "string" &&
+					property.Typ[0] != "interface{}" {
+					property.XSDTyp = getRestrictionXSDType(property.Typ[0], rest[j], restInv[i])
+					if property.XSDTyp == "" {
 						err = errors.New("Class " + class.Name + " Restriction " +
 							restInv[i].Property.Name + " no xsd type")
 						return
@@ -239,11 +238,7 @@ func (mod *GoModel) extractClass(class *Class, ont *Ontology, ontName string) (g
 		}
 		b, _ := GetBaseClass([]string{property.BaseTyp[1], property.Typ[1]}, ont.Class)
 		if property.BaseTyp[0] != property.Typ[0] && b == nil {
-			if property.Typ[0] == "string" || property.Typ[0] == "time.Time" ||
-				property.Typ[0] == "time.Duration" || property.Typ[0] == "int" ||
-				property.Typ[0] == "float64" || property.Typ[0] == "bool" {
-
-			} else {
+			if !IsLiteralType(property.Typ[0]) {
 				// WARNING: This removes properties that are present in specification (relevant for saref4ener:PowerProfile:consists of; saref4ener:AlternativesGroup does not inherit from saref:Profile)
 				continue
 			}
@@ -308,7 +303,7 @@ func getRestrictionAllowedTypes(rest *Restriction, ont *Ontology) (values [][2]s
 			if _, ok := ont.Class[rest.Value[i]]; ok {
 				allowedType := trimName(rest.Value[i], ont)
 				values = append(values, [2]string{allowedType, rest.Value[i]})
-			} else if strings.HasPrefix(rest.Value[i], "http://www.w3.org/2001/XMLSchema") {
+			} else if isDatatype(rest.Value[i]) {
 				allowedType, err := mapLiteralType(rest.Value[i])
 				if err == nil {
 					values = append(values, [2]string{allowedType, ""})
@@ -340,7 +335,7 @@ func getRestrictionType(rest *Restriction, ont *Ontology) (ret [2]string, typeEx
 		for i := range rest.Value {
 			if temp := trimName(rest.Value[i], ont); temp != "" {
 				isClass = true
-			} else if strings.HasPrefix(rest.Value[i], "http://www.w3.org/2001/XMLSchema") {
+			} else if isDatatype(rest.Value[i]) {
 				isLiteral = true
 			}
 		}
@@ -404,43 +399,51 @@ func (mod *GoModel) extractIndividual(individual *Individual,
 	return
 }
 
+// isDatatype checks if the iri is a xsd datatype or another registered datatype (e.g.
+// rdf:langString)
+func isDatatype(iri string) (ok bool) {
+	_, ok = rdf.LookupDatatype(iri)
+	ok = ok || strings.HasPrefix(iri, "http://www.w3.org/2001/XMLSchema")
+	return
+}
+
 // mapLiteralType maps the literal type to a go datatype
 func mapLiteralType(literal string) (goType string, err error) {
-	switch literal {
-	case "http://www.w3.org/2001/XMLSchema#time":
-		goType = "time.Time"
-	case "http://www.w3.org/2001/XMLSchema#dateTime":
-		goType = "time.Time"
-	case "http://www.w3.org/2001/XMLSchema#date":
-		goType = "time.Time"
-	case "http://www.w3.org/2001/XMLSchema#duration":
-		goType = "time.Duration"
-	case "http://www.w3.org/2001/XMLSchema#dateTimeStamp":
-		goType = "time.Time"
-	case "http://www.w3.org/2001/XMLSchema#gYear":
-		goType = "time.Time"
-	case "http://www.w3.org/2001/XMLSchema#gDay":
-		goType = "time.Time"
-	case "http://www.w3.org/2001/XMLSchema#gYearMonth":
-		goType = "time.Time"
-	case "http://www.w3.org/2001/XMLSchema#gMonth":
-		goType = "time.Time"
-	case "http://www.w3.org/2001/XMLSchema#string":
-		goType = "string"
-	case "http://www.w3.org/2001/XMLSchema#float":
-		goType = "float64"
-	case "http://www.w3.org/2001/XMLSchema#decimal":
-		goType = "float64"
-	case "http://www.w3.org/2001/XMLSchema#integer":
-		goType = "int"
-	case "http://www.w3.org/2001/XMLSchema#nonNegativeInteger":
-		goType = "int"
-	case "http://www.w3.org/2001/XMLSchema#unsignedInt":
-		goType = "int"
-	case "http://www.w3.org/2001/XMLSchema#boolean":
-		goType = "bool"
-	default:
+	dt, ok := rdf.LookupDatatype(literal)
+	if !ok {
 		err = errors.New("Unknown literal")
+		return
+	}
+	goType = dt.GoType
+	return
+}
+
+// IsLiteralType returns true if goType is the Go type of a literal (xsd datatype) property
+func IsLiteralType(goType string) (ok bool) {
+	if goType == "int" || goType == "interface{}" || goType == "time.Duration" {
+		ok = true
+		return
+	}
+	dts := rdf.Datatypes()
+	for i := range dts {
+		if dts[i].GoType == goType {
+			ok = true
+			return
+		}
+	}
+	return
+}
+
+// getRestrictionXSDType returns the xsd datatype of the first restriction whose value maps to goType
+func getRestrictionXSDType(goType string, rest ...*Restriction) (xsdType string) {
+	for i := range rest {
+		if len(rest[i].Value) == 0 {
+			continue
+		}
+		if typ, err := mapLiteralType(rest[i].Value[0]); err == nil && typ == goType {
+			xsdType = rest[i].Value[0]
+			return
+		}
 	}
 	return
 }
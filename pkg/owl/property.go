@@ -57,29 +57,26 @@ func extractProperties(g *rdf.Graph) (properties map[string]*Property, err error
 	fmt.Println("\tExtract properties")
 	properties = make(map[string]*Property)
 	// detrmine all properties
-	for i := range g.Nodes {
-		for j := range g.Nodes[i].Edge {
-			if g.Nodes[i].Edge[j].Pred.String() ==
-				"http://www.w3.org/1999/02/22-rdf-syntax-ns#type" &&
-				(g.Nodes[i].Edge[j].Object.Term.String() ==
-					"http://www.w3.org/2002/07/owl#ObjectProperty" ||
-					g.Nodes[i].Edge[j].Object.Term.String() ==
-						"http://www.w3.org/2002/07/owl#DatatypeProperty") {
-				prop := Property{
-					Node:    g.Nodes[i],
-					Name:    g.Nodes[i].Term.String(),
-					Comment: getComment(g.Nodes[i]),
-					Range:   getRange(g.Nodes[i]),
-					Type:    g.Nodes[i].Edge[j].Object.Term.String(),
-				}
-				err = prop.extractPropertyCharacteristics()
-				if err != nil {
-					err = errors.New(err.Error() + " property " + prop.Name)
-					return
-				}
-				properties[g.Nodes[i].Term.String()] = &prop
-				break
+	typ := rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#type")
+	for _, propType := range []string{"http://www.w3.org/2002/07/owl#ObjectProperty",
+		"http://www.w3.org/2002/07/owl#DatatypeProperty"} {
+		for _, node := range g.Subjects(typ, rdf.NewIRI(propType)) {
+			if _, ok := properties[node.Term.String()]; ok {
+				continue
+			}
+			prop := Property{
+				Node:    node,
+				Name:    node.Term.String(),
+				Comment: getComment(node),
+				Range:   getRange(node),
+				Type:    propType,
+			}
+			err = prop.extractPropertyCharacteristics()
+			if err != nil {
+				err = errors.New(err.Error() + " property " + prop.Name)
+				return
 			}
+			properties[node.Term.String()] = &prop
 		}
 	}
 	return
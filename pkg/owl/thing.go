@@ -51,6 +51,7 @@ import (
 // Thing is the common base class of all types (owl:Thing)
 type Thing interface {
 	IRI() string
+	Term() rdf.Term
 	String() string
 	InitFromNode(*rdf.Node) error
 	ToGraph(*rdf.Graph)
@@ -0,0 +1,388 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// idIssuer issues identifiers for blank nodes (prefix and counter) in the order of the requests
+type idIssuer struct {
+	prefix  string
+	counter int
+	issued  map[string]string // issued identifiers by old identifier
+	order   []string          // old identifiers in the order of issuance
+}
+
+// newIDIssuer creates an identifier issuer with the specified prefix
+func newIDIssuer(prefix string) (is *idIssuer) {
+	is = &idIssuer{prefix: prefix, issued: make(map[string]string)}
+	return
+}
+
+// issue returns the identifier issued for the old identifier; a new one is issued if necessary
+func (is *idIssuer) issue(old string) (id string) {
+	id, ok := is.issued[old]
+	if ok {
+		return
+	}
+	id = is.prefix + strconv.Itoa(is.counter)
+	is.counter++
+	is.issued[old] = id
+	is.order = append(is.order, old)
+	return
+}
+
+// copy returns a deep copy of the issuer
+func (is *idIssuer) copy() (c *idIssuer) {
+	c = &idIssuer{prefix: is.prefix, counter: is.counter, issued: make(map[string]string),
+		order: append([]string(nil), is.order...)}
+	for k, v := range is.issued {
+		c.issued[k] = v
+	}
+	return
+}
+
+// canonicalizer holds the state of the rdf dataset canonicalization algorithm (RDFC-1.0)
+type canonicalizer struct {
+	quads       []Quad            // input quads without duplicates
+	blankQuads  map[string][]int  // indices of the quads mentioning a blank node
+	firstDegree map[string]string // first degree hashes of blank nodes
+	canonical   *idIssuer         // canonical identifiers (c14nN)
+}
+
+// canonicalQuads relabels the blank nodes of the quads with canonical identifiers (RDFC-1.0) and
+// returns the quads without duplicates sorted by their n-quads serialization
+func canonicalQuads(quad []Quad) (ret []Quad) {
+	c := &canonicalizer{blankQuads: make(map[string][]int), firstDegree: make(map[string]string),
+		canonical: newIDIssuer("c14n")}
+	seen := make(map[string]bool)
+	for i := range quad {
+		line := quad[i].SerializeNQuads()
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+		c.quads = append(c.quads, quad[i])
+		for _, term := range []Term{quad[i].Sub, quad[i].Obj, quad[i].Graph} {
+			blank, ok := term.(BlankNode)
+			if !ok {
+				continue
+			}
+			// the quad is referenced once even if the blank node occurs in several positions
+			idx := c.blankQuads[blank.name]
+			if len(idx) == 0 || idx[len(idx)-1] != len(c.quads)-1 {
+				c.blankQuads[blank.name] = append(idx, len(c.quads)-1)
+			}
+		}
+	}
+	c.issueIdentifiers()
+
+	lines := make([]string, len(c.quads))
+	ret = make([]Quad, len(c.quads))
+	for i, q := range c.quads {
+		ret[i] = Quad{Triple: Triple{Sub: c.relabel(q.Sub), Pred: q.Pred, Obj: c.relabel(q.Obj)},
+			Graph: c.relabel(q.Graph)}
+		lines[i] = ret[i].SerializeNQuads()
+	}
+	sort.Sort(quadsByLine{quads: ret, lines: lines})
+	return
+}
+
+// quadsByLine sorts quads by their n-quads serialization
+type quadsByLine struct {
+	quads []Quad
+	lines []string
+}
+
+func (q quadsByLine) Len() int           { return len(q.quads) }
+func (q quadsByLine) Less(i, j int) bool { return q.lines[i] < q.lines[j] }
+func (q quadsByLine) Swap(i, j int) {
+	q.quads[i], q.quads[j] = q.quads[j], q.quads[i]
+	q.lines[i], q.lines[j] = q.lines[j], q.lines[i]
+}
+
+// relabel replaces a blank node by its canonical blank node
+func (c *canonicalizer) relabel(term Term) (ret Term) {
+	ret = term
+	if blank, ok := term.(BlankNode); ok {
+		ret = BlankNode{name: c.canonical.issued[blank.name]}
+	}
+	return
+}
+
+// issueIdentifiers issues the canonical identifiers of all blank nodes: blank nodes with a unique
+// first degree hash first, then the remaining ones ordered by their n-degree hashes
+func (c *canonicalizer) issueIdentifiers() {
+	byHash := make(map[string][]string)
+	for blank := range c.blankQuads {
+		hash := c.hashFirstDegree(blank)
+		byHash[hash] = append(byHash[hash], blank)
+	}
+	hashes := make([]string, 0, len(byHash))
+	for hash := range byHash {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	var shared []string
+	for _, hash := range hashes {
+		if len(byHash[hash]) > 1 {
+			shared = append(shared, hash)
+			continue
+		}
+		c.canonical.issue(byHash[hash][0])
+	}
+	type pathResult struct {
+		hash   string
+		issuer *idIssuer
+	}
+	for _, hash := range shared {
+		var results []pathResult
+		for _, blank := range byHash[hash] {
+			if _, ok := c.canonical.issued[blank]; ok {
+				continue
+			}
+			issuer := newIDIssuer("b")
+			issuer.issue(blank)
+			h, is := c.hashNDegree(blank, issuer)
+			results = append(results, pathResult{hash: h, issuer: is})
+		}
+		sort.SliceStable(results, func(i, j int) bool { return results[i].hash < results[j].hash })
+		for _, res := range results {
+			for _, blank := range res.issuer.order {
+				c.canonical.issue(blank)
+			}
+		}
+	}
+}
+
+// hashFirstDegree hashes the quads mentioning a blank node (the blank node is replaced by _:a and
+// all other blank nodes by _:z)
+func (c *canonicalizer) hashFirstDegree(blank string) (hash string) {
+	hash, ok := c.firstDegree[blank]
+	if ok {
+		return
+	}
+	lines := make([]string, 0, len(c.blankQuads[blank]))
+	for _, i := range c.blankQuads[blank] {
+		lines = append(lines, serializeNQuad(c.quads[i], func(name string) string {
+			if name == blank {
+				return "a"
+			}
+			return "z"
+		})+"\n")
+	}
+	sort.Strings(lines)
+	hash = hashString(strings.Join(lines, ""))
+	c.firstDegree[blank] = hash
+	return
+}
+
+// hashRelated hashes a blank node that is related to another blank node by a quad; position is s,
+// o or g
+func (c *canonicalizer) hashRelated(related string, quad Quad, issuer *idIssuer,
+	position string) (hash string) {
+	input := position
+	if position != "g" {
+		input += "<" + quad.Pred.String() + ">"
+	}
+	if id, ok := c.canonical.issued[related]; ok {
+		input += "_:" + id
+	} else if id, ok := issuer.issued[related]; ok {
+		input += "_:" + id
+	} else {
+		input += c.hashFirstDegree(related)
+	}
+	hash = hashString(input)
+	return
+}
+
+// hashNDegree hashes a blank node with all blank nodes that are reachable from it; the returned
+// issuer contains the identifiers issued while choosing the lexicographically smallest path
+func (c *canonicalizer) hashNDegree(blank string, issuer *idIssuer) (hash string,
+	ret *idIssuer) {
+	related := make(map[string][]string)
+	for _, i := range c.blankQuads[blank] {
+		q := c.quads[i]
+		for _, comp := range []struct {
+			term     Term
+			position string
+		}{{q.Sub, "s"}, {q.Obj, "o"}, {q.Graph, "g"}} {
+			b, ok := comp.term.(BlankNode)
+			if !ok || b.name == blank {
+				continue
+			}
+			h := c.hashRelated(b.name, q, issuer, comp.position)
+			related[h] = append(related[h], b.name)
+		}
+	}
+	hashes := make([]string, 0, len(related))
+	for h := range related {
+		hashes = append(hashes, h)
+	}
+	sort.Strings(hashes)
+	var data strings.Builder
+	for _, h := range hashes {
+		data.WriteString(h)
+		chosenPath := ""
+		var chosenIssuer *idIssuer
+		permute(related[h], func(perm []string) {
+			issuerCopy := issuer.copy()
+			path := ""
+			var recursion []string
+			skip := func() bool {
+				return chosenPath != "" && len(path) >= len(chosenPath) && path > chosenPath
+			}
+			for _, r := range perm {
+				if id, ok := c.canonical.issued[r]; ok {
+					path += "_:" + id
+				} else {
+					if _, ok := issuerCopy.issued[r]; !ok {
+						recursion = append(recursion, r)
+					}
+					path += "_:" + issuerCopy.issue(r)
+				}
+				if skip() {
+					return
+				}
+			}
+			for _, r := range recursion {
+				resHash, resIssuer := c.hashNDegree(r, issuerCopy)
+				path += "_:" + issuerCopy.issue(r) + "<" + resHash + ">"
+				issuerCopy = resIssuer
+				if skip() {
+					return
+				}
+			}
+			if chosenPath == "" || path < chosenPath {
+				chosenPath, chosenIssuer = path, issuerCopy
+			}
+		})
+		data.WriteString(chosenPath)
+		issuer = chosenIssuer
+	}
+	hash, ret = hashString(data.String()), issuer
+	return
+}
+
+// permute calls fn for all permutations of the items
+func permute(items []string, fn func(perm []string)) {
+	perm := append([]string(nil), items...)
+	var gen func(k int)
+	gen = func(k int) {
+		if k == len(perm) {
+			fn(perm)
+			return
+		}
+		for i := k; i < len(perm); i++ {
+			perm[k], perm[i] = perm[i], perm[k]
+			gen(k + 1)
+			perm[k], perm[i] = perm[i], perm[k]
+		}
+	}
+	gen(0)
+}
+
+// serializeNQuad serializes a quad in n-quads format with blank nodes labeled by label
+func serializeNQuad(q Quad, label func(name string) string) (ret string) {
+	term := func(t Term) string {
+		if blank, ok := t.(BlankNode); ok {
+			return "_:" + label(blank.name)
+		}
+		return serializeNTerm(t)
+	}
+	ret = term(q.Sub) + " " + term(q.Pred) + " " + term(q.Obj)
+	if q.Graph != nil {
+		ret += " " + term(q.Graph)
+	}
+	ret += " ."
+	return
+}
+
+// hashString returns the hex encoded sha-256 hash of a string
+func hashString(str string) (hash string) {
+	sum := sha256.Sum256([]byte(str))
+	hash = hex.EncodeToString(sum[:])
+	return
+}
+
+// Canonical returns the triples of the graph with canonical blank node labels (c14n0, c14n1, ...)
+// as defined by RDF Dataset Canonicalization (RDFC-1.0), sorted by their n-triples serialization.
+// Isomorphic graphs have identical canonical triples.
+func (graph *Graph) Canonical() (trip []Triple) {
+	quad := make([]Quad, len(graph.Edges))
+	for i, edge := range graph.Edges {
+		quad[i] = Quad{Triple: Triple{Sub: edge.Subject.Term, Pred: edge.Pred,
+			Obj: edge.Object.Term}}
+	}
+	trip = quadsToTriples(canonicalQuads(quad))
+	return
+}
+
+// Canonical returns the quads of the dataset with canonical blank node labels (RDFC-1.0), sorted
+// by their n-quads serialization
+func (ds *Dataset) Canonical() (quad []Quad) {
+	quad = canonicalQuads(ds.ToQuads())
+	return
+}
+
+// Isomorphic checks if two graphs are equal except for the labels of their blank nodes
+func Isomorphic(a, b *Graph) (ok bool) {
+	ca, cb := a.Canonical(), b.Canonical()
+	if len(ca) != len(cb) {
+		return
+	}
+	for i := range ca {
+		if ca[i].SerializeNTriples() != cb[i].SerializeNTriples() {
+			return
+		}
+	}
+	ok = true
+	return
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/internal/testsuite"
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// rdfcTests is the base iri of the canonicalization test suite
+const rdfcTests = "http://json-ld.github.io/normalization/tests/"
+
+func TestCanonicalManifest(t *testing.T) {
+	dir := filepath.Join("testdata", "rdfc")
+	_, entries := testsuite.ReadManifest(t, filepath.Join(dir, "manifest.ttl"),
+		rdfcTests+"manifest.ttl")
+	for _, test := range entries {
+		test := test
+		action := testsuite.File(test.Action, rdfcTests)
+		t.Run(action, func(t *testing.T) {
+			if test.Type != "Urdna2015EvalTest" {
+				t.Fatalf("unknown test type %s", test.Type)
+			}
+			input, err := ioutil.ReadFile(filepath.Join(dir, action))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := ioutil.ReadFile(filepath.Join(dir, testsuite.File(test.Result, rdfcTests)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			quads, err := rdf.DecodeNQuads(bytes.NewReader(input))
+			if err != nil {
+				t.Fatalf("%s: %v", test.Name, err)
+			}
+			ds, err := rdf.NewDataset(quads)
+			if err != nil {
+				t.Fatalf("%s: %v", test.Name, err)
+			}
+			var got bytes.Buffer
+			rdf.EncodeNQuads(ds.Canonical(), &got)
+			if got.String() != string(want) {
+				t.Errorf("%s: got\n%s\nwant\n%s", test.Name, got.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+// Quad is one rdf triple together with the name of the graph it belongs to
+type Quad struct {
+	Triple
+	Graph Term // graph name (IRI or blank node); nil for the default graph
+}
+
+// NamedGraph is a graph of a dataset that is identified by a name
+type NamedGraph struct {
+	Name  Term  // graph name (IRI or blank node)
+	Graph Graph // graph
+}
+
+// Dataset is a rdf dataset containing a default graph and any number of named graphs
+type Dataset struct {
+	Default Graph                  // default graph
+	Named   map[string]*NamedGraph // named graphs (key = TermKey of the graph name)
+}
+
+// NewDataset creates a dataset from a rdf quad slice
+func NewDataset(quad []Quad) (ds Dataset, err error) {
+	var def []Triple
+	named := make(map[string][]Triple)
+	names := make(map[string]Term)
+	for i := range quad {
+		if quad[i].Graph == nil {
+			def = append(def, quad[i].Triple)
+			continue
+		}
+		name := TermKey(quad[i].Graph)
+		named[name] = append(named[name], quad[i].Triple)
+		names[name] = quad[i].Graph
+	}
+	ds.Default, err = NewGraph(def)
+	if err != nil {
+		return
+	}
+	ds.Named = make(map[string]*NamedGraph)
+	for i := range named {
+		ng := &NamedGraph{Name: names[i]}
+		ng.Graph, err = NewGraph(named[i])
+		if err != nil {
+			return
+		}
+		ds.Named[i] = ng
+	}
+	return
+}
+
+// Graph returns the graph with the specified name or the default graph if name is nil. A new
+// named graph is created if it does not exist yet.
+func (ds *Dataset) Graph(name Term) (g *Graph) {
+	if name == nil {
+		if ds.Default.Nodes == nil {
+			ds.Default.Nodes = make(map[string]*Node)
+		}
+		g = &ds.Default
+		return
+	}
+	if ds.Named == nil {
+		ds.Named = make(map[string]*NamedGraph)
+	}
+	ng, ok := ds.Named[TermKey(name)]
+	if !ok {
+		ng = &NamedGraph{Name: name, Graph: Graph{Nodes: make(map[string]*Node)}}
+		ds.Named[TermKey(name)] = ng
+	}
+	g = &ng.Graph
+	return
+}
+
+// ToQuads extracts quads from a dataset (default graph first)
+func (ds *Dataset) ToQuads() (ret []Quad) {
+	trip := ds.Default.ToTriples()
+	for i := range trip {
+		ret = append(ret, Quad{Triple: trip[i]})
+	}
+	for i := range ds.Named {
+		trip = ds.Named[i].Graph.ToTriples()
+		for j := range trip {
+			ret = append(ret, Quad{Triple: trip[j], Graph: ds.Named[i].Name})
+		}
+	}
+	return
+}
+
+// SerializeNQuads serializes a single Quad in n-quads format
+func (q Quad) SerializeNQuads() (ret string) {
+	ret = serializeNTerm(q.Sub) + " " + serializeNTerm(q.Pred) + " " + serializeNTerm(q.Obj)
+	if q.Graph != nil {
+		ret += " " + serializeNTerm(q.Graph)
+	}
+	ret += " ."
+	return
+}
+
+// quadsToTriples drops the graph names of quads
+func quadsToTriples(quad []Quad) (trip []Triple) {
+	trip = make([]Triple, len(quad))
+	for i := range quad {
+		trip[i] = quad[i].Triple
+	}
+	return
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Diff returns the triples that have to be added to and removed from oldGraph to get a graph that
+// is isomorphic to newGraph. Triples with blank nodes are compared by the parts of the graphs that
+// are connected by blank nodes: a changed part is removed and added as a whole. Added blank nodes
+// are relabeled if their labels are used in oldGraph, so that the changes can be applied to
+// oldGraph directly.
+func Diff(oldGraph, newGraph *Graph) (added, removed []Triple) {
+	oldGround, oldParts := splitBlankParts(oldGraph)
+	newGround, newParts := splitBlankParts(newGraph)
+
+	oldKeys := make(map[string]bool, len(oldGround))
+	for _, t := range oldGround {
+		oldKeys[tripleKey(t)] = true
+	}
+	newKeys := make(map[string]bool, len(newGround))
+	for _, t := range newGround {
+		newKeys[tripleKey(t)] = true
+		if !oldKeys[tripleKey(t)] {
+			added = append(added, t)
+		}
+	}
+	for _, t := range oldGround {
+		if !newKeys[tripleKey(t)] {
+			removed = append(removed, t)
+		}
+	}
+
+	// parts with identical canonical forms are unchanged
+	unmatched := make(map[string][]int)
+	for i, part := range oldParts {
+		sig := partSignature(part)
+		unmatched[sig] = append(unmatched[sig], i)
+	}
+	var addedParts [][]Triple
+	for _, part := range newParts {
+		sig := partSignature(part)
+		if idx := unmatched[sig]; len(idx) > 0 {
+			unmatched[sig] = idx[1:]
+			continue
+		}
+		addedParts = append(addedParts, part)
+	}
+	removedParts := make(map[int]bool)
+	for _, idx := range unmatched {
+		for _, i := range idx {
+			removedParts[i] = true
+		}
+	}
+	for i, part := range oldParts {
+		if removedParts[i] {
+			removed = append(removed, part...)
+		}
+	}
+
+	// relabel added blank nodes whose labels are used in oldGraph
+	labels := make(map[string]BlankNode)
+	issued := make(map[string]bool)
+	free := func(label string) bool {
+		return !issued[label] && oldGraph.Node(BlankNode{name: label}) == nil
+	}
+	num := 0
+	relabel := func(term Term) (ret Term) {
+		blank, ok := term.(BlankNode)
+		if !ok {
+			ret = term
+			return
+		}
+		l, ok := labels[blank.name]
+		if !ok {
+			l = blank
+			for !free(l.name) {
+				l = BlankNode{name: "b" + strconv.Itoa(num)}
+				num++
+				if newGraph.Node(l) != nil {
+					l = blank
+				}
+			}
+			labels[blank.name] = l
+			issued[l.name] = true
+		}
+		ret = l
+		return
+	}
+	for _, part := range addedParts {
+		for _, t := range part {
+			added = append(added, Triple{Sub: relabel(t.Sub), Pred: t.Pred, Obj: relabel(t.Obj)})
+		}
+	}
+	return
+}
+
+// splitBlankParts splits the triples of a graph into triples without blank nodes and parts of
+// triples that are connected by blank nodes (in the order of the edges)
+func splitBlankParts(graph *Graph) (ground []Triple, parts [][]Triple) {
+	// union find over the blank nodes
+	parent := make(map[string]string)
+	var find func(b string) string
+	find = func(b string) string {
+		p, ok := parent[b]
+		if !ok || p == b {
+			parent[b] = b
+			return b
+		}
+		root := find(p)
+		parent[b] = root
+		return root
+	}
+	for _, edge := range graph.Edges {
+		s, sok := edge.Subject.Term.(BlankNode)
+		o, ook := edge.Object.Term.(BlankNode)
+		switch {
+		case sok && ook:
+			parent[find(s.name)] = find(o.name)
+		case sok:
+			find(s.name)
+		case ook:
+			find(o.name)
+		}
+	}
+	index := make(map[string]int)
+	for _, edge := range graph.Edges {
+		t := Triple{Sub: edge.Subject.Term, Pred: edge.Pred, Obj: edge.Object.Term}
+		var blank string
+		if b, ok := t.Sub.(BlankNode); ok {
+			blank = b.name
+		} else if b, ok := t.Obj.(BlankNode); ok {
+			blank = b.name
+		} else {
+			ground = append(ground, t)
+			continue
+		}
+		root := find(blank)
+		i, ok := index[root]
+		if !ok {
+			i = len(parts)
+			index[root] = i
+			parts = append(parts, nil)
+		}
+		parts[i] = append(parts[i], t)
+	}
+	return
+}
+
+// partSignature returns the canonical n-quads of a part, which are identical for isomorphic parts
+func partSignature(part []Triple) (sig string) {
+	quad := make([]Quad, len(part))
+	for i := range part {
+		quad[i] = Quad{Triple: part[i]}
+	}
+	var b strings.Builder
+	for _, q := range canonicalQuads(quad) {
+		b.WriteString(q.SerializeNQuads() + "\n")
+	}
+	sig = b.String()
+	return
+}
+
+// tripleKey returns a key identifying a triple
+func tripleKey(t Triple) (key string) {
+	key = TermKey(t.Sub) + " " + TermKey(t.Pred) + " " + TermKey(t.Obj)
+	return
+}
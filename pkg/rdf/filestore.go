@@ -0,0 +1,411 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// files of a file store
+const (
+	storeTerms = "terms"   // one term per line in n-triples syntax; the line number is the term id
+	storeLog   = "log"     // committed changes (A s p o / D s p o with term ids; C ends a transaction)
+	storeIndex = "spo.idx" // term ids (s p o) of all triples at the time of the last Compact
+)
+
+// FileStore is a Store which persists its triples in a directory. The changes of each
+// transaction are appended to a log, Compact writes all triples to an index file and clears the
+// log. Terms are stored once and referenced by their id. All triples are held in memory for
+// queries.
+type FileStore struct {
+	dir      string
+	graph    Graph          // triples of the store
+	terms    []Term         // terms by id
+	ids      map[string]int // term ids by TermKey
+	written  int            // number of terms in the terms file
+	termFile *os.File       // terms file (append only)
+	termSize int64          // size of the terms file
+	logFile  *os.File       // log file (append only)
+	logSize  int64          // size of the log file
+	tx       bool           // transaction started with Begin
+}
+
+// OpenFileStore opens the file store in the directory dir; the directory is created if it does
+// not exist. Changes of transactions that have not been committed completely are discarded.
+func OpenFileStore(dir string) (store *FileStore, err error) {
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	s := &FileStore{dir: dir, ids: make(map[string]int)}
+	s.graph.Nodes = make(map[string]*Node)
+	if s.termSize, err = s.readFile(storeTerms, s.readTerm); err != nil {
+		return
+	}
+	s.written = len(s.terms)
+	if _, err = s.readFile(storeIndex, s.readIndex); err != nil {
+		return
+	}
+	var rows []PatchRow
+	s.logSize, err = s.readFile(storeLog, func(line string) (done bool, err error) {
+		if line == "C" {
+			s.graph.applyRows(rows)
+			rows = nil
+			done = true
+			return
+		}
+		row := PatchRow{Op: PatchAdd}
+		if strings.HasPrefix(line, "D ") {
+			row.Op = PatchDelete
+		} else if !strings.HasPrefix(line, "A ") {
+			err = errors.New("Invalid log entry " + line)
+			return
+		}
+		row.Quad.Triple, err = s.triple(line[2:])
+		rows = append(rows, row)
+		return
+	})
+	if err != nil {
+		return
+	}
+	if s.termFile, err = s.openFile(storeTerms, s.termSize); err != nil {
+		return
+	}
+	if s.logFile, err = s.openFile(storeLog, s.logSize); err != nil {
+		s.termFile.Close()
+		return
+	}
+	store = s
+	return
+}
+
+// readFile reads the lines of a store file and calls read for each line. The returned size is the
+// end of the last line for which read returned done (incomplete lines are never done); the
+// content behind it is ignored.
+func (store *FileStore) readFile(name string,
+	read func(line string) (done bool, err error)) (size int64, err error) {
+	file, err := os.Open(filepath.Join(store.dir, name))
+	if os.IsNotExist(err) {
+		err = nil
+		return
+	} else if err != nil {
+		return
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	var pos int64
+	for {
+		line, errRead := reader.ReadString('\n')
+		if errRead == io.EOF {
+			return
+		} else if errRead != nil {
+			err = errRead
+			return
+		}
+		pos += int64(len(line))
+		var done bool
+		if done, err = read(strings.TrimSuffix(line, "\n")); err != nil {
+			err = errors.New("Error reading " + name + ": " + err.Error())
+			return
+		}
+		if done {
+			size = pos
+		}
+	}
+}
+
+// openFile opens a store file for appending; content behind size is removed
+func (store *FileStore) openFile(name string, size int64) (file *os.File, err error) {
+	file, err = os.OpenFile(filepath.Join(store.dir, name), os.O_WRONLY|os.O_CREATE|os.O_APPEND,
+		0644)
+	if err != nil {
+		return
+	}
+	if err = file.Truncate(size); err != nil {
+		file.Close()
+	}
+	return
+}
+
+// readTerm reads a line of the terms file
+func (store *FileStore) readTerm(line string) (done bool, err error) {
+	p := &ntParser{line: line, lineNum: len(store.terms) + 1, keepLabel: true}
+	term, err := p.parseTerm("term")
+	if err != nil {
+		return
+	}
+	store.ids[TermKey(term)] = len(store.terms)
+	store.terms = append(store.terms, term)
+	done = true
+	return
+}
+
+// readIndex reads a line of the index file
+func (store *FileStore) readIndex(line string) (done bool, err error) {
+	trip, err := store.triple(line)
+	if err != nil {
+		return
+	}
+	store.graph.AddTriple(trip)
+	done = true
+	return
+}
+
+// triple returns the triple of the term ids s p o
+func (store *FileStore) triple(ids string) (trip Triple, err error) {
+	fields := strings.Fields(ids)
+	if len(fields) != 3 {
+		err = errors.New("Invalid triple " + ids)
+		return
+	}
+	var terms [3]Term
+	for i := range fields {
+		id, errConv := strconv.Atoi(fields[i])
+		if errConv != nil || id < 0 || id >= len(store.terms) {
+			err = errors.New("Invalid term id " + fields[i])
+			return
+		}
+		terms[i] = store.terms[id]
+	}
+	trip = Triple{Sub: terms[0], Pred: terms[1], Obj: terms[2]}
+	return
+}
+
+// id returns the id of a term; new terms get the next free id
+func (store *FileStore) id(term Term) (id string) {
+	num, ok := store.ids[TermKey(term)]
+	if !ok {
+		num = len(store.terms)
+		store.ids[TermKey(term)] = num
+		store.terms = append(store.terms, term)
+	}
+	id = strconv.Itoa(num)
+	return
+}
+
+// Add adds the triples to the store and returns the number of added triples
+func (store *FileStore) Add(triple ...Triple) (num int, err error) {
+	err = store.change(func() {
+		num, _ = store.graph.Add(triple...)
+	})
+	return
+}
+
+// Remove removes the triples from the store and returns the number of removed triples
+func (store *FileStore) Remove(triple ...Triple) (num int, err error) {
+	err = store.change(func() {
+		num, _ = store.graph.Remove(triple...)
+	})
+	return
+}
+
+// change applies a change to the graph; the change is written immediately if no transaction has
+// been started with Begin
+func (store *FileStore) change(apply func()) (err error) {
+	if store.logFile == nil {
+		err = errors.New("Store is closed")
+		return
+	}
+	if store.tx {
+		apply()
+		return
+	}
+	if err = store.graph.Begin(); err != nil {
+		return
+	}
+	apply()
+	err = store.write()
+	return
+}
+
+// Match returns an iterator over all triples of the store matching subject, predicate and object;
+// nil terms are wildcards. The store must not be changed while the iterator is used.
+func (store *FileStore) Match(s, p, o Term) (it *Iterator) {
+	it = store.graph.Match(s, p, o)
+	return
+}
+
+// Begin starts a transaction; the changes are written to the log when Commit is called
+func (store *FileStore) Begin() (err error) {
+	if store.logFile == nil {
+		err = errors.New("Store is closed")
+		return
+	}
+	if err = store.graph.Begin(); err != nil {
+		return
+	}
+	store.tx = true
+	return
+}
+
+// Commit writes the changes of the transaction to the log; the changes are undone if they cannot
+// be written
+func (store *FileStore) Commit() (err error) {
+	if !store.tx {
+		err = errors.New("No transaction started")
+		return
+	}
+	store.tx = false
+	err = store.write()
+	return
+}
+
+// Abort undoes the changes of the transaction
+func (store *FileStore) Abort() (err error) {
+	if !store.tx {
+		err = errors.New("No transaction started")
+		return
+	}
+	store.tx = false
+	err = store.graph.Abort()
+	return
+}
+
+// write appends new terms and the changes of the running graph transaction to the store files and
+// ends the graph transaction
+func (store *FileStore) write() (err error) {
+	rows := store.graph.tx.Rows
+	if len(rows) == 0 {
+		err = store.graph.Commit()
+		return
+	}
+	var log strings.Builder
+	for _, row := range rows {
+		op := "A "
+		if row.Op == PatchDelete {
+			op = "D "
+		}
+		trip := row.Quad.Triple
+		log.WriteString(op + store.id(trip.Sub) + " " + store.id(trip.Pred) + " " +
+			store.id(trip.Obj) + "\n")
+	}
+	log.WriteString("C\n")
+	var terms strings.Builder
+	for _, term := range store.terms[store.written:] {
+		terms.WriteString(serializeNTerm(term) + "\n")
+	}
+	termSize, logSize := store.termSize+int64(terms.Len()), store.logSize+int64(log.Len())
+	if err = appendFile(store.termFile, terms.String()); err == nil {
+		err = appendFile(store.logFile, log.String())
+	}
+	if err != nil {
+		store.termFile.Truncate(store.termSize)
+		store.logFile.Truncate(store.logSize)
+		store.graph.Abort()
+		return
+	}
+	store.written = len(store.terms)
+	store.termSize, store.logSize = termSize, logSize
+	err = store.graph.Commit()
+	return
+}
+
+// appendFile appends data to a file and flushes the file to disk
+func appendFile(file *os.File, data string) (err error) {
+	if _, err = file.WriteString(data); err != nil {
+		return
+	}
+	err = file.Sync()
+	return
+}
+
+// Compact writes all triples to the index file and clears the log
+func (store *FileStore) Compact() (err error) {
+	if store.logFile == nil {
+		err = errors.New("Store is closed")
+		return
+	}
+	if store.tx {
+		err = errors.New("Transaction is running")
+		return
+	}
+	var index strings.Builder
+	for _, edge := range store.graph.Edges {
+		index.WriteString(store.id(edge.Subject.Term) + " " + store.id(edge.Pred) + " " +
+			store.id(edge.Object.Term) + "\n")
+	}
+	name := filepath.Join(store.dir, storeIndex)
+	file, err := os.Create(name + ".tmp")
+	if err != nil {
+		return
+	}
+	err = appendFile(file, index.String())
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = os.Rename(name+".tmp", name)
+	}
+	if err != nil {
+		os.Remove(name + ".tmp")
+		return
+	}
+	if err = store.logFile.Truncate(0); err != nil {
+		return
+	}
+	store.logSize = 0
+	return
+}
+
+// Close aborts a running transaction and closes the store files
+func (store *FileStore) Close() (err error) {
+	if store.logFile == nil {
+		return
+	}
+	if store.tx {
+		store.Abort()
+	}
+	err = store.termFile.Close()
+	if errClose := store.logFile.Close(); err == nil {
+		err = errClose
+	}
+	store.termFile, store.logFile = nil, nil
+	return
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// storeTriples returns the triples used by the file store tests; they contain all kinds of terms
+func storeTriples() (trips []Triple) {
+	for i := 0; i < 20; i++ {
+		trips = append(trips, testTriple(i))
+	}
+	p := NewIRI("http://example.com/p")
+	trips = append(trips,
+		Triple{Sub: NewBlankNode("b0"), Pred: p, Obj: NewLangLiteral("text\n\"quoted\"", "en")},
+		Triple{Sub: NewBlankNode("b0"), Pred: p, Obj: NewTypedLiteral("42", XsdInteger)},
+		Triple{Sub: NewIRI("http://example.com/s0"), Pred: p, Obj: NewBlankNode("b0")})
+	return
+}
+
+// openTestStore opens the file store in the directory and fails the test on errors
+func openTestStore(t *testing.T, dir string) (store *FileStore) {
+	store, err := OpenFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+// checkStore checks that the store contains exactly the triples
+func checkStore(t *testing.T, store *FileStore, want []Triple) {
+	var trips []Triple
+	for it := store.Match(nil, nil, nil); it.Next(); {
+		trips = append(trips, it.Triple())
+	}
+	got, _ := NewGraph(trips)
+	exp, _ := NewGraph(want)
+	if !Isomorphic(&got, &exp) {
+		t.Fatalf("store has %d triples, want %d", len(trips), len(want))
+	}
+}
+
+func TestFileStoreReopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filestore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	trips := storeTriples()
+	store := openTestStore(t, dir)
+	if num, err := store.Add(trips[:10]...); err != nil || num != 10 {
+		t.Fatalf("added %d triples: %v", num, err)
+	}
+	if err = store.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	store.Add(trips[10:]...)
+	store.Remove(trips[0])
+	if err = store.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	want := trips[1:]
+	checkStore(t, store, want)
+	store.Close()
+
+	store = openTestStore(t, dir)
+	checkStore(t, store, want)
+	if err = store.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	store.Remove(trips[1])
+	want = trips[2:]
+	store.Close()
+
+	store = openTestStore(t, dir)
+	defer store.Close()
+	checkStore(t, store, want)
+}
+
+func TestFileStoreAbort(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filestore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	trips := storeTriples()
+	store := openTestStore(t, dir)
+	store.Add(trips[:10]...)
+	if err = store.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	store.Add(trips[10:]...)
+	store.Remove(trips[:5]...)
+	if err = store.Abort(); err != nil {
+		t.Fatal(err)
+	}
+	checkStore(t, store, trips[:10])
+	if err = store.Abort(); err == nil {
+		t.Fatal("abort without transaction succeeded")
+	}
+
+	// the transaction running when the store is closed is discarded
+	store.Begin()
+	store.Remove(trips[:10]...)
+	store.Close()
+	store = openTestStore(t, dir)
+	checkStore(t, store, trips[:10])
+	store.Add(trips[10])
+	store.Close()
+
+	// changes of an incompletely written transaction are ignored
+	log, err := os.OpenFile(filepath.Join(dir, storeLog), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.WriteString("D 0 1 2\nA 0 1")
+	log.Close()
+	store = openTestStore(t, dir)
+	checkStore(t, store, trips[:11])
+	store.Add(trips[11])
+	store.Close()
+	store = openTestStore(t, dir)
+	checkStore(t, store, trips[:12])
+	store.Close()
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"path"
+	"strings"
+)
+
+// media types of the supported rdf serialization formats
+const (
+	FormatTurtle   = "text/turtle"
+	FormatNTriples = "application/n-triples"
+	FormatNQuads   = "application/n-quads"
+	FormatTriG     = "application/trig"
+	FormatJSONLD   = "application/ld+json"
+	FormatRDFXML   = "application/rdf+xml"
+)
+
+// Decode decodes an input in the specified format (media type) to rdf triples. Triples of named
+// graphs are merged into the result. Optional decode options set the base iri of the document and
+// the loader for json-ld.
+func Decode(input io.Reader, format string, opts ...DecodeOptions) (trip []Triple, err error) {
+	var quad []Quad
+	switch format {
+	case FormatTurtle:
+		trip, err = DecodeTTL(input, opts...)
+	case FormatNTriples:
+		trip, err = DecodeNTriples(input)
+	case FormatNQuads:
+		quad, err = DecodeNQuads(input)
+		trip = quadsToTriples(quad)
+	case FormatTriG:
+		quad, err = DecodeTriG(input, opts...)
+		trip = quadsToTriples(quad)
+	case FormatJSONLD:
+		trip, err = DecodeJSONLD(input, opts...)
+	case FormatRDFXML:
+		trip, err = DecodeRDFXML(input, opts...)
+	default:
+		err = errors.New("unsupported rdf format " + format)
+	}
+	return
+}
+
+// FormatFromMediaType returns the format of a content type (e.g. of a http response). An empty
+// string is returned if the content type is not a known rdf serialization.
+func FormatFromMediaType(contentType string) (format string) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return
+	}
+	switch mediaType {
+	case FormatTurtle, "application/x-turtle", "application/turtle":
+		format = FormatTurtle
+	case FormatNTriples:
+		format = FormatNTriples
+	case FormatNQuads, "text/x-nquads":
+		format = FormatNQuads
+	case FormatTriG, "application/x-trig":
+		format = FormatTriG
+	case FormatJSONLD, "application/json":
+		format = FormatJSONLD
+	case FormatRDFXML, "application/xml", "text/xml":
+		format = FormatRDFXML
+	}
+	return
+}
+
+// FormatFromExtension returns the format of a file name or url based on its extension. An empty
+// string is returned if the extension is unknown.
+func FormatFromExtension(name string) (format string) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".ttl", ".turtle":
+		format = FormatTurtle
+	case ".nt":
+		format = FormatNTriples
+	case ".nq":
+		format = FormatNQuads
+	case ".trig":
+		format = FormatTriG
+	case ".jsonld", ".json":
+		format = FormatJSONLD
+	case ".rdf", ".owl", ".xml", ".rdfs":
+		format = FormatRDFXML
+	}
+	return
+}
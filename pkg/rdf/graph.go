@@ -59,53 +59,164 @@ type Node struct {
 
 // Edge is a edge (predicate) in a rdf graph
 type Edge struct {
-	Pred    Predicate
-	Subject *Node
-	Object  *Node
+	Pred     Predicate
+	Subject  *Node
+	Object   *Node
+	Inferred bool // edge has been inferred by a reasoner
+	pos      int  // position in the edges of the graph (set when the edge is indexed)
+	removed  bool // edge has been removed from the graph
 }
 
-// Graph is a rdf grapgh containing nodes and edges
+// Graph is a rdf grapgh containing nodes (stored by TermKey) and edges; edges that are appended to
+// Edges are indexed when the graph is queried the next time (e.g. with Match). A graph must not be
+// copied, since the copy would share nodes, index and transaction with the original (go vet
+// reports copies); pass a *Graph or use Copy instead.
 type Graph struct {
-	Nodes map[string]*Node
-	Edges []*Edge
+	Nodes  map[string]*Node
+	Edges  []*Edge
+	index  *graphIndex
+	tx     *Patch // changes of the running transaction (nil: no transaction)
+	noCopy noCopy
 }
 
-// NewGraph creates a graph from an rdf triple slice
+// noCopy makes go vet report copies of the struct containing it (see sync.WaitGroup)
+type noCopy struct{}
+
+// Lock is a no-op used by the copylocks check of go vet
+func (*noCopy) Lock() {}
+
+// Unlock is a no-op used by the copylocks check of go vet
+func (*noCopy) Unlock() {}
+
+// NewGraph creates a graph from an rdf triple slice; identical triples are added once
 func NewGraph(triple []Triple) (graph Graph, err error) {
 	graph.Nodes = make(map[string]*Node)
 	for i := range triple {
-		// object
-		obj, ok := graph.Nodes[triple[i].Obj.String()]
-		if !ok {
-			obj = &Node{
-				Term: triple[i].Obj,
-			}
-			graph.Nodes[obj.Term.String()] = obj
+		graph.AddTriple(triple[i])
+	}
+	err = nil
+	return
+}
+
+// AddNode returns the node of the term; the node is created if the graph does not contain it yet
+func (graph *Graph) AddNode(term Term) (node *Node) {
+	if graph.Nodes == nil {
+		graph.Nodes = make(map[string]*Node)
+	}
+	node, ok := graph.Nodes[TermKey(term)]
+	if !ok {
+		node = &Node{Term: term}
+		graph.Nodes[TermKey(term)] = node
+	}
+	return
+}
+
+// Node returns the node of the term (nil if the graph does not contain it)
+func (graph *Graph) Node(term Term) (node *Node) {
+	node = graph.Nodes[TermKey(term)]
+	return
+}
+
+// AddTriple adds the nodes and the edge of a triple to the graph; if the graph already contains
+// the triple, the existing edge is returned and added is false
+func (graph *Graph) AddTriple(triple Triple) (edge *Edge, added bool) {
+	subj := graph.AddNode(triple.Sub)
+	obj := graph.AddNode(triple.Obj)
+	if it := graph.Match(triple.Sub, triple.Pred, triple.Obj); it.Next() {
+		edge = it.Edge()
+		return
+	}
+	edge = &Edge{
+		Pred:    triple.Pred,
+		Subject: subj,
+		Object:  obj,
+	}
+	subj.Edge = append(subj.Edge, edge)
+	obj.InverseEdge = append(obj.InverseEdge, edge)
+	graph.Edges = append(graph.Edges, edge)
+	graph.record(PatchAdd, edge)
+	added = true
+	return
+}
+
+// RemoveTriple removes the edges of the triples from the graph and returns the number of removed
+// edges; triples that are not contained in the graph are ignored
+func (graph *Graph) RemoveTriple(triple ...Triple) (num int) {
+	var edges []*Edge
+	for i := range triple {
+		for it := graph.Match(triple[i].Sub, triple[i].Pred, triple[i].Obj); it.Next(); {
+			edges = append(edges, it.Edge())
 		}
+	}
+	num = graph.RemoveEdge(edges...)
+	return
+}
 
-		// subject
-		subj, ok := graph.Nodes[triple[i].Sub.String()]
-		if !ok {
-			subj = &Node{
-				Term: triple[i].Sub,
+// RemoveEdge removes the edges from the graph and from their subject and object nodes and returns
+// the number of removed edges; nodes without any remaining edge are removed from the graph. The
+// last edges of Edges are moved to the positions of the removed edges.
+func (graph *Graph) RemoveEdge(edge ...*Edge) (num int) {
+	idx := graph.updateIndex()
+	var del []*Edge
+	for _, e := range edge {
+		if e.pos < len(graph.Edges) && graph.Edges[e.pos] == e && !e.removed {
+			e.removed = true
+			del = append(del, e)
+		}
+	}
+	if len(del) == 0 {
+		return
+	}
+	nodes := make(map[*Node]bool)
+	for _, e := range del {
+		nodes[e.Subject], nodes[e.Object] = true, true
+		graph.record(PatchDelete, e)
+		last := graph.Edges[len(graph.Edges)-1]
+		graph.Edges[e.pos], last.pos = last, e.pos
+		graph.Edges = graph.Edges[:len(graph.Edges)-1]
+	}
+	num = len(del)
+	idx.remove(graph, num)
+	keep := func(edges []*Edge) (ret []*Edge) {
+		for _, e := range edges {
+			if !e.removed {
+				ret = append(ret, e)
 			}
-			graph.Nodes[subj.Term.String()] = subj
 		}
-
-		// predicate
-		edge := &Edge{
-			Pred:    triple[i].Pred,
-			Subject: subj,
-			Object:  obj,
+		return
+	}
+	for node := range nodes {
+		node.Edge = keep(node.Edge)
+		node.InverseEdge = keep(node.InverseEdge)
+		if len(node.Edge) == 0 && len(node.InverseEdge) == 0 {
+			graph.removeNode(node)
 		}
-		subj.Edge = append(subj.Edge, edge)
-		obj.InverseEdge = append(obj.InverseEdge, edge)
-		graph.Edges = append(graph.Edges, edge)
 	}
-	err = nil
 	return
 }
 
+// RemoveNode removes the nodes and all edges from or to them from the graph and returns the number
+// of removed edges
+func (graph *Graph) RemoveNode(node ...*Node) (num int) {
+	var edges []*Edge
+	for i := range node {
+		edges = append(edges, node[i].Edge...)
+		edges = append(edges, node[i].InverseEdge...)
+	}
+	num = graph.RemoveEdge(edges...)
+	for i := range node {
+		graph.removeNode(node[i])
+	}
+	return
+}
+
+// removeNode deletes the node from the nodes of the graph
+func (graph *Graph) removeNode(node *Node) {
+	if graph.Nodes[TermKey(node.Term)] == node {
+		delete(graph.Nodes, TermKey(node.Term))
+	}
+}
+
 // ToTriples extracts triples from a graph
 func (graph *Graph) ToTriples() (ret []Triple) {
 	for i := range graph.Edges {
@@ -119,6 +230,25 @@ func (graph *Graph) ToTriples() (ret []Triple) {
 	return
 }
 
+// Copy returns a copy of the graph with new nodes and edges (including the inferred flags); the
+// copy has no index and no running transaction
+func (graph *Graph) Copy() (g Graph) {
+	g.Nodes = make(map[string]*Node, len(graph.Nodes))
+	for i := range graph.Nodes {
+		g.Nodes[i] = &Node{Term: graph.Nodes[i].Term}
+	}
+	g.Edges = make([]*Edge, 0, len(graph.Edges))
+	for _, edge := range graph.Edges {
+		subj := g.Nodes[TermKey(edge.Subject.Term)]
+		obj := g.Nodes[TermKey(edge.Object.Term)]
+		e := &Edge{Pred: edge.Pred, Subject: subj, Object: obj, Inferred: edge.Inferred}
+		subj.Edge = append(subj.Edge, e)
+		obj.InverseEdge = append(obj.InverseEdge, e)
+		g.Edges = append(g.Edges, e)
+	}
+	return
+}
+
 // SubGraph returns a graph containing the specified nodes and all transitive objects
 func (graph *Graph) SubGraph(nodes ...*Node) (g Graph) {
 	sub := make(map[string]*Node)
@@ -128,13 +258,13 @@ func (graph *Graph) SubGraph(nodes ...*Node) (g Graph) {
 	g.Nodes = make(map[string]*Node)
 	for i := range sub {
 		newNode := &Node{Term: sub[i].Term}
-		g.Nodes[newNode.Term.String()] = newNode
+		g.Nodes[TermKey(newNode.Term)] = newNode
 	}
 	for i := range sub {
 		subj, ok := g.Nodes[i]
 		if ok {
 			for j := range sub[i].Edge {
-				obj, ok := g.Nodes[sub[i].Edge[j].Object.Term.String()]
+				obj, ok := g.Nodes[TermKey(sub[i].Edge[j].Object.Term)]
 				if ok {
 					pred := &Edge{
 						Pred:    sub[i].Edge[j].Pred,
@@ -154,9 +284,9 @@ func (graph *Graph) SubGraph(nodes ...*Node) (g Graph) {
 // addDependentNodes adds all nodes that are connected to node via an edge
 func (node *Node) addDependentNodes(nodes map[string]*Node) {
 	for i := range node.Edge {
-		if _, ok := nodes[node.Edge[i].Object.Term.String()]; !ok {
+		if _, ok := nodes[TermKey(node.Edge[i].Object.Term)]; !ok {
 			obj := node.Edge[i].Object
-			nodes[obj.Term.String()] = obj
+			nodes[TermKey(obj.Term)] = obj
 			obj.addDependentNodes(nodes)
 		}
 	}
@@ -170,64 +300,54 @@ func (node *Node) addDependentNodes(nodes map[string]*Node) {
 	return
 }
 
-// Merge merges gIn into graph (nodes and edges are copied)
+// Merge merges gIn into graph (nodes and edges are copied); the blank nodes of gIn are scoped to
+// gIn and get labels which are not used in graph, gIn is not modified
 func (graph *Graph) Merge(gIn *Graph) (err error) {
-	blankID := 0
-	for i := range graph.Nodes {
-		if graph.Nodes[i].Term.Type() == TermBlankNode {
-			temp := strings.Split(i, "bn")
-			if len(temp) > 1 {
-				id, err := strconv.Atoi(temp[1])
-				if err != nil {
-					return err
-				}
-				if id > blankID {
-					blankID = id
-				}
-			}
+	next := 0
+	blanks := make(map[string]Term)
+	term := func(t Term) Term {
+		if t.Type() != TermBlankNode {
+			return t
 		}
-	}
-	blankID++
-	for i := range gIn.Nodes {
-		if gIn.Nodes[i].Term.Type() == TermBlankNode {
-			gIn.Nodes[i].Term = BlankNode{name: "bn" + strconv.Itoa(blankID)}
-			blankID++
+		blank, ok := blanks[t.String()]
+		if !ok {
+			blank = graph.newBlankNode(&next)
+			blanks[t.String()] = blank
 		}
+		return blank
 	}
-
+	edges := gIn.Edges
+	for i := range edges {
+		graph.AddTriple(Triple{
+			Sub:  term(edges[i].Subject.Term),
+			Pred: edges[i].Pred,
+			Obj:  term(edges[i].Object.Term),
+		})
+	}
+	var nodes []Term
 	for i := range gIn.Nodes {
-		if _, ok := graph.Nodes[gIn.Nodes[i].Term.String()]; !ok {
-			n := &Node{Term: gIn.Nodes[i].Term}
-			graph.Nodes[gIn.Nodes[i].Term.String()] = n
+		if len(gIn.Nodes[i].Edge) == 0 && len(gIn.Nodes[i].InverseEdge) == 0 {
+			nodes = append(nodes, gIn.Nodes[i].Term)
 		}
 	}
-	for i := range gIn.Edges {
-		if subj, ok := graph.Nodes[gIn.Edges[i].Subject.Term.String()]; ok {
-			predExist := false
-			for j := range subj.Edge {
-				if subj.Edge[j].Pred.String() == gIn.Edges[i].Pred.String() &&
-					subj.Edge[j].Object.Term.String() == gIn.Edges[i].Object.Term.String() {
-					predExist = true
-					break
-				}
-			}
-			if !predExist {
-				if obj, ok := graph.Nodes[gIn.Edges[i].Object.Term.String()]; ok {
-					pred := &Edge{
-						Pred:    gIn.Edges[i].Pred,
-						Subject: subj,
-						Object:  obj,
-					}
-					subj.Edge = append(subj.Edge, pred)
-					obj.InverseEdge = append(obj.InverseEdge, pred)
-					graph.Edges = append(graph.Edges, pred)
-				}
-			}
-		}
+	for i := range nodes {
+		graph.AddNode(term(nodes[i]))
 	}
 	return
 }
 
+// newBlankNode returns a blank node labeled bnN which is not used in the graph; N starts at next
+// and next is set behind N
+func (graph *Graph) newBlankNode(next *int) (blank BlankNode) {
+	for {
+		blank = BlankNode{name: "bn" + strconv.Itoa(*next)}
+		*next++
+		if graph.Node(blank) == nil {
+			return
+		}
+	}
+}
+
 // ToGraphvizDot exports a graph to the graphviz dot format
 func (graph *Graph) ToGraphvizDot(output io.Writer, replace map[string]string,
 	nodeShape map[string]string) (err error) {
@@ -235,7 +355,7 @@ func (graph *Graph) ToGraphvizDot(output io.Writer, replace map[string]string,
 	Index := 0
 	dot := "digraph model\n{\n"
 	for i := range graph.Nodes {
-		label := i
+		label := graph.Nodes[i].Term.String()
 		shape := ""
 		// if graph.Nodes[i].Literal != nil {
 		// 	label = graph.Nodes[i].Literal.String()
@@ -247,7 +367,7 @@ func (graph *Graph) ToGraphvizDot(output io.Writer, replace map[string]string,
 			}
 		}
 		for j := range nodeShape {
-			if strings.HasPrefix(i, j) {
+			if strings.HasPrefix(graph.Nodes[i].Term.String(), j) {
 				shape = "shape=" + nodeShape[j] + ", "
 			}
 		}
@@ -265,8 +385,8 @@ func (graph *Graph) ToGraphvizDot(output io.Writer, replace map[string]string,
 				break
 			}
 		}
-		subj := "n" + strconv.Itoa(labelIndex[graph.Edges[i].Subject.Term.String()])
-		obj := "n" + strconv.Itoa(labelIndex[graph.Edges[i].Object.Term.String()])
+		subj := "n" + strconv.Itoa(labelIndex[TermKey(graph.Edges[i].Subject.Term)])
+		obj := "n" + strconv.Itoa(labelIndex[TermKey(graph.Edges[i].Object.Term)])
 		dot += subj + " -> " + obj + " [label=<" + label + ">]\n"
 	}
 	dot += "}\n"
@@ -0,0 +1,170 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// testTriple returns the i-th triple of a set of triples with shared subjects and predicates
+func testTriple(i int) (trip Triple) {
+	trip = Triple{
+		Sub:  NewIRI("http://example.com/s" + strconv.Itoa(i%10)),
+		Pred: NewIRI("http://example.com/p" + strconv.Itoa(i%3)),
+		Obj:  NewIRI("http://example.com/o" + strconv.Itoa(i)),
+	}
+	return
+}
+
+func TestRemoveKeepsIndex(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	graph, _ := NewGraph(nil)
+	want := make(map[string]Triple)
+	for round := 0; round < 5000; round++ {
+		trip := testTriple(r.Intn(100))
+		if r.Intn(2) == 0 {
+			graph.AddTriple(trip)
+			want[TermKey(trip.Obj)] = trip
+		} else {
+			graph.RemoveTriple(trip)
+			delete(want, TermKey(trip.Obj))
+		}
+		if round%100 != 0 {
+			continue
+		}
+		if len(graph.Edges) != len(want) {
+			t.Fatalf("round %d: %d edges, want %d", round, len(graph.Edges), len(want))
+		}
+		for i := 0; i < 100; i++ {
+			trip := testTriple(i)
+			_, ok := want[TermKey(trip.Obj)]
+			patterns := [][3]Term{
+				{trip.Sub, trip.Pred, trip.Obj}, {trip.Sub, nil, trip.Obj}, {nil, trip.Pred, trip.Obj},
+				{nil, nil, trip.Obj},
+			}
+			for _, pat := range patterns {
+				if found := graph.Match(pat[0], pat[1], pat[2]).Next(); found != ok {
+					t.Fatalf("round %d: match %v = %v, want %v", round, pat, found, ok)
+				}
+			}
+			if ok != (graph.Node(trip.Obj) != nil) {
+				t.Fatalf("round %d: node %v exists = %v", round, trip.Obj, !ok)
+			}
+		}
+		for _, trip := range want {
+			n := 0
+			for it := graph.Match(trip.Sub, trip.Pred, nil); it.Next(); {
+				n++
+			}
+			if n != len(graph.Objects(trip.Sub, trip.Pred)) ||
+				n != countEdges(graph.Node(trip.Sub), trip.Pred) {
+				t.Fatalf("round %d: inconsistent edges of %v", round, trip.Sub)
+			}
+		}
+	}
+}
+
+// countEdges counts the edges of the node with the predicate
+func countEdges(node *Node, pred Term) (n int) {
+	for _, e := range node.Edge {
+		if EqualTerms(e.Pred, pred) {
+			n++
+		}
+	}
+	return
+}
+
+func TestRemoveAllEdges(t *testing.T) {
+	var trips []Triple
+	for i := 0; i < 50; i++ {
+		trips = append(trips, testTriple(i))
+	}
+	graph, _ := NewGraph(trips)
+	if num := graph.RemoveEdge(graph.Edges...); num != 50 {
+		t.Fatalf("removed %d edges, want 50", num)
+	}
+	if len(graph.Edges) != 0 || len(graph.Nodes) != 0 || graph.Match(nil, nil, nil).Next() {
+		t.Fatalf("graph not empty: %d edges, %d nodes", len(graph.Edges),
+			len(graph.Nodes))
+	}
+}
+
+func TestCopy(t *testing.T) {
+	var graph Graph
+	for i := 0; i < 20; i++ {
+		graph.AddTriple(testTriple(i))
+	}
+	graph.Edges[0].Inferred = true
+	if err := graph.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	g := graph.Copy()
+	if !g.Edges[0].Inferred || g.Edges[0] == graph.Edges[0] {
+		t.Error("edges are not copied with inferred flag")
+	}
+	// changes of the copy are not recorded in the transaction and not seen by the original
+	g.RemoveTriple(testTriple(1))
+	g.AddTriple(testTriple(100))
+	if err := g.Commit(); err == nil {
+		t.Error("copy has a running transaction")
+	}
+	if err := graph.Abort(); err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.Edges) != 20 || graph.Node(testTriple(100).Obj) != nil {
+		t.Errorf("original changed: %d edges", len(graph.Edges))
+	}
+	if n := len(graph.Objects(testTriple(1).Sub, testTriple(1).Pred)); n != 1 {
+		t.Errorf("original: got %d objects, want 1", n)
+	}
+	if n := len(g.Objects(testTriple(1).Sub, testTriple(1).Pred)); n != 0 {
+		t.Errorf("copy: got %d objects, want 0", n)
+	}
+	if len(g.Edges) != 20 {
+		t.Errorf("copy: got %d edges, want 20", len(g.Edges))
+	}
+}
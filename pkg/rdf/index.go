@@ -0,0 +1,223 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+// graphIndex indexes the edges of a graph by subject, predicate and object; all lists keep the
+// order in which the edges have been added. Removed edges stay in the lists until the index is
+// rebuilt, which happens when they outnumber the remaining edges.
+type graphIndex struct {
+	num  int                          // number of indexed edges
+	dead int                          // number of removed edges in the lists
+	last *Edge                        // last indexed edge (to detect replaced edges)
+	s    map[*Node][]*Edge            // edges by subject
+	p    map[string][]*Edge           // edges by predicate
+	o    map[*Node][]*Edge            // edges by object
+	spo  map[*Node]map[string][]*Edge // edges by subject and predicate
+	pos  map[string]map[*Node][]*Edge // edges by predicate and object
+	osp  map[*Node]map[*Node][]*Edge  // edges by object and subject
+}
+
+// Iterator iterates over the edges matching a triple pattern
+type Iterator struct {
+	edges  []*Edge               // remaining edges
+	filter func(edge *Edge) bool // additional condition for edges (nil: all edges match)
+	edge   *Edge                 // current edge
+}
+
+// Next moves the iterator to the next matching edge; false is returned if there is none
+func (it *Iterator) Next() (ok bool) {
+	for len(it.edges) > 0 {
+		edge := it.edges[0]
+		it.edges = it.edges[1:]
+		if !edge.removed && (it.filter == nil || it.filter(edge)) {
+			it.edge = edge
+			ok = true
+			return
+		}
+	}
+	it.edge = nil
+	return
+}
+
+// Edge returns the current edge
+func (it *Iterator) Edge() (edge *Edge) {
+	edge = it.edge
+	return
+}
+
+// Triple returns the current edge as triple
+func (it *Iterator) Triple() (trip Triple) {
+	if it.edge != nil {
+		trip = Triple{Sub: it.edge.Subject.Term, Pred: it.edge.Pred, Obj: it.edge.Object.Term}
+	}
+	return
+}
+
+// Match returns an iterator over all edges matching subject, predicate and object in the order
+// they have been added (see RemoveEdge for the order after removals); nil terms are wildcards
+func (graph *Graph) Match(s, p, o Term) (it *Iterator) {
+	it = &Iterator{}
+	idx := graph.updateIndex()
+	var subj, obj *Node
+	if s != nil {
+		if subj = graph.Nodes[TermKey(s)]; subj == nil {
+			return
+		}
+	}
+	if o != nil {
+		if obj = graph.Nodes[TermKey(o)]; obj == nil {
+			return
+		}
+	}
+	pred := ""
+	if p != nil {
+		pred = p.String()
+	}
+	switch {
+	case s != nil && p != nil:
+		it.edges = idx.spo[subj][pred]
+		if o != nil {
+			it.filter = func(edge *Edge) bool { return edge.Object == obj }
+		}
+	case s != nil && o != nil:
+		it.edges = idx.osp[obj][subj]
+	case p != nil && o != nil:
+		it.edges = idx.pos[pred][obj]
+	case s != nil:
+		it.edges = idx.s[subj]
+	case p != nil:
+		it.edges = idx.p[pred]
+	case o != nil:
+		it.edges = idx.o[obj]
+	default:
+		it.edges = graph.Edges
+	}
+	return
+}
+
+// Objects returns the distinct objects of all edges with the specified subject and predicate
+func (graph *Graph) Objects(s, p Term) (obj []*Node) {
+	found := make(map[*Node]bool)
+	for it := graph.Match(s, p, nil); it.Next(); {
+		if node := it.Edge().Object; !found[node] {
+			found[node] = true
+			obj = append(obj, node)
+		}
+	}
+	return
+}
+
+// Subjects returns the distinct subjects of all edges with the specified predicate and object
+func (graph *Graph) Subjects(p, o Term) (subj []*Node) {
+	found := make(map[*Node]bool)
+	for it := graph.Match(nil, p, o); it.Next(); {
+		if node := it.Edge().Subject; !found[node] {
+			found[node] = true
+			subj = append(subj, node)
+		}
+	}
+	return
+}
+
+// updateIndex indexes all edges appended since the last update; the index is rebuilt if indexed
+// edges have been removed or replaced
+func (graph *Graph) updateIndex() (idx *graphIndex) {
+	idx = graph.index
+	if idx == nil || len(graph.Edges) < idx.num ||
+		(idx.num > 0 && graph.Edges[idx.num-1] != idx.last) {
+		idx = &graphIndex{
+			s:   make(map[*Node][]*Edge),
+			p:   make(map[string][]*Edge),
+			o:   make(map[*Node][]*Edge),
+			spo: make(map[*Node]map[string][]*Edge),
+			pos: make(map[string]map[*Node][]*Edge),
+			osp: make(map[*Node]map[*Node][]*Edge),
+		}
+		graph.index = idx
+	}
+	for i, edge := range graph.Edges[idx.num:] {
+		edge.pos, edge.removed = idx.num+i, false
+		idx.add(edge)
+	}
+	idx.num = len(graph.Edges)
+	if idx.num > 0 {
+		idx.last = graph.Edges[idx.num-1]
+	}
+	return
+}
+
+// add adds an edge to the index
+func (idx *graphIndex) add(edge *Edge) {
+	pred := edge.Pred.String()
+	idx.s[edge.Subject] = append(idx.s[edge.Subject], edge)
+	idx.p[pred] = append(idx.p[pred], edge)
+	idx.o[edge.Object] = append(idx.o[edge.Object], edge)
+	if idx.spo[edge.Subject] == nil {
+		idx.spo[edge.Subject] = make(map[string][]*Edge)
+	}
+	idx.spo[edge.Subject][pred] = append(idx.spo[edge.Subject][pred], edge)
+	if idx.pos[pred] == nil {
+		idx.pos[pred] = make(map[*Node][]*Edge)
+	}
+	idx.pos[pred][edge.Object] = append(idx.pos[pred][edge.Object], edge)
+	if idx.osp[edge.Object] == nil {
+		idx.osp[edge.Object] = make(map[*Node][]*Edge)
+	}
+	idx.osp[edge.Object][edge.Subject] = append(idx.osp[edge.Object][edge.Subject], edge)
+}
+
+// remove updates the index after num edges have been removed from the edges of the graph; the
+// index is dropped if the removed edges outnumber the remaining ones
+func (idx *graphIndex) remove(graph *Graph, num int) {
+	idx.dead += num
+	idx.num = len(graph.Edges)
+	idx.last = nil
+	if idx.num > 0 {
+		idx.last = graph.Edges[idx.num-1]
+	}
+	if idx.dead > idx.num {
+		graph.index = nil
+	}
+}
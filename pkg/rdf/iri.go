@@ -0,0 +1,196 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"regexp"
+	"strings"
+)
+
+// iriRegexp splits an iri reference into its components (RFC 3986, appendix B)
+var iriRegexp = regexp.MustCompile(`^(?:([^:/?#]+):)?(?://([^/?#]*))?([^?#]*)(?:\?([^#]*))?(?:#(.*))?$`)
+
+// iriParts are the components of an iri reference; the flags denote defined components (e.g. an
+// empty query is different from no query)
+type iriParts struct {
+	scheme, authority, path, query, fragment       string
+	hasScheme, hasAuthority, hasQuery, hasFragment bool
+}
+
+// splitIRI splits an iri reference into its components
+func splitIRI(iri string) (parts iriParts) {
+	m := iriRegexp.FindStringSubmatchIndex(iri)
+	if m == nil {
+		parts.path = iri
+		return
+	}
+	component := func(i int) (str string, ok bool) {
+		if m[2*i] >= 0 {
+			str, ok = iri[m[2*i]:m[2*i+1]], true
+		}
+		return
+	}
+	parts.scheme, parts.hasScheme = component(1)
+	parts.authority, parts.hasAuthority = component(2)
+	parts.path, _ = component(3)
+	parts.query, parts.hasQuery = component(4)
+	parts.fragment, parts.hasFragment = component(5)
+	return
+}
+
+// String recomposes the iri reference (RFC 3986, section 5.3)
+func (parts iriParts) String() (iri string) {
+	if parts.hasScheme {
+		iri += parts.scheme + ":"
+	}
+	if parts.hasAuthority {
+		iri += "//" + parts.authority
+	}
+	iri += parts.path
+	if parts.hasQuery {
+		iri += "?" + parts.query
+	}
+	if parts.hasFragment {
+		iri += "#" + parts.fragment
+	}
+	return
+}
+
+// ResolveIRI resolves a (relative) iri reference against a base iri (RFC 3986, section 5.2)
+func ResolveIRI(base string, ref string) (iri string) {
+	iri = resolveIRI(base, ref)
+	return
+}
+
+// resolveIRI resolves a (relative) iri reference against a base iri (RFC 3986, section 5.2);
+// absolute iris and references without base are returned unchanged
+func resolveIRI(base string, ref string) (iri string) {
+	iri = ref
+	if base == "" {
+		return
+	}
+	r := splitIRI(ref)
+	if r.hasScheme {
+		return
+	}
+	b := splitIRI(base)
+	t := iriParts{scheme: b.scheme, hasScheme: b.hasScheme, fragment: r.fragment,
+		hasFragment: r.hasFragment}
+	switch {
+	case r.hasAuthority:
+		t.authority, t.hasAuthority = r.authority, true
+		t.path = removeDotSegments(r.path)
+		t.query, t.hasQuery = r.query, r.hasQuery
+	case r.path == "":
+		t.authority, t.hasAuthority = b.authority, b.hasAuthority
+		t.path = b.path
+		t.query, t.hasQuery = b.query, b.hasQuery
+		if r.hasQuery {
+			t.query = r.query
+		}
+	default:
+		t.authority, t.hasAuthority = b.authority, b.hasAuthority
+		t.query, t.hasQuery = r.query, r.hasQuery
+		if strings.HasPrefix(r.path, "/") {
+			t.path = removeDotSegments(r.path)
+		} else {
+			t.path = removeDotSegments(mergePaths(b, r.path))
+		}
+	}
+	iri = t.String()
+	return
+}
+
+// mergePaths merges a relative path with the path of the base iri (RFC 3986, section 5.2.3)
+func mergePaths(base iriParts, path string) (merged string) {
+	if base.hasAuthority && base.path == "" {
+		merged = "/" + path
+		return
+	}
+	merged = base.path[:strings.LastIndex(base.path, "/")+1] + path
+	return
+}
+
+// removeDotSegments removes the segments "." and ".." from a path (RFC 3986, section 5.2.4)
+func removeDotSegments(path string) (out string) {
+	var segments []string
+	in := path
+	for in != "" {
+		switch {
+		case strings.HasPrefix(in, "../"):
+			in = in[3:]
+		case strings.HasPrefix(in, "./"):
+			in = in[2:]
+		case strings.HasPrefix(in, "/./"):
+			in = in[2:]
+		case in == "/.":
+			in = "/"
+		case strings.HasPrefix(in, "/../"):
+			in = in[3:]
+			if len(segments) > 0 {
+				segments = segments[:len(segments)-1]
+			}
+		case in == "/..":
+			in = "/"
+			if len(segments) > 0 {
+				segments = segments[:len(segments)-1]
+			}
+		case in == "." || in == "..":
+			in = ""
+		default:
+			// move the first segment (including an initial "/") to the output
+			i := strings.Index(in[1:], "/")
+			if i < 0 {
+				segments = append(segments, in)
+				in = ""
+			} else {
+				segments = append(segments, in[:i+1])
+				in = in[i+1:]
+			}
+		}
+	}
+	out = strings.Join(segments, "")
+	return
+}
@@ -47,14 +47,28 @@ package rdf
 import (
 	"encoding/json"
 	"io"
-	"os"
 	"strings"
 
 	"github.com/piprate/json-gold/ld"
 )
 
-// DecodeJSONLD decodes a jsonld input to rdf triples
-func DecodeJSONLD(input io.Reader) (trip []Triple, err error) {
+// DecodeJSONLD decodes a jsonld input to rdf triples. Triples of named graphs are merged into the
+// result; use DecodeJSONLDQuads to keep the graph names. Optional decode options set the loader
+// for remote contexts.
+func DecodeJSONLD(input io.Reader, opts ...DecodeOptions) (trip []Triple, err error) {
+	var quad []Quad
+	quad, err = DecodeJSONLDQuads(input, opts...)
+	if err != nil {
+		return
+	}
+	trip = quadsToTriples(quad)
+	return
+}
+
+// DecodeJSONLDQuads decodes a jsonld input to rdf quads; optional decode options set the base iri
+// of the document and the loader for remote contexts
+func DecodeJSONLDQuads(input io.Reader, opts ...DecodeOptions) (quad []Quad, err error) {
+	opt := decodeOptions(opts)
 	jsonDec := json.NewDecoder(input)
 	var doc interface{}
 	err = jsonDec.Decode(&doc)
@@ -63,35 +77,107 @@ func DecodeJSONLD(input io.Reader) (trip []Triple, err error) {
 	}
 
 	proc := ld.NewJsonLdProcessor()
-	options := ld.NewJsonLdOptions("")
+	options := ld.NewJsonLdOptions(opt.Base)
 	options.Format = "application/n-quads"
+	if opt.DocumentLoader != nil {
+		options.DocumentLoader = opt.DocumentLoader
+	}
 	tripleString, err := proc.ToRDF(doc, options)
 	if err != nil {
 		return
 	}
-	var file *os.File
-	file, err = os.Create("triples.ttl")
-	file.Write([]byte(tripleString.(string)))
-	file.Close()
 	r := strings.NewReader(tripleString.(string))
-	trip, err = DecodeTTL(r)
+	quad, err = DecodeNQuads(r)
 	return
 }
 
-// EncodeJSONLD encodes triples in json ld format
-func EncodeJSONLD(triple []Triple, output io.Writer) (err error) {
-	prefix := make(map[string]string)
-	tripleString := ""
+// EncodeJSONLD encodes triples in json ld format; optional encode options are used to compact or
+// frame the output
+func EncodeJSONLD(triple []Triple, output io.Writer, opts ...EncodeOptions) (err error) {
+	quad := make([]Quad, len(triple))
 	for i := range triple {
-		tripleString += triple[i].SerializeTTL(prefix) + "\n"
+		quad[i].Triple = triple[i]
+	}
+	err = EncodeJSONLDQuads(quad, output, opts...)
+	return
+}
+
+// EncodeJSONLDQuads encodes quads in json ld format (named graphs are written as @graph);
+// optional encode options are used to compact or frame the output
+func EncodeJSONLDQuads(quad []Quad, output io.Writer, opts ...EncodeOptions) (err error) {
+	opt := encodeOptions(opts)
+	var b strings.Builder
+	for i := range quad {
+		b.WriteString(quad[i].SerializeNQuads() + "\n")
 	}
 	proc := ld.NewJsonLdProcessor()
 	options := ld.NewJsonLdOptions("")
-	doc, err := proc.FromRDF(tripleString, options)
+	options.Format = "application/n-quads"
+	if opt.DocumentLoader != nil {
+		options.DocumentLoader = opt.DocumentLoader
+	}
+	doc, err := proc.FromRDF(b.String(), options)
 	if err != nil {
 		return
 	}
+	context := opt.jsonldContext()
+	if opt.Frame != nil {
+		frame := make(map[string]interface{})
+		for i := range opt.Frame {
+			frame[i] = opt.Frame[i]
+		}
+		frameContext, ok := frame["@context"]
+		if !ok {
+			frameContext = context
+			frame["@context"] = context
+		}
+		// the frame is expanded first and the framed document is compacted afterwards (framing
+		// with a context compacts the iris of the context itself)
+		var expanded []interface{}
+		expanded, err = proc.Expand(frame, options)
+		if err != nil {
+			return
+		}
+		frame = make(map[string]interface{})
+		if len(expanded) > 0 {
+			frame, _ = expanded[0].(map[string]interface{})
+		}
+		doc, err = proc.Frame(doc, frame, options)
+		if err != nil {
+			return
+		}
+		options.Base = opt.Base
+		doc, err = proc.Compact(doc, frameContext, options)
+		if err != nil {
+			return
+		}
+	} else if len(context) > 0 {
+		options.Base = opt.Base
+		doc, err = proc.Compact(doc, context, options)
+		if err != nil {
+			return
+		}
+	}
 	jsonEnc := json.NewEncoder(output)
 	err = jsonEnc.Encode(doc)
 	return
 }
+
+// jsonldContext creates the jsonld context from the context, the prefixes and the base iri of the
+// encode options
+func (opt EncodeOptions) jsonldContext() (context map[string]interface{}) {
+	context = make(map[string]interface{})
+	for i := range opt.Prefixes {
+		// empty prefix names are no valid json ld terms
+		if i != "" {
+			context[i] = opt.Prefixes[i]
+		}
+	}
+	for i := range opt.Context {
+		context[i] = opt.Context[i]
+	}
+	if opt.Base != "" {
+		context["@base"] = opt.Base
+	}
+	return
+}
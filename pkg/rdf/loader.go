@@ -0,0 +1,144 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/piprate/json-gold/ld"
+)
+
+// DecodeOptions are optional settings for the decoders
+type DecodeOptions struct {
+	Base           string            // base iri of the document (e.g. its url)
+	DocumentLoader ld.DocumentLoader // loader for remote jsonld documents (nil: http requests)
+}
+
+// decodeOptions returns the first of the optional decode options
+func decodeOptions(opts []DecodeOptions) (opt DecodeOptions) {
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return
+}
+
+// FileDocumentLoader loads jsonld documents (e.g. remote contexts) from a file system instead of
+// requesting them via http; documents that are not found are loaded by the fallback loader or
+// cause an error if there is none
+type FileDocumentLoader struct {
+	files    fs.FS             // file system with cached documents
+	names    map[string]string // explicit mapping of document urls to file names
+	fallback ld.DocumentLoader // loader for documents that are not in the file system
+}
+
+// NewFileDocumentLoader creates a document loader for the documents in the file system (e.g. an
+// embed.FS); fallback may be nil
+func NewFileDocumentLoader(files fs.FS, fallback ld.DocumentLoader) (loader *FileDocumentLoader) {
+	loader = &FileDocumentLoader{files: files, names: make(map[string]string), fallback: fallback}
+	return
+}
+
+// NewDirDocumentLoader creates a document loader for the documents in a directory; fallback may
+// be nil
+func NewDirDocumentLoader(dir string, fallback ld.DocumentLoader) (loader *FileDocumentLoader) {
+	loader = NewFileDocumentLoader(os.DirFS(dir), fallback)
+	return
+}
+
+// AddDocument maps a document url to a file name in the file system (a slash separated path
+// without leading slash, see fs.ValidPath)
+func (loader *FileDocumentLoader) AddDocument(u string, name string) {
+	loader.names[u] = name
+}
+
+// LoadDocument loads the document with the specified url; the file is either the one added with
+// AddDocument or host and path of the url (e.g. "schema.org/docs/jsonldcontext.json"), with
+// "index.jsonld" appended for urls ending with '/' and ".jsonld" tried as extension
+func (loader *FileDocumentLoader) LoadDocument(u string) (doc *ld.RemoteDocument, err error) {
+	for _, name := range loader.fileNames(u) {
+		var file fs.File
+		file, err = loader.files.Open(name)
+		if err != nil {
+			continue
+		}
+		var document interface{}
+		document, err = ld.DocumentFromReader(file)
+		file.Close()
+		if err != nil {
+			return
+		}
+		doc = &ld.RemoteDocument{DocumentURL: u, Document: document}
+		return
+	}
+	if loader.fallback != nil {
+		doc, err = loader.fallback.LoadDocument(u)
+		return
+	}
+	err = ld.NewJsonLdError(ld.LoadingDocumentFailed, errors.New("document "+u+" not cached"))
+	return
+}
+
+// fileNames returns the possible file names of a document url
+func (loader *FileDocumentLoader) fileNames(u string) (names []string) {
+	if name, ok := loader.names[u]; ok {
+		names = append(names, name)
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return
+	}
+	name := strings.TrimPrefix(path.Join("/", parsed.Host, parsed.Path), "/")
+	if strings.HasSuffix(parsed.Path, "/") || parsed.Path == "" {
+		names = append(names, path.Join(name, "index.jsonld"))
+		return
+	}
+	names = append(names, name, name+".jsonld")
+	return
+}
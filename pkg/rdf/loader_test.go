@@ -0,0 +1,84 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFileDocumentLoader(t *testing.T) {
+	context := func(iri string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(`{"@context": {"name": "` + iri + `"}}`)}
+	}
+	files := fstest.MapFS{
+		"example.org/context.jsonld": context("http://example.org/a"),
+		"other.json":                 context("http://example.org/b"),
+	}
+	loader := NewFileDocumentLoader(files, nil)
+	loader.AddDocument("http://example.com/ctx", "other.json")
+	tests := []struct {
+		context string
+		want    string
+	}{
+		{"http://example.org/context", "http://example.org/a"},
+		{"http://example.com/ctx", "http://example.org/b"},
+	}
+	for _, test := range tests {
+		input := `{"@context": "` + test.context + `", "@id": "http://example.org/s", "name": "x"}`
+		trips, err := DecodeJSONLD(strings.NewReader(input), DecodeOptions{DocumentLoader: loader})
+		if err != nil {
+			t.Fatalf("%s: %v", test.context, err)
+		}
+		if len(trips) != 1 || trips[0].Pred.String() != test.want {
+			t.Errorf("%s: got %v, want predicate %s", test.context, trips, test.want)
+		}
+	}
+	_, err := loader.LoadDocument("http://example.org/missing")
+	if err == nil {
+		t.Error("expected error for missing document")
+	}
+}
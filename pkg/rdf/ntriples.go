@@ -0,0 +1,559 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ntParser parses line based rdf documents (n-triples and n-quads)
+type ntParser struct {
+	reader    *bufio.Reader        // reader of document
+	line      string               // current line
+	lineNum   int                  // number of current line
+	pos       int                  // byte position in current line
+	quads     bool                 // allow graph label (n-quads)
+	bnCounter int                  // blank node counter
+	blank     map[string]BlankNode // blankNode map
+	keepLabel bool                 // keep the labels of blank nodes (rdf patch)
+}
+
+// DecodeNTriples decodes a n-triples input to rdf triples
+func DecodeNTriples(input io.Reader) (trip []Triple, err error) {
+	p := newNTParser(input, false)
+	for {
+		var t Triple
+		var ok bool
+		t, _, ok, err = p.next()
+		if err != nil || !ok {
+			break
+		}
+		trip = append(trip, t)
+	}
+	return
+}
+
+// DecodeNQuads decodes a n-quads input to rdf quads
+func DecodeNQuads(input io.Reader) (quad []Quad, err error) {
+	p := newNTParser(input, true)
+	for {
+		var q Quad
+		var ok bool
+		q.Triple, q.Graph, ok, err = p.next()
+		if err != nil || !ok {
+			break
+		}
+		quad = append(quad, q)
+	}
+	return
+}
+
+// EncodeNTriples serializes triples in n-triples format
+func EncodeNTriples(triple []Triple, output io.Writer) (err error) {
+	w := bufio.NewWriter(output)
+	for i := range triple {
+		_, err = w.WriteString(triple[i].SerializeNTriples() + "\n")
+		if err != nil {
+			return
+		}
+	}
+	err = w.Flush()
+	return
+}
+
+// EncodeNQuads serializes quads in n-quads format
+func EncodeNQuads(quad []Quad, output io.Writer) (err error) {
+	w := bufio.NewWriter(output)
+	for i := range quad {
+		_, err = w.WriteString(quad[i].SerializeNQuads() + "\n")
+		if err != nil {
+			return
+		}
+	}
+	err = w.Flush()
+	return
+}
+
+// SerializeNTriples serializes a single Triple in n-triples format
+func (trip Triple) SerializeNTriples() (ret string) {
+	ret = serializeNTerm(trip.Sub) + " " + serializeNTerm(trip.Pred) + " " +
+		serializeNTerm(trip.Obj) + " ."
+	return
+}
+
+// serializeNTerm serializes a term in n-triples format (no prefixes, escaped strings)
+func serializeNTerm(term Term) (ret string) {
+	switch t := term.(type) {
+	case IRI:
+		ret = "<" + escapeIRI(t.name) + ">"
+	case BlankNode:
+		ret = "_:" + t.name
+	case Literal:
+		ret = "\"" + escapeString(t.str) + "\""
+		if t.langTag != "" {
+			ret += "@" + t.langTag
+		} else if t.typeIRI != "" && t.typeIRI != XsdString {
+			ret += "^^<" + escapeIRI(t.typeIRI) + ">"
+		}
+	default:
+		ret = term.String()
+	}
+	return
+}
+
+// escapeString escapes a literal string according to the n-triples grammar
+func escapeString(in string) (out string) {
+	var b strings.Builder
+	for _, r := range in {
+		switch r {
+		case '\\':
+			b.WriteString("\\\\")
+		case '"':
+			b.WriteString("\\\"")
+		case '\n':
+			b.WriteString("\\n")
+		case '\r':
+			b.WriteString("\\r")
+		case '\t':
+			b.WriteString("\\t")
+		case '\b':
+			b.WriteString("\\b")
+		case '\f':
+			b.WriteString("\\f")
+		default:
+			if r < 0x20 || r == 0x7f {
+				b.WriteString(uchar(r))
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	out = b.String()
+	return
+}
+
+// escapeIRI escapes all characters that are not allowed in an IRIREF
+func escapeIRI(in string) (out string) {
+	var b strings.Builder
+	for _, r := range in {
+		if r <= 0x20 || strings.ContainsRune("<>\"{}|^`\\", r) {
+			b.WriteString(uchar(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	out = b.String()
+	return
+}
+
+// uchar returns the \u or \U escape sequence of a rune
+func uchar(r rune) (ret string) {
+	hex := strings.ToUpper(strconv.FormatInt(int64(r), 16))
+	if r > 0xffff {
+		ret = "\\U" + strings.Repeat("0", 8-len(hex)) + hex
+	} else {
+		ret = "\\u" + strings.Repeat("0", 4-len(hex)) + hex
+	}
+	return
+}
+
+// newNTParser creates a parser for n-triples (quads = false) or n-quads (quads = true)
+func newNTParser(input io.Reader, quads bool) (p *ntParser) {
+	p = &ntParser{reader: bufio.NewReaderSize(input, 1<<16), quads: quads,
+		blank: make(map[string]BlankNode)}
+	return
+}
+
+// next returns the next statement of the document. ok is false if the end of the document has
+// been reached.
+func (p *ntParser) next() (trip Triple, graph Term, ok bool, err error) {
+	for {
+		var line string
+		line, err = p.reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			err = errors.New("Error reading line " + strconv.Itoa(p.lineNum+1) + ": " +
+				err.Error())
+			return
+		}
+		eof := err == io.EOF
+		err = nil
+		if line == "" && eof {
+			return
+		}
+		p.lineNum++
+		p.line = strings.TrimRight(line, "\r\n")
+		p.pos = 0
+		p.skipWS()
+		if p.pos >= len(p.line) || p.line[p.pos] == '#' {
+			if eof {
+				return
+			}
+			continue
+		}
+		trip, graph, err = p.parseStatement()
+		if err != nil {
+			return
+		}
+		ok = true
+		return
+	}
+}
+
+// parseStatement parses subject predicate object graphLabel? '.' of the current line
+func (p *ntParser) parseStatement() (trip Triple, graph Term, err error) {
+	// subject
+	switch p.peek() {
+	case '<':
+		trip.Sub, err = p.parseIRI()
+	case '_':
+		trip.Sub, err = p.parseBlankNode()
+	default:
+		err = p.errorf("expected IRI or blank node as subject")
+	}
+	if err != nil {
+		return
+	}
+	p.skipWS()
+
+	// predicate
+	if p.peek() != '<' {
+		err = p.errorf("expected IRI as predicate")
+		return
+	}
+	trip.Pred, err = p.parseIRI()
+	if err != nil {
+		return
+	}
+	p.skipWS()
+
+	// object
+	switch p.peek() {
+	case '<':
+		trip.Obj, err = p.parseIRI()
+	case '_':
+		trip.Obj, err = p.parseBlankNode()
+	case '"':
+		trip.Obj, err = p.parseLiteral()
+	default:
+		err = p.errorf("expected IRI, blank node or literal as object")
+	}
+	if err != nil {
+		return
+	}
+	p.skipWS()
+
+	// graph label
+	if p.quads {
+		switch p.peek() {
+		case '<':
+			graph, err = p.parseIRI()
+		case '_':
+			graph, err = p.parseBlankNode()
+		}
+		if err != nil {
+			return
+		}
+		p.skipWS()
+	}
+
+	// dot
+	if p.peek() != '.' {
+		err = p.errorf("expected '.' at end of statement")
+		return
+	}
+	p.pos++
+	p.skipWS()
+	if p.pos < len(p.line) && p.line[p.pos] != '#' {
+		err = p.errorf("unexpected content after end of statement")
+	}
+	return
+}
+
+// parseTerm parses an IRI, blank node or literal; what is used in the error message
+func (p *ntParser) parseTerm(what string) (term Term, err error) {
+	switch p.peek() {
+	case '<':
+		term, err = p.parseIRI()
+	case '_':
+		term, err = p.parseBlankNode()
+	case '"':
+		term, err = p.parseLiteral()
+	default:
+		err = p.errorf("expected IRI, blank node or literal as " + what)
+	}
+	return
+}
+
+// parseIRI parses an IRIREF ('<' iri '>')
+func (p *ntParser) parseIRI() (iri IRI, err error) {
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.pos >= len(p.line) {
+			err = p.errorf("missing '>' at end of IRI")
+			return
+		}
+		c := p.line[p.pos]
+		switch {
+		case c == '>':
+			p.pos++
+			iri = IRI{name: b.String()}
+			if !strings.Contains(iri.name, ":") {
+				err = p.errorf("relative IRI <" + iri.name + "> not allowed")
+			}
+			return
+		case c == '\\':
+			var r rune
+			r, err = p.parseUChar()
+			if err != nil {
+				return
+			}
+			b.WriteRune(r)
+		case c <= 0x20 || strings.IndexByte("<\"{}|^`", c) >= 0:
+			err = p.errorf("invalid character " + strconv.QuoteRune(rune(c)) + " in IRI")
+			return
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// parseBlankNode parses a blank node label ('_:' label)
+func (p *ntParser) parseBlankNode() (blank BlankNode, err error) {
+	if !strings.HasPrefix(p.line[p.pos:], "_:") {
+		err = p.errorf("expected '_:' at beginning of blank node")
+		return
+	}
+	p.pos += 2
+	start := p.pos
+	for p.pos < len(p.line) {
+		r, s := utf8.DecodeRuneInString(p.line[p.pos:])
+		if r == ' ' || r == '\t' || r == '<' || r == '"' || r == '#' {
+			break
+		}
+		p.pos += s
+	}
+	// a label must not end with a dot
+	for p.pos > start && p.line[p.pos-1] == '.' {
+		p.pos--
+	}
+	label := p.line[start:p.pos]
+	if label == "" {
+		err = p.errorf("empty blank node label")
+		return
+	}
+	if p.keepLabel {
+		blank = BlankNode{name: label}
+		return
+	}
+	var ok bool
+	blank, ok = p.blank[label]
+	if !ok {
+		blank = BlankNode{name: "bn" + strconv.Itoa(p.bnCounter)}
+		p.bnCounter++
+		p.blank[label] = blank
+	}
+	return
+}
+
+// parseLiteral parses a literal ('"' string '"' (LANGTAG | '^^' IRIREF)?)
+func (p *ntParser) parseLiteral() (lit Literal, err error) {
+	p.pos++
+	var b strings.Builder
+	closed := false
+	for p.pos < len(p.line) && !closed {
+		c := p.line[p.pos]
+		switch c {
+		case '"':
+			closed = true
+			p.pos++
+		case '\\':
+			var r rune
+			r, err = p.parseEChar()
+			if err != nil {
+				return
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	if !closed {
+		err = p.errorf("missing '\"' at end of literal")
+		return
+	}
+	lit.str = b.String()
+	if p.pos >= len(p.line) {
+		return
+	}
+	switch p.line[p.pos] {
+	case '@':
+		p.pos++
+		start := p.pos
+		for p.pos < len(p.line) && (isAlpha(rune(p.line[p.pos])) ||
+			(p.pos > start && (p.line[p.pos] == '-' || isDigit(rune(p.line[p.pos]))))) {
+			p.pos++
+		}
+		lit.langTag = p.line[start:p.pos]
+		if lit.langTag == "" || strings.HasSuffix(lit.langTag, "-") {
+			err = p.errorf("invalid language tag")
+			return
+		}
+	case '^':
+		if !strings.HasPrefix(p.line[p.pos:], "^^<") {
+			err = p.errorf("expected '^^<' before datatype IRI")
+			return
+		}
+		p.pos += 2
+		var typ IRI
+		typ, err = p.parseIRI()
+		if err != nil {
+			return
+		}
+		lit.typeIRI = typ.name
+	}
+	return
+}
+
+// parseEChar parses an escape sequence in a string
+func (p *ntParser) parseEChar() (r rune, err error) {
+	if p.pos+1 >= len(p.line) {
+		err = p.errorf("incomplete escape sequence")
+		return
+	}
+	switch p.line[p.pos+1] {
+	case 't':
+		r = '\t'
+	case 'b':
+		r = '\b'
+	case 'n':
+		r = '\n'
+	case 'r':
+		r = '\r'
+	case 'f':
+		r = '\f'
+	case '"':
+		r = '"'
+	case '\'':
+		r = '\''
+	case '\\':
+		r = '\\'
+	case 'u', 'U':
+		r, err = p.parseUChar()
+		return
+	default:
+		err = p.errorf("invalid escape sequence \\" + string(p.line[p.pos+1]))
+		return
+	}
+	p.pos += 2
+	return
+}
+
+// parseUChar parses a \uXXXX or \UXXXXXXXX escape sequence
+func (p *ntParser) parseUChar() (r rune, err error) {
+	length := 0
+	if p.pos+1 < len(p.line) {
+		switch p.line[p.pos+1] {
+		case 'u':
+			length = 4
+		case 'U':
+			length = 8
+		}
+	}
+	if length == 0 || p.pos+2+length > len(p.line) {
+		err = p.errorf("invalid unicode escape sequence")
+		return
+	}
+	var code uint64
+	code, err = strconv.ParseUint(p.line[p.pos+2:p.pos+2+length], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		err = p.errorf("invalid unicode escape sequence")
+		return
+	}
+	r = rune(code)
+	p.pos += 2 + length
+	return
+}
+
+// peek returns the byte at the current position or 0 at the end of the line
+func (p *ntParser) peek() (c byte) {
+	if p.pos < len(p.line) {
+		c = p.line[p.pos]
+	}
+	return
+}
+
+// skipWS skips spaces and tabs
+func (p *ntParser) skipWS() {
+	for p.pos < len(p.line) && (p.line[p.pos] == ' ' || p.line[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// errorf returns an error containing the current line number and column
+func (p *ntParser) errorf(msg string) (err error) {
+	err = errors.New("line " + strconv.Itoa(p.lineNum) + ", column " + strconv.Itoa(p.pos+1) +
+		": " + msg)
+	return
+}
+
+// isAlpha checks if c is an ascii letter
+func isAlpha(c rune) (ok bool) {
+	ok = (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+	return
+}
+
+// isDigit checks if c is an ascii digit
+func isDigit(c rune) (ok bool) {
+	ok = c >= '0' && c <= '9'
+	return
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/piprate/json-gold/ld"
+)
+
+// PrefixMap maps prefix names to namespace iris (e.g. "owl" -> "http://www.w3.org/2002/07/owl#")
+type PrefixMap map[string]string
+
+// EncodeOptions are optional settings for the encoders
+type EncodeOptions struct {
+	Prefixes PrefixMap              // prefixes which are used instead of generated ones
+	Base     string                 // base iri (iris are written relative to base if possible)
+	Context  map[string]interface{} // jsonld context for compaction (prefixes are added)
+	Frame    map[string]interface{} // jsonld frame (Context is used if frame has no @context)
+
+	DocumentLoader ld.DocumentLoader // loader for remote jsonld contexts (nil: http requests)
+}
+
+// encodeOptions returns the first of the optional encode options
+func encodeOptions(opts []EncodeOptions) (opt EncodeOptions) {
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return
+}
+
+// prefixes determines the prefixes (namespace iri -> prefix name) for the triples and the
+// additional iris; user supplied prefixes replace generated prefixes and no prefixes are
+// generated for namespaces that can be written relative to the base iri
+func (opt EncodeOptions) prefixes(triple []Triple, extra ...string) (prefix map[string]string) {
+	prefix = collectPrefixes(triple, func(ns string) (skip bool) {
+		_, skip = opt.relativeIRI(ns)
+		return
+	}, extra...)
+	names := make([]string, 0, len(opt.Prefixes))
+	for i := range opt.Prefixes {
+		names = append(names, i)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for i := range prefix {
+			if prefix[i] == name {
+				delete(prefix, i)
+			}
+		}
+		prefix[opt.Prefixes[name]] = name
+	}
+	return
+}
+
+// writeBase writes the base directive
+func (opt EncodeOptions) writeBase(output io.Writer) {
+	if opt.Base != "" {
+		output.Write([]byte("@base <" + escapeIRI(opt.Base) + "> .\n"))
+	}
+}
+
+// relativeIRI returns the iri relative to the document or the directory of the base iri if
+// the relative iri resolves to the same iri
+func (opt EncodeOptions) relativeIRI(name string) (rel string, ok bool) {
+	if opt.Base == "" {
+		return
+	}
+	doc := opt.Base
+	if i := strings.IndexByte(doc, '#'); i >= 0 {
+		doc = doc[:i]
+	}
+	for _, pre := range []string{doc, doc[:strings.LastIndexByte(doc, '/')+1]} {
+		rel = strings.TrimPrefix(name, pre)
+		if pre != "" && rel != name && resolveIRI(opt.Base, rel) == name {
+			ok = true
+			return
+		}
+	}
+	rel = ""
+	return
+}
+
+// serializeTTL serializes a term in ttl format; iris and datatypes without prefix are written
+// relative to the base iri if possible
+func (opt EncodeOptions) serializeTTL(term Term, prefix map[string]string) (ret string) {
+	ret = term.SerializeTTL(prefix)
+	switch {
+	case opt.Base == "":
+	case term.Type() == TermIRI && strings.HasPrefix(ret, "<"):
+		if rel, ok := opt.relativeIRI(term.String()); ok {
+			ret = "<" + escapeIRI(rel) + ">"
+		}
+	case term.Type() == TermLiteral && strings.HasSuffix(ret, ">"):
+		lit := term.(Literal)
+		if rel, ok := opt.relativeIRI(lit.typeIRI); ok {
+			ret = strings.TrimSuffix(ret, "<"+escapeIRI(lit.typeIRI)+">") + "<" +
+				escapeIRI(rel) + ">"
+		}
+	}
+	return
+}
+
+// serializeTripleTTL serializes a single triple in ttl format
+func (opt EncodeOptions) serializeTripleTTL(trip Triple, prefix map[string]string) (ret string) {
+	ret = opt.serializeTTL(trip.Sub, prefix) + " " + opt.serializeTTL(trip.Pred, prefix) + " " +
+		opt.serializeTTL(trip.Obj, prefix) + " ."
+	return
+}
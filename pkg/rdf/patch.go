@@ -0,0 +1,326 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// PatchOp is the operation of a row of a rdf patch
+type PatchOp int
+
+// possible patch operations
+const (
+	PatchAdd          PatchOp = iota // A: add a triple or quad
+	PatchDelete                      // D: delete a triple or quad
+	PatchAddPrefix                   // PA: add a prefix
+	PatchDeletePrefix                // PD: delete a prefix
+	PatchBegin                       // TX: begin a transaction
+	PatchCommit                      // TC: commit a transaction
+	PatchAbort                       // TA: abort a transaction
+)
+
+// patchOps are the keywords of the patch operations
+var patchOps = map[PatchOp]string{PatchAdd: "A", PatchDelete: "D", PatchAddPrefix: "PA",
+	PatchDeletePrefix: "PD", PatchBegin: "TX", PatchCommit: "TC", PatchAbort: "TA"}
+
+// PatchHeader is a header entry of a rdf patch (e.g. id or prev)
+type PatchHeader struct {
+	Key   string
+	Value Term
+}
+
+// PatchRow is a change of a rdf patch
+type PatchRow struct {
+	Op     PatchOp
+	Quad   Quad   // added or deleted quad (A, D)
+	Prefix string // prefix (PA, PD)
+	IRI    string // namespace of the prefix (PA)
+}
+
+// Patch is a change set in the RDF Patch format. Blank nodes are identified by their labels, i.e.
+// a patch refers to the blank nodes of the graph it is applied to.
+type Patch struct {
+	Header []PatchHeader
+	Rows   []PatchRow
+}
+
+// String returns the keyword of the operation
+func (op PatchOp) String() (str string) {
+	str, ok := patchOps[op]
+	if !ok {
+		str = "PatchOp(" + strconv.Itoa(int(op)) + ")"
+	}
+	return
+}
+
+// NewPatch creates a patch that deletes the removed triples and adds the added triples in one
+// transaction (e.g. the result of Diff)
+func NewPatch(added, removed []Triple) (patch Patch) {
+	patch.Rows = append(patch.Rows, PatchRow{Op: PatchBegin})
+	for i := range removed {
+		patch.Rows = append(patch.Rows, PatchRow{Op: PatchDelete, Quad: Quad{Triple: removed[i]}})
+	}
+	for i := range added {
+		patch.Rows = append(patch.Rows, PatchRow{Op: PatchAdd, Quad: Quad{Triple: added[i]}})
+	}
+	patch.Rows = append(patch.Rows, PatchRow{Op: PatchCommit})
+	return
+}
+
+// EncodePatch serializes a patch in the RDF Patch text format
+func EncodePatch(patch Patch, output io.Writer) (err error) {
+	w := bufio.NewWriter(output)
+	for _, h := range patch.Header {
+		_, err = w.WriteString("H " + h.Key + " " + serializeNTerm(h.Value) + " .\n")
+		if err != nil {
+			return
+		}
+	}
+	for _, row := range patch.Rows {
+		line := row.Op.String()
+		switch row.Op {
+		case PatchAdd, PatchDelete:
+			line += " " + strings.TrimSuffix(row.Quad.SerializeNQuads(), " .")
+		case PatchAddPrefix:
+			line += " " + row.Prefix + " <" + escapeIRI(row.IRI) + ">"
+		case PatchDeletePrefix:
+			line += " " + row.Prefix
+		}
+		if _, err = w.WriteString(line + " .\n"); err != nil {
+			return
+		}
+	}
+	err = w.Flush()
+	return
+}
+
+// DecodePatch decodes a patch in the RDF Patch text format; terms are written as in n-quads
+func DecodePatch(input io.Reader) (patch Patch, err error) {
+	p := newNTParser(input, true)
+	p.keepLabel = true
+	ops := make(map[string]PatchOp, len(patchOps))
+	for op, keyword := range patchOps {
+		ops[keyword] = op
+	}
+	for {
+		var line string
+		line, err = p.reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			err = errors.New("Error reading line " + strconv.Itoa(p.lineNum+1) + ": " +
+				err.Error())
+			return
+		}
+		eof := err == io.EOF
+		err = nil
+		if line == "" && eof {
+			return
+		}
+		p.lineNum++
+		p.line = strings.TrimRight(line, "\r\n")
+		p.pos = 0
+		p.skipWS()
+		if p.pos < len(p.line) && p.line[p.pos] != '#' {
+			start := p.pos
+			keyword := p.word()
+			op, ok := ops[keyword]
+			switch {
+			case keyword == "H":
+				var h PatchHeader
+				if h, err = p.parsePatchHeader(); err != nil {
+					return
+				}
+				patch.Header = append(patch.Header, h)
+			case !ok:
+				p.pos = start
+				err = p.errorf("unknown patch operation " + strconv.Quote(keyword))
+				return
+			default:
+				row := PatchRow{Op: op}
+				if err = p.parsePatchRow(&row); err != nil {
+					return
+				}
+				patch.Rows = append(patch.Rows, row)
+			}
+		}
+		if eof {
+			return
+		}
+	}
+}
+
+// word parses a sequence of characters up to the next white space
+func (p *ntParser) word() (w string) {
+	start := p.pos
+	for p.pos < len(p.line) && p.line[p.pos] != ' ' && p.line[p.pos] != '\t' {
+		p.pos++
+	}
+	w = p.line[start:p.pos]
+	p.skipWS()
+	return
+}
+
+// parsePatchHeader parses key and value of a header row
+func (p *ntParser) parsePatchHeader() (h PatchHeader, err error) {
+	if h.Key = p.word(); h.Key == "" {
+		err = p.errorf("expected header key")
+		return
+	}
+	if h.Value, err = p.parseTerm("header value"); err != nil {
+		return
+	}
+	err = p.parseRowEnd()
+	return
+}
+
+// parsePatchRow parses the arguments of a patch row; the dot at the end of the row is optional
+// for transaction rows
+func (p *ntParser) parsePatchRow(row *PatchRow) (err error) {
+	switch row.Op {
+	case PatchAdd, PatchDelete:
+		row.Quad.Triple, row.Quad.Graph, err = p.parseStatement()
+		return
+	case PatchAddPrefix:
+		row.Prefix = strings.TrimSuffix(p.word(), ":")
+		if p.peek() != '<' {
+			err = p.errorf("expected IRI of prefix")
+			return
+		}
+		var iri IRI
+		if iri, err = p.parseIRI(); err != nil {
+			return
+		}
+		row.IRI = iri.name
+	case PatchDeletePrefix:
+		row.Prefix = strings.TrimSuffix(p.word(), ":")
+	default:
+		if p.pos >= len(p.line) || p.line[p.pos] == '#' {
+			return
+		}
+	}
+	err = p.parseRowEnd()
+	return
+}
+
+// parseRowEnd parses the dot at the end of a row
+func (p *ntParser) parseRowEnd() (err error) {
+	p.skipWS()
+	if p.peek() != '.' {
+		err = p.errorf("expected '.' at end of row")
+		return
+	}
+	p.pos++
+	p.skipWS()
+	if p.pos < len(p.line) && p.line[p.pos] != '#' {
+		err = p.errorf("unexpected content after end of row")
+	}
+	return
+}
+
+// ApplyPatch applies the changes of a patch to the graph. Rows between TX and TC are applied
+// together, rows between TX and TA are dropped. Adding existing and deleting missing triples has
+// no effect; prefix rows are ignored. The graph is not changed if the patch is invalid (e.g. a
+// transaction is not closed or a quad has a graph name).
+func (graph *Graph) ApplyPatch(patch Patch) (err error) {
+	var apply, tx []PatchRow
+	inTx := false
+	for i, row := range patch.Rows {
+		switch row.Op {
+		case PatchBegin:
+			if inTx {
+				err = errors.New("row " + strconv.Itoa(i+1) + ": nested transaction")
+				return
+			}
+			inTx, tx = true, nil
+		case PatchCommit, PatchAbort:
+			if !inTx {
+				err = errors.New("row " + strconv.Itoa(i+1) + ": " + row.Op.String() +
+					" without transaction")
+				return
+			}
+			if row.Op == PatchCommit {
+				apply = append(apply, tx...)
+			}
+			inTx = false
+		case PatchAdd, PatchDelete:
+			if row.Quad.Graph != nil {
+				err = errors.New("row " + strconv.Itoa(i+1) + ": named graphs are not supported")
+				return
+			}
+			if inTx {
+				tx = append(tx, row)
+			} else {
+				apply = append(apply, row)
+			}
+		}
+	}
+	if inTx {
+		err = errors.New("transaction is not closed")
+		return
+	}
+	graph.applyRows(apply)
+	return
+}
+
+// applyRows adds and deletes the triples of the rows in order; consecutive deletions are removed
+// at once
+func (graph *Graph) applyRows(rows []PatchRow) {
+	var del []Triple
+	for _, row := range rows {
+		if row.Op == PatchDelete {
+			del = append(del, row.Quad.Triple)
+			continue
+		}
+		graph.RemoveTriple(del...)
+		del = del[:0]
+		graph.AddTriple(row.Quad.Triple)
+	}
+	graph.RemoveTriple(del...)
+	return
+}
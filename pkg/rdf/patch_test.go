@@ -0,0 +1,100 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestApplyPatch(t *testing.T) {
+	var oldTrips, newTrips []Triple
+	for i := 0; i < 200; i++ {
+		oldTrips = append(oldTrips, testTriple(i))
+		if i%10 != 0 {
+			newTrips = append(newTrips, testTriple(i))
+		}
+	}
+	for i := 200; i < 220; i++ {
+		newTrips = append(newTrips, testTriple(i))
+	}
+	oldGraph, _ := NewGraph(oldTrips)
+	newGraph, _ := NewGraph(newTrips)
+	var buf bytes.Buffer
+	if err := EncodePatch(NewPatch(Diff(&oldGraph, &newGraph)), &buf); err != nil {
+		t.Fatal(err)
+	}
+	patch, err := DecodePatch(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = oldGraph.ApplyPatch(patch); err != nil {
+		t.Fatal(err)
+	}
+	if !Isomorphic(&oldGraph, &newGraph) {
+		t.Fatalf("patched graph has %d edges, want %d", len(oldGraph.Edges), len(newGraph.Edges))
+	}
+}
+
+func TestApplyPatchOrder(t *testing.T) {
+	input := "A <http://example.com/s> <http://example.com/p> <http://example.com/o> .\n" +
+		"D <http://example.com/s> <http://example.com/p> <http://example.com/o> .\n" +
+		"A <http://example.com/s> <http://example.com/p> <http://example.com/o> .\n" +
+		"A <http://example.com/s> <http://example.com/p> <http://example.com/x> .\n" +
+		"D <http://example.com/s> <http://example.com/p> <http://example.com/x> .\n"
+	patch, err := DecodePatch(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph, _ := NewGraph(nil)
+	if err = graph.ApplyPatch(patch); err != nil {
+		t.Fatal(err)
+	}
+	trips := graph.ToTriples()
+	if len(trips) != 1 || trips[0].Obj.String() != "http://example.com/o" {
+		t.Fatalf("patched graph contains %v", trips)
+	}
+}
@@ -42,8 +42,8 @@ OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
 THE SOFTWARE.
 */
 
-// Package rdf implements functions for serializing/deserializing to/from ttl and json-ld as well
-// as conversion of triples to a graph structure.
+// Package rdf implements functions for serializing/deserializing to/from ttl, n-triples, n-quads and
+// json-ld as well as conversion of triples to a graph structure.
 package rdf
 
 import (
@@ -60,34 +60,87 @@ var (
 	XsdString = "http://www.w3.org/2001/XMLSchema#string"
 	// XsdBoolean bool
 	XsdBoolean = "http://www.w3.org/2001/XMLSchema#boolean"
-	// XsdDecimal float
+	// XsdDecimal Decimal
 	XsdDecimal = "http://www.w3.org/2001/XMLSchema#decimal"
-	// XsdInteger int
+	// XsdInteger *big.Int
 	XsdInteger = "http://www.w3.org/2001/XMLSchema#integer"
-	// XsdDouble float
+	// XsdDouble float64
 	XsdDouble = "http://www.w3.org/2001/XMLSchema#double"
 
-	// XsdTime time
+	// XsdTime Time
 	XsdTime = "http://www.w3.org/2001/XMLSchema#time"
-	// XsdDate time
+	// XsdDate Date
 	XsdDate = "http://www.w3.org/2001/XMLSchema#date"
 	// XsdDateTime time
 	XsdDateTime = "http://www.w3.org/2001/XMLSchema#dateTime"
 	// XsdDateTimeStamp time
 	XsdDateTimeStamp = "http://www.w3.org/2001/XMLSchema#dateTimeStamp"
-	// XsdYear time
+	// XsdYear GYear
 	XsdYear = "http://www.w3.org/2001/XMLSchema#gYear"
-	// XsdMonth time
+	// XsdMonth GMonth
 	XsdMonth = "http://www.w3.org/2001/XMLSchema#gMonth"
-	// XsdDay time
+	// XsdDay GDay
 	XsdDay = "http://www.w3.org/2001/XMLSchema#gDay"
-	// XsdYearMonth time
+	// XsdYearMonth GYearMonth
 	XsdYearMonth = "http://www.w3.org/2001/XMLSchema#gYearMonth"
-	// XsdDuration time
-	XsdDuration = "http://www.w3.org/2001/XMLSchema#Duration"
-
-	// XsdByte byte
+	// XsdMonthDay GMonthDay
+	XsdMonthDay = "http://www.w3.org/2001/XMLSchema#gMonthDay"
+	// XsdDuration Duration
+	XsdDuration = "http://www.w3.org/2001/XMLSchema#duration"
+	// XsdDayTimeDuration Duration
+	XsdDayTimeDuration = "http://www.w3.org/2001/XMLSchema#dayTimeDuration"
+	// XsdYearMonthDuration Duration
+	XsdYearMonthDuration = "http://www.w3.org/2001/XMLSchema#yearMonthDuration"
+
+	// XsdFloat float32
+	XsdFloat = "http://www.w3.org/2001/XMLSchema#float"
+	// XsdNonPositiveInteger *big.Int
+	XsdNonPositiveInteger = "http://www.w3.org/2001/XMLSchema#nonPositiveInteger"
+	// XsdNegativeInteger *big.Int
+	XsdNegativeInteger = "http://www.w3.org/2001/XMLSchema#negativeInteger"
+	// XsdNonNegativeInteger *big.Int
+	XsdNonNegativeInteger = "http://www.w3.org/2001/XMLSchema#nonNegativeInteger"
+	// XsdPositiveInteger *big.Int
+	XsdPositiveInteger = "http://www.w3.org/2001/XMLSchema#positiveInteger"
+	// XsdLong int64
+	XsdLong = "http://www.w3.org/2001/XMLSchema#long"
+	// XsdInt int32
+	XsdInt = "http://www.w3.org/2001/XMLSchema#int"
+	// XsdShort int16
+	XsdShort = "http://www.w3.org/2001/XMLSchema#short"
+	// XsdByte int8
 	XsdByte = "http://www.w3.org/2001/XMLSchema#byte"
+	// XsdUnsignedLong uint64
+	XsdUnsignedLong = "http://www.w3.org/2001/XMLSchema#unsignedLong"
+	// XsdUnsignedInt uint32
+	XsdUnsignedInt = "http://www.w3.org/2001/XMLSchema#unsignedInt"
+	// XsdUnsignedShort uint16
+	XsdUnsignedShort = "http://www.w3.org/2001/XMLSchema#unsignedShort"
+	// XsdUnsignedByte uint8
+	XsdUnsignedByte = "http://www.w3.org/2001/XMLSchema#unsignedByte"
+
+	// XsdNormalizedString string
+	XsdNormalizedString = "http://www.w3.org/2001/XMLSchema#normalizedString"
+	// XsdToken string
+	XsdToken = "http://www.w3.org/2001/XMLSchema#token"
+	// XsdLanguage string
+	XsdLanguage = "http://www.w3.org/2001/XMLSchema#language"
+	// XsdName string
+	XsdName = "http://www.w3.org/2001/XMLSchema#Name"
+	// XsdNCName string
+	XsdNCName = "http://www.w3.org/2001/XMLSchema#NCName"
+	// XsdNMTOKEN string
+	XsdNMTOKEN = "http://www.w3.org/2001/XMLSchema#NMTOKEN"
+
+	// XsdAnyURI *url.URL
+	XsdAnyURI = "http://www.w3.org/2001/XMLSchema#anyURI"
+	// XsdHexBinary []byte
+	XsdHexBinary = "http://www.w3.org/2001/XMLSchema#hexBinary"
+	// XsdBase64Binary []byte
+	XsdBase64Binary = "http://www.w3.org/2001/XMLSchema#base64Binary"
+
+	// RdfLangString language-tagged string
+	RdfLangString = "http://www.w3.org/1999/02/22-rdf-syntax-ns#langString"
 )
 
 // Triple is one rdf triple consisting of Subject, Predicate and Object
@@ -129,6 +182,38 @@ type Object interface {
 	Term
 }
 
+// TermKey returns a key identifying a term; iris, blank nodes and literals get different keys even
+// if their strings are equal, and literals are distinguished by datatype and language tag. The nodes
+// of a graph are stored by their term keys.
+func TermKey(term Term) (key string) {
+	switch t := term.(type) {
+	case IRI:
+		key = "<" + t.name + ">"
+	case BlankNode:
+		key = "_:" + t.name
+	case Literal:
+		key = strconv.Quote(t.str)
+		if t.langTag != "" {
+			key += "@" + strings.ToLower(t.langTag)
+		} else {
+			key += "^^<" + t.Datatype() + ">"
+		}
+	default:
+		key = term.String()
+	}
+	return
+}
+
+// EqualTerms checks if two terms are identical (same kind, string, datatype and language tag)
+func EqualTerms(a, b Term) (equal bool) {
+	if a == nil || b == nil {
+		equal = a == nil && b == nil
+		return
+	}
+	equal = a.Type() == b.Type() && TermKey(a) == TermKey(b)
+	return
+}
+
 // IRI is a possible RDF term
 type IRI struct {
 	name string
@@ -172,121 +257,149 @@ func (lit Literal) String() (str string) {
 	return
 }
 
-// NewLiteral returns a literal
+// NewLiteral returns a literal; the datatype is determined by the Go type of val unless typ is
+// set. Values of registered datatypes are written in canonical form, strings are accepted as lexical
+// forms of any registered datatype and time.Time values can be written as any date or time type.
 func NewLiteral(val interface{}, typ string) (lit Literal, err error) {
-	switch t := val.(type) {
-	case int, int32, int64:
-		lit = Literal{str: fmt.Sprintf("%v", t), typeIRI: XsdInteger, value: t}
-	case bool:
-		lit = Literal{str: fmt.Sprintf("%v", t), typeIRI: XsdBoolean, value: t}
-	case float32, float64:
-		lit = Literal{str: fmt.Sprintf("%v", t), typeIRI: XsdDouble, value: t}
-	case string:
-		lit = Literal{str: t, typeIRI: XsdString, value: t}
-	case time.Time:
-		switch typ {
-		case XsdTime:
-			lit = Literal{str: t.Format("15:04:05Z07:00"), typeIRI: XsdDateTime, value: t}
-		case XsdDateTime:
-			lit = Literal{str: t.Format(time.RFC3339), typeIRI: XsdDateTime, value: t}
-		case XsdDateTimeStamp:
-			lit = Literal{str: t.Format(time.RFC3339), typeIRI: XsdDateTimeStamp, value: t}
-		case XsdDate:
-			lit = Literal{str: t.Format("2006-01-02Z07:00"), typeIRI: XsdDate, value: t}
-		case XsdDay:
-			lit = Literal{str: t.Format("---02"), typeIRI: XsdDay, value: t}
-		case XsdMonth:
-			lit = Literal{str: t.Format("--01"), typeIRI: XsdMonth, value: t}
-		case XsdYear:
-			lit = Literal{str: t.Format("2006"), typeIRI: XsdYear, value: t}
-		case XsdYearMonth:
-			lit = Literal{str: t.Format("2006-01"), typeIRI: XsdYearMonth, value: t}
-		default:
-			lit = Literal{str: t.Format(time.RFC3339), typeIRI: XsdDateTime, value: t}
+	if typ == "" {
+		typ, err = defaultDatatype(val)
+		if err != nil {
+			return
 		}
-	case time.Duration:
-		sec := fmt.Sprintf("%v", float32(t.Seconds())-float32(int(t.Seconds()))+float32(int(
-			t.Seconds())%60)) + "S"
-		min := fmt.Sprintf("%v", int(t.Minutes())%60) + "M"
-		hour := fmt.Sprintf("%v", int(t.Hours())) + "H"
-		//day := fmt.Sprintf("%v", int(in.Hours())/24) + "D"
-		lit = Literal{str: "P" + hour + min + sec, typeIRI: XsdDuration, value: t}
-	case []byte:
-		lit = Literal{str: string(t), typeIRI: XsdByte, value: t}
-	default:
-		err = fmt.Errorf("invalid rdf literal type %v", t)
+	}
+	dt, ok := datatypes[typ]
+	if !ok {
+		lit = Literal{str: fmt.Sprintf("%v", val), typeIRI: typ, value: val}
+		return
+	}
+	str, err := dt.Format(val)
+	if err != nil {
+		return
+	}
+	lit = Literal{str: str, typeIRI: typ}
+	if s, ok := val.(LangString); ok && typ == RdfLangString {
+		lit = NewLangLiteral(str, s.Lang)
 	}
 	return
 }
 
-// ToTime converts a xsd literal to time.Time if possible
-func (lit Literal) ToTime() (t time.Time, err error) {
-	switch lit.typeIRI {
-	case XsdTime:
-		t, err = time.Parse("15:04:05Z07:00", lit.str)
-	case XsdDateTime:
-		t, err = time.Parse(time.RFC3339, lit.str)
-	case XsdDateTimeStamp:
-		t, err = time.Parse(time.RFC3339, lit.str)
-	case XsdDate:
-		t, err = time.Parse("2006-01-02Z07:00", lit.str)
-	case XsdDay:
-		t, err = time.Parse("---02", lit.str)
-	case XsdMonth:
-		t, err = time.Parse("--01", lit.str)
-	case XsdYear:
-		t, err = time.Parse("2006", lit.str)
-	case XsdYearMonth:
-		t, err = time.Parse("2006-01", lit.str)
-	default:
-		err = errors.New("Cannot convert xsd datatype " + lit.typeIRI + " to time")
+// NewTypedLiteral returns a literal with the specified lexical form and datatype iri
+func NewTypedLiteral(lexical string, datatype string) (lit Literal) {
+	lit = Literal{str: lexical, typeIRI: datatype}
+	return
+}
+
+// NewLangLiteral returns a language-tagged string with the specified lexical form and language tag
+func NewLangLiteral(lexical string, lang string) (lit Literal) {
+	lit = Literal{str: lexical, langTag: lang}
+	return
+}
+
+// Datatype returns the datatype iri of the literal (rdf:langString for language-tagged strings and
+// xsd:string for simple literals)
+func (lit Literal) Datatype() (datatype string) {
+	if lit.langTag != "" {
+		datatype = RdfLangString
+	} else if lit.typeIRI == "" {
+		datatype = XsdString
+	} else {
+		datatype = lit.typeIRI
+	}
+	return
+}
+
+// Lang returns the language tag of the literal (empty if the literal is no language-tagged string)
+func (lit Literal) Lang() (lang string) {
+	lang = lit.langTag
+	return
+}
+
+// Value returns the Go value of the literal (see Parse); the lexical form is returned if it cannot
+// be converted
+func (lit Literal) Value() (value interface{}) {
+	if lit.value != nil {
+		value = lit.value
+		return
 	}
+	value, err := lit.Parse()
 	if err != nil {
+		value = lit.str
+	}
+	return
+}
+
+// Parse converts the lexical form to a value of the Go type of the registered datatype (e.g.
+// *big.Int for xsd:integer, Decimal for xsd:decimal, LangString for language-tagged strings)
+func (lit Literal) Parse() (value interface{}, err error) {
+	if lit.langTag != "" {
+		value = LangString{Text: lit.str, Lang: lit.langTag}
+		return
+	}
+	dt, ok := datatypes[lit.Datatype()]
+	if !ok {
+		err = errors.New("Unknown datatype " + lit.Datatype())
 		return
 	}
-	lit.value = t
+	value, err = dt.Parse(lit.str)
 	return
 }
 
-// ToDuration converts a literal to time.Duration if possible
-func (lit Literal) ToDuration() (d time.Duration, err error) {
-	switch lit.typeIRI {
-	case XsdDuration:
-		temp := strings.Split(lit.str, "P")
-		if len(temp) < 2 {
-			err = errors.New("Cannot convert xsd datatype " + lit.typeIRI + " to duration")
-			return
-		}
-		str := ""
-		h := strings.Split(temp[1], "H")
-		if len(h) > 1 {
-			_, err := strconv.Atoi(h[0])
-			if err != nil {
-				str += h[0] + "h"
-			}
-		}
-		m := strings.Split(h[len(h)-1], "M")
-		if len(m) > 1 {
-			_, err := strconv.Atoi(m[0])
-			if err != nil {
-				str += m[0] + "m"
-			}
-		}
-		s := strings.Split(m[len(m)-1], "S")
-		if len(s) > 1 {
-			_, err := strconv.ParseFloat(s[0], 32)
-			if err != nil {
-				str += s[0] + "s"
-			}
-		}
-		d, err = time.ParseDuration(str)
+// Validate checks the lexical form of the literal against its datatype; literals of unknown
+// datatypes are always valid
+func (lit Literal) Validate() (err error) {
+	dt, ok := datatypes[lit.Datatype()]
+	if !ok {
+		return
+	}
+	_, err = dt.Parse(lit.str)
+	return
+}
+
+// Canonical returns the literal with the canonical lexical form of its value (e.g. "01"^^xsd:integer
+// becomes "1"^^xsd:integer); literals of unknown datatypes are returned unchanged
+func (lit Literal) Canonical() (can Literal, err error) {
+	can = lit
+	dt, ok := datatypes[lit.Datatype()]
+	if !ok || lit.langTag != "" {
+		return
+	}
+	can.str, err = dt.Canonical(lit.str)
+	if err != nil {
+		can = lit
+	}
+	return
+}
+
+// ToTime converts a xsd date or time literal to time.Time if possible (see Time methods of Date,
+// GYear, ...)
+func (lit Literal) ToTime() (t time.Time, err error) {
+	value, err := lit.Parse()
+	if err != nil {
+		return
+	}
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case interface{ Time() time.Time }:
+		t = v.Time()
 	default:
-		err = errors.New("Cannot convert xsd datatype " + lit.typeIRI + " to duration")
+		err = errors.New("Cannot convert xsd datatype " + lit.typeIRI + " to time")
 	}
+	return
+}
+
+// ToDuration converts a xsd duration literal to time.Duration if possible
+func (lit Literal) ToDuration() (d time.Duration, err error) {
+	value, err := lit.Parse()
 	if err != nil {
 		return
 	}
-	lit.value = d
+	dur, ok := value.(Duration)
+	if !ok {
+		err = errors.New("Cannot convert xsd datatype " + lit.typeIRI + " to duration")
+		return
+	}
+	d, err = dur.ToDuration()
 	return
 }
 
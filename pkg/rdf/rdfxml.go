@@ -0,0 +1,613 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// namespaces used in rdf/xml documents
+const (
+	rdfNS = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xmlNS = "http://www.w3.org/XML/1998/namespace"
+)
+
+// entityRegex matches internal entity declarations in a document type declaration
+var entityRegex = regexp.MustCompile(`<!ENTITY\s+([^\s%]+)\s+(?:"([^"]*)"|'([^']*)')\s*>`)
+
+// xmlParser parses a rdf/xml document
+type xmlParser struct {
+	dec       *xml.Decoder         // xml decoder of document
+	triples   []Triple             // list of all extracted triples
+	bnCounter int                  // blank node counter
+	blank     map[string]BlankNode // blankNode map (rdf:nodeID)
+}
+
+// xmlContext holds the inherited xml:base, xml:lang and namespace declarations of an element
+type xmlContext struct {
+	base string            // base IRI
+	lang string            // language tag
+	ns   map[string]string // namespaces by prefix (empty prefix: default namespace)
+}
+
+// DecodeRDFXML decodes a rdf/xml input to rdf triples; optional decode options set the base iri of
+// the document (xml:base overrides it)
+func DecodeRDFXML(input io.Reader, opts ...DecodeOptions) (trip []Triple, err error) {
+	base := decodeOptions(opts).Base
+	p := &xmlParser{dec: xml.NewDecoder(input), blank: make(map[string]BlankNode)}
+	p.dec.Entity = make(map[string]string)
+	for {
+		var tok xml.Token
+		tok, err = p.dec.Token()
+		if err == io.EOF {
+			err = nil
+			break
+		}
+		if err != nil {
+			err = p.errorf(err.Error())
+			return
+		}
+		switch t := tok.(type) {
+		case xml.Directive:
+			// internal entities are used for namespaces in many ontologies
+			for _, m := range entityRegex.FindAllStringSubmatch(string(t), -1) {
+				p.dec.Entity[m[1]] = m[2] + m[3]
+			}
+		case xml.StartElement:
+			ctx := p.context(xmlContext{base: base}, t)
+			if t.Name.Space == rdfNS && t.Name.Local == "RDF" {
+				err = p.parseNodeElementList(ctx)
+			} else {
+				_, err = p.parseNodeElement(t, ctx)
+			}
+			trip = p.triples
+			return
+		}
+	}
+	trip = p.triples
+	return
+}
+
+// parseNodeElementList parses all node elements until the end of the current element
+func (p *xmlParser) parseNodeElementList(ctx xmlContext) (err error) {
+	for {
+		var tok xml.Token
+		tok, err = p.token()
+		if err != nil {
+			return
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			_, err = p.parseNodeElement(t, ctx)
+			if err != nil {
+				return
+			}
+		case xml.EndElement:
+			return
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) > 0 {
+				err = p.errorf("unexpected text " + strconv.Quote(string(t)))
+				return
+			}
+		}
+	}
+}
+
+// parseNodeElement parses a node element (rdf:Description or typed node) and all its property
+// elements
+func (p *xmlParser) parseNodeElement(start xml.StartElement, ctx xmlContext) (subj Term,
+	err error) {
+	ctx = p.context(ctx, start)
+	var props []xml.Attr
+	for _, a := range start.Attr {
+		switch rdfSyntaxAttr(a) {
+		case "about":
+			subj = NewIRI(resolveIRI(ctx.base, a.Value))
+		case "ID":
+			subj = NewIRI(resolveIRI(ctx.base, "#"+a.Value))
+		case "nodeID":
+			subj = p.blankNode(a.Value)
+		case "":
+			if isPropertyAttr(a) {
+				props = append(props, a)
+			}
+		default:
+			err = p.errorf("attribute rdf:" + a.Name.Local + " not allowed on node element")
+			return
+		}
+	}
+	if subj == nil {
+		subj = p.newBlankNode()
+	}
+	if start.Name.Space != rdfNS || start.Name.Local != "Description" {
+		p.add(subj, NewIRI(rdfNS+"type"), NewIRI(start.Name.Space+start.Name.Local))
+	}
+	p.addPropertyAttrs(subj, props, ctx)
+	err = p.parsePropertyElements(subj, ctx)
+	return
+}
+
+// parsePropertyElements parses all property elements until the end of the current element
+func (p *xmlParser) parsePropertyElements(subj Term, ctx xmlContext) (err error) {
+	li := 0
+	for {
+		var tok xml.Token
+		tok, err = p.token()
+		if err != nil {
+			return
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			err = p.parsePropertyElement(subj, t, ctx, &li)
+			if err != nil {
+				return
+			}
+		case xml.EndElement:
+			return
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) > 0 {
+				err = p.errorf("unexpected text " + strconv.Quote(string(t)))
+				return
+			}
+		}
+	}
+}
+
+// parsePropertyElement parses one property element and adds the resulting triples
+func (p *xmlParser) parsePropertyElement(subj Term, start xml.StartElement, ctx xmlContext,
+	li *int) (err error) {
+	if start.Name.Space == "" {
+		err = p.errorf("property element " + start.Name.Local + " without namespace")
+		return
+	}
+	ctx = p.context(ctx, start)
+	pred := NewIRI(start.Name.Space + start.Name.Local)
+	if start.Name.Space == rdfNS && start.Name.Local == "li" {
+		*li++
+		pred = NewIRI(rdfNS + "_" + strconv.Itoa(*li))
+	}
+	var resource, nodeID, datatype, parseType, id *string
+	var props []xml.Attr
+	for i := range start.Attr {
+		a := start.Attr[i]
+		switch rdfSyntaxAttr(a) {
+		case "resource":
+			resource = &start.Attr[i].Value
+		case "nodeID":
+			nodeID = &start.Attr[i].Value
+		case "datatype":
+			datatype = &start.Attr[i].Value
+		case "parseType":
+			parseType = &start.Attr[i].Value
+		case "ID":
+			id = &start.Attr[i].Value
+		case "":
+			if isPropertyAttr(a) {
+				props = append(props, a)
+			}
+		default:
+			err = p.errorf("attribute rdf:" + a.Name.Local + " not allowed on property element")
+			return
+		}
+	}
+
+	var obj Term
+	switch {
+	case parseType != nil && *parseType == "Resource":
+		blank := p.newBlankNode()
+		obj = blank
+		p.add(subj, pred, obj)
+		err = p.parsePropertyElements(blank, ctx)
+		if err != nil {
+			return
+		}
+	case parseType != nil && *parseType == "Collection":
+		obj, err = p.parseCollection(ctx)
+		if err != nil {
+			return
+		}
+		p.add(subj, pred, obj)
+	case parseType != nil:
+		// parseType="Literal" and all unknown parse types
+		var str string
+		str, err = p.xmlLiteral(ctx)
+		if err != nil {
+			return
+		}
+		obj = Literal{str: str, typeIRI: rdfNS + "XMLLiteral"}
+		p.add(subj, pred, obj)
+	default:
+		obj, err = p.parsePropertyContent(ctx, resource, nodeID, datatype, props)
+		if err != nil {
+			return
+		}
+		p.add(subj, pred, obj)
+	}
+
+	// reification
+	if id != nil {
+		stmt := NewIRI(resolveIRI(ctx.base, "#"+*id))
+		p.add(stmt, NewIRI(rdfNS+"type"), NewIRI(rdfNS+"Statement"))
+		p.add(stmt, NewIRI(rdfNS+"subject"), subj)
+		p.add(stmt, NewIRI(rdfNS+"predicate"), pred)
+		p.add(stmt, NewIRI(rdfNS+"object"), obj)
+	}
+	return
+}
+
+// parsePropertyContent parses the content of a property element (literal, node element or empty)
+func (p *xmlParser) parsePropertyContent(ctx xmlContext, resource, nodeID, datatype *string,
+	props []xml.Attr) (obj Term, err error) {
+	var text []byte
+	for {
+		var tok xml.Token
+		tok, err = p.token()
+		if err != nil {
+			return
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if obj != nil || len(bytes.TrimSpace(text)) > 0 {
+				err = p.errorf("property element with mixed content")
+				return
+			}
+			obj, err = p.parseNodeElement(t, ctx)
+			if err != nil {
+				return
+			}
+		case xml.CharData:
+			text = append(text, t...)
+		case xml.EndElement:
+			if obj != nil {
+				return
+			}
+			if resource != nil || nodeID != nil || len(props) > 0 {
+				if len(bytes.TrimSpace(text)) > 0 {
+					err = p.errorf("property element with text and resource attributes")
+					return
+				}
+				// empty property element
+				if resource != nil {
+					obj = NewIRI(resolveIRI(ctx.base, *resource))
+				} else if nodeID != nil {
+					obj = p.blankNode(*nodeID)
+				} else {
+					obj = p.newBlankNode()
+				}
+				p.addPropertyAttrs(obj, props, ctx)
+				return
+			}
+			lit := Literal{str: string(text)}
+			if datatype != nil {
+				lit.typeIRI = resolveIRI(ctx.base, *datatype)
+			} else {
+				lit.langTag = ctx.lang
+			}
+			obj = lit
+			return
+		}
+	}
+}
+
+// parseCollection parses the node elements of a collection (rdf:parseType="Collection") and
+// returns the head of the resulting rdf list
+func (p *xmlParser) parseCollection(ctx xmlContext) (head Term, err error) {
+	var items []Term
+	for {
+		var tok xml.Token
+		tok, err = p.token()
+		if err != nil {
+			return
+		}
+		if t, ok := tok.(xml.StartElement); ok {
+			var item Term
+			item, err = p.parseNodeElement(t, ctx)
+			if err != nil {
+				return
+			}
+			items = append(items, item)
+		} else if _, ok := tok.(xml.EndElement); ok {
+			break
+		}
+	}
+	head = NewIRI(rdfNS + "nil")
+	for i := len(items) - 1; i >= 0; i-- {
+		node := p.newBlankNode()
+		p.add(node, NewIRI(rdfNS+"first"), items[i])
+		p.add(node, NewIRI(rdfNS+"rest"), head)
+		head = node
+	}
+	return
+}
+
+// xmlLiteral returns the content of the current element as xml string in exclusive canonical
+// form: namespaces are declared at the outermost elements of the literal that use them, comments
+// are removed and attributes are sorted
+func (p *xmlParser) xmlLiteral(ctx xmlContext) (str string, err error) {
+	var b strings.Builder
+	scopes := []xmlContext{ctx}         // contexts of the open elements of the input
+	declared := []map[string]string{{}} // namespaces declared in the output by prefix
+	var names []string                  // qualified names of the open elements
+	for {
+		var tok xml.Token
+		tok, err = p.token()
+		if err != nil {
+			return
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			scope := p.context(scopes[len(scopes)-1], t)
+			outer := declared[len(declared)-1]
+			decls := make(map[string]string)
+			var name string
+			if name, err = p.qualifiedName(scope, outer, decls, t.Name, false); err != nil {
+				return
+			}
+			var attrs []xml.Attr
+			for _, a := range t.Attr {
+				if a.Name.Space != "xmlns" && (a.Name.Space != "" || a.Name.Local != "xmlns") {
+					attrs = append(attrs, a)
+				}
+			}
+			sort.Slice(attrs, func(i, j int) bool {
+				if attrs[i].Name.Space != attrs[j].Name.Space {
+					return attrs[i].Name.Space < attrs[j].Name.Space
+				}
+				return attrs[i].Name.Local < attrs[j].Name.Local
+			})
+			b.WriteString("<" + name)
+			var attrStr strings.Builder
+			for _, a := range attrs {
+				var attrName string
+				attrName, err = p.qualifiedName(scope, outer, decls, a.Name, true)
+				if err != nil {
+					return
+				}
+				attrStr.WriteString(" " + attrName + "=\"" + xmlAttrEscaper.Replace(a.Value) +
+					"\"")
+			}
+			inner := outer
+			if len(decls) > 0 {
+				prefixes := make([]string, 0, len(decls))
+				inner = make(map[string]string, len(outer)+len(decls))
+				for prefix, space := range outer {
+					inner[prefix] = space
+				}
+				for prefix, space := range decls {
+					prefixes = append(prefixes, prefix)
+					inner[prefix] = space
+				}
+				sort.Strings(prefixes)
+				for _, prefix := range prefixes {
+					if prefix == "" {
+						b.WriteString(" xmlns=\"" + xmlAttrEscaper.Replace(decls[prefix]) + "\"")
+					} else {
+						b.WriteString(" xmlns:" + prefix + "=\"" +
+							xmlAttrEscaper.Replace(decls[prefix]) + "\"")
+					}
+				}
+			}
+			b.WriteString(attrStr.String() + ">")
+			scopes = append(scopes, scope)
+			declared = append(declared, inner)
+			names = append(names, name)
+		case xml.EndElement:
+			if len(names) == 0 {
+				str = b.String()
+				return
+			}
+			b.WriteString("</" + names[len(names)-1] + ">")
+			scopes = scopes[:len(scopes)-1]
+			declared = declared[:len(declared)-1]
+			names = names[:len(names)-1]
+		case xml.CharData:
+			b.WriteString(xmlTextEscaper.Replace(string(t)))
+		case xml.ProcInst:
+			b.WriteString("<?" + t.Target)
+			if len(t.Inst) > 0 {
+				b.WriteString(" " + string(t.Inst))
+			}
+			b.WriteString("?>")
+		}
+	}
+}
+
+// qualifiedName returns the prefixed name of an element or attribute of a xml literal; the
+// namespace is added to decls if it has not been declared in the output yet
+func (p *xmlParser) qualifiedName(scope xmlContext, declared map[string]string,
+	decls map[string]string, name xml.Name, attr bool) (qname string, err error) {
+	if name.Space == xmlNS {
+		qname = "xml:" + name.Local
+		return
+	}
+	if name.Space == "" {
+		qname = name.Local
+		// elements without namespace must not inherit a declared default namespace
+		if !attr && declared[""] != "" {
+			decls[""] = ""
+		}
+		return
+	}
+	prefix, found := "", false
+	for pre, space := range scope.ns {
+		if space != name.Space || attr && pre == "" {
+			continue
+		}
+		if !found || pre == "" || prefix != "" && pre < prefix {
+			prefix, found = pre, true
+		}
+	}
+	if !found {
+		err = p.errorf("undeclared namespace prefix " + name.Space)
+		return
+	}
+	if space, ok := declared[prefix]; !ok || space != name.Space {
+		decls[prefix] = name.Space
+	}
+	qname = name.Local
+	if prefix != "" {
+		qname = prefix + ":" + name.Local
+	}
+	return
+}
+
+// escapers of text and attribute values of canonical xml
+var (
+	xmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	xmlAttrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", "\"", "&quot;", "\t", "&#x9;",
+		"\n", "&#xA;", "\r", "&#xD;")
+)
+
+// addPropertyAttrs adds triples for all property attributes of an element
+func (p *xmlParser) addPropertyAttrs(subj Term, props []xml.Attr, ctx xmlContext) {
+	for i := range props {
+		pred := props[i].Name.Space + props[i].Name.Local
+		if pred == rdfNS+"type" {
+			p.add(subj, NewIRI(pred), NewIRI(resolveIRI(ctx.base, props[i].Value)))
+		} else {
+			p.add(subj, NewIRI(pred), Literal{str: props[i].Value, langTag: ctx.lang})
+		}
+	}
+}
+
+// context returns the context of an element by applying its xml:base, xml:lang and namespace
+// attributes
+func (p *xmlParser) context(parent xmlContext, start xml.StartElement) (ctx xmlContext) {
+	ctx = parent
+	copied := false
+	for _, a := range start.Attr {
+		switch {
+		case a.Name.Space == "xmlns" || a.Name.Space == "" && a.Name.Local == "xmlns":
+			if !copied {
+				ctx.ns = make(map[string]string, len(parent.ns)+1)
+				for prefix, space := range parent.ns {
+					ctx.ns[prefix] = space
+				}
+				copied = true
+			}
+			if a.Name.Space == "xmlns" {
+				ctx.ns[a.Name.Local] = a.Value
+			} else {
+				ctx.ns[""] = a.Value
+			}
+		case a.Name.Space == xmlNS && a.Name.Local == "base":
+			ctx.base = resolveIRI(parent.base, a.Value)
+		case a.Name.Space == xmlNS && a.Name.Local == "lang":
+			ctx.lang = a.Value
+		}
+	}
+	return
+}
+
+// add adds a triple to the list of extracted triples
+func (p *xmlParser) add(subj Term, pred IRI, obj Term) {
+	p.triples = append(p.triples, Triple{Sub: subj, Pred: pred, Obj: obj})
+}
+
+// token returns the next token and fails at the end of the document
+func (p *xmlParser) token() (tok xml.Token, err error) {
+	tok, err = p.dec.Token()
+	if err == io.EOF {
+		err = p.errorf("unexpected end of document")
+	} else if err != nil {
+		err = p.errorf(err.Error())
+	}
+	return
+}
+
+// blankNode returns the blank node with the specified rdf:nodeID
+func (p *xmlParser) blankNode(name string) (blank BlankNode) {
+	var ok bool
+	blank, ok = p.blank[name]
+	if !ok {
+		blank = p.newBlankNode()
+		p.blank[name] = blank
+	}
+	return
+}
+
+// newBlankNode creates a new blank node and increments the counter
+func (p *xmlParser) newBlankNode() (blank BlankNode) {
+	blank = BlankNode{name: "bn" + strconv.Itoa(p.bnCounter)}
+	p.bnCounter++
+	return
+}
+
+// errorf returns an error containing the current line of the document
+func (p *xmlParser) errorf(msg string) (err error) {
+	line, _ := p.dec.InputPos()
+	err = errors.New("rdf/xml line " + strconv.Itoa(line) + ": " + msg)
+	return
+}
+
+// rdfSyntaxAttr returns the local name if the attribute is a rdf syntax attribute (rdf:about,
+// rdf:ID, ...) and an empty string otherwise
+func rdfSyntaxAttr(a xml.Attr) (name string) {
+	if a.Name.Space != rdfNS && a.Name.Space != "" {
+		return
+	}
+	switch a.Name.Local {
+	case "about", "ID", "nodeID", "resource", "datatype", "parseType":
+		name = a.Name.Local
+	case "aboutEach", "aboutEachPrefix", "bagID":
+		if a.Name.Space == rdfNS {
+			name = a.Name.Local
+		}
+	}
+	return
+}
+
+// isPropertyAttr checks if the attribute is a property attribute
+func isPropertyAttr(a xml.Attr) (ok bool) {
+	ok = a.Name.Space != "" && a.Name.Space != "xmlns" && a.Name.Space != xmlNS
+	return
+}
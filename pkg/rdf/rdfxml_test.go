@@ -0,0 +1,124 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// rdfXMLDoc wraps the property elements in a rdf/xml document with the subject
+// http://example.org/s
+func rdfXMLDoc(props string) (doc string) {
+	doc = `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xmlns:ex="http://example.org/" xmlns:h="http://www.w3.org/1999/xhtml">
+  <rdf:Description rdf:about="http://example.org/s">` + props + `</rdf:Description>
+</rdf:RDF>`
+	return
+}
+
+func TestXMLLiteral(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{`<h:b xmlns:a="http://a/"><h:i a:x="1">x</h:i><a:c/></h:b>`,
+			`<h:b xmlns:h="http://www.w3.org/1999/xhtml"><h:i xmlns:a="http://a/" a:x="1">x</h:i>` +
+				`<a:c xmlns:a="http://a/"></a:c></h:b>`},
+		{`text &amp; <span xmlns="http://www.w3.org/1999/xhtml">y<em>z</em></span>`,
+			`text &amp; <span xmlns="http://www.w3.org/1999/xhtml">y<em>z</em></span>`},
+		{`<a:x xmlns:a="http://a/"><a:y xmlns:a="http://b/"/><a:z/></a:x>`,
+			`<a:x xmlns:a="http://a/"><a:y xmlns:a="http://b/"></a:y><a:z></a:z></a:x>`},
+		{`<x xmlns="http://a/"><y xmlns=""/></x>`,
+			`<x xmlns="http://a/"><y xmlns=""></y></x>`},
+		{`<e b="2" a="&quot;1&quot;"><!-- comment -->t</e>`,
+			`<e a="&quot;1&quot;" b="2">t</e>`},
+	}
+	for _, test := range tests {
+		trips, err := DecodeRDFXML(strings.NewReader(rdfXMLDoc(
+			`<ex:p rdf:parseType="Literal">` + test.content + `</ex:p>`)))
+		if err != nil {
+			t.Fatalf("%s: %v", test.content, err)
+		}
+		lit, ok := trips[0].Obj.(Literal)
+		if len(trips) != 1 || !ok || lit.Datatype() != rdfNS+"XMLLiteral" {
+			t.Fatalf("%s: got %v", test.content, trips)
+		}
+		if lit.String() != test.want {
+			t.Errorf("%s:\ngot  %s\nwant %s", test.content, lit.String(), test.want)
+		}
+	}
+}
+
+func TestPropertyElementWithoutNamespace(t *testing.T) {
+	_, err := DecodeRDFXML(strings.NewReader(rdfXMLDoc(`<p>x</p>`)))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestRDFXMLBase(t *testing.T) {
+	doc := `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xmlns:ex="http://example.org/">
+  <rdf:Description rdf:about="#Foo"><ex:p rdf:resource="bar"/></rdf:Description>
+  <rdf:Description xml:base="http://example.com/dir/" rdf:about="baz"><ex:p rdf:resource="#q"/>
+  </rdf:Description>
+</rdf:RDF>`
+	trips, err := DecodeRDFXML(strings.NewReader(doc),
+		DecodeOptions{Base: "http://example.org/onto"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{{"http://example.org/onto#Foo", "http://example.org/bar"},
+		{"http://example.com/dir/baz", "http://example.com/dir/#q"}}
+	if len(trips) != len(want) {
+		t.Fatalf("got %v", trips)
+	}
+	for i, w := range want {
+		if trips[i].Sub.String() != w[0] || trips[i].Obj.String() != w[1] {
+			t.Errorf("got %v %v, want %v %v", trips[i].Sub, trips[i].Obj, w[0], w[1])
+		}
+	}
+}
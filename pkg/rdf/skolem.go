@@ -0,0 +1,121 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// genidPath is the well-known path of skolem IRIs
+const genidPath = "/.well-known/genid/"
+
+// Skolemize replaces all blank nodes of the graph with skolem IRIs which consist of the scheme
+// and authority of base, the path /.well-known/genid/ and a random identifier
+func (graph *Graph) Skolemize(base string) (err error) {
+	u, err := url.Parse(base)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		err = errors.New("Invalid skolem base " + base)
+		return
+	}
+	prefix := u.Scheme + "://" + u.Host + genidPath
+	var blanks []*Node
+	for _, node := range graph.Nodes {
+		if node.Term.Type() == TermBlankNode {
+			blanks = append(blanks, node)
+		}
+	}
+	id := make([]byte, 16)
+	for _, node := range blanks {
+		var iri IRI
+		for iri.name == "" || graph.Node(iri) != nil {
+			if _, err = rand.Read(id); err != nil {
+				return
+			}
+			iri = NewIRI(prefix + hex.EncodeToString(id))
+		}
+		graph.renameNode(node, iri)
+	}
+	return
+}
+
+// Deskolemize replaces all skolem IRIs (IRIs with the path /.well-known/genid/...) of the graph
+// with blank nodes which are labeled bnN
+func (graph *Graph) Deskolemize() {
+	var skolems []*Node
+	for _, node := range graph.Nodes {
+		if node.Term.Type() == TermIRI && IsSkolemIRI(node.Term.String()) {
+			skolems = append(skolems, node)
+		}
+	}
+	sort.Slice(skolems, func(i, j int) bool {
+		return skolems[i].Term.String() < skolems[j].Term.String()
+	})
+	next := 0
+	for _, node := range skolems {
+		graph.renameNode(node, graph.newBlankNode(&next))
+	}
+	return
+}
+
+// IsSkolemIRI checks if iri is a skolem IRI
+func IsSkolemIRI(iri string) (ok bool) {
+	u, err := url.Parse(iri)
+	ok = err == nil && u.IsAbs() && strings.HasPrefix(u.Path, genidPath) &&
+		len(u.Path) > len(genidPath)
+	return
+}
+
+// renameNode replaces the term of the node; the term must not be used by another node
+func (graph *Graph) renameNode(node *Node, term Term) {
+	graph.removeNode(node)
+	node.Term = term
+	graph.Nodes[TermKey(term)] = node
+	return
+}
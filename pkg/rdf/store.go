@@ -0,0 +1,133 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import "errors"
+
+// Store is a set of triples which can be modified and queried. Changes between Begin and Commit
+// are applied together, Abort undoes them; changes outside of a transaction are committed
+// immediately. Graph keeps the triples in memory, FileStore persists them in a directory.
+type Store interface {
+	// Add adds the triples and returns the number of triples that have not been contained before
+	Add(triple ...Triple) (num int, err error)
+	// Remove removes the triples and returns the number of triples that have been contained
+	Remove(triple ...Triple) (num int, err error)
+	// Match returns an iterator over all triples matching subject, predicate and object; nil
+	// terms are wildcards
+	Match(s, p, o Term) (it *Iterator)
+	// Begin starts a transaction
+	Begin() (err error)
+	// Commit ends the transaction and keeps its changes
+	Commit() (err error)
+	// Abort ends the transaction and undoes its changes
+	Abort() (err error)
+}
+
+// Add adds the triples to the graph and returns the number of added triples
+func (graph *Graph) Add(triple ...Triple) (num int, err error) {
+	for i := range triple {
+		if _, added := graph.AddTriple(triple[i]); added {
+			num++
+		}
+	}
+	return
+}
+
+// Remove removes the triples from the graph and returns the number of removed triples
+func (graph *Graph) Remove(triple ...Triple) (num int, err error) {
+	num = graph.RemoveTriple(triple...)
+	return
+}
+
+// Begin starts a transaction; all following changes of the graph are recorded until Commit or
+// Abort is called
+func (graph *Graph) Begin() (err error) {
+	if graph.tx != nil {
+		err = errors.New("Transaction already started")
+		return
+	}
+	graph.tx = &Patch{}
+	return
+}
+
+// Commit ends the transaction of the graph
+func (graph *Graph) Commit() (err error) {
+	if graph.tx == nil {
+		err = errors.New("No transaction started")
+		return
+	}
+	graph.tx = nil
+	return
+}
+
+// Abort ends the transaction of the graph and undoes all changes since Begin
+func (graph *Graph) Abort() (err error) {
+	if graph.tx == nil {
+		err = errors.New("No transaction started")
+		return
+	}
+	rows := graph.tx.Rows
+	graph.tx = nil
+	undo := make([]PatchRow, len(rows))
+	for i, row := range rows {
+		if row.Op == PatchAdd {
+			row.Op = PatchDelete
+		} else {
+			row.Op = PatchAdd
+		}
+		undo[len(rows)-1-i] = row
+	}
+	graph.applyRows(undo)
+	return
+}
+
+// record records a change of the graph if a transaction is running
+func (graph *Graph) record(op PatchOp, edge *Edge) {
+	if graph.tx != nil {
+		graph.tx.Rows = append(graph.tx.Rows, PatchRow{Op: op, Quad: Quad{Triple: Triple{
+			Sub: edge.Subject.Term, Pred: edge.Pred, Obj: edge.Object.Term}}})
+	}
+	return
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SyntaxError describes an error in a ttl or trig document
+type SyntaxError struct {
+	Line     int            // line of the error (starting at 1)
+	Column   int            // column of the error in runes (starting at 1)
+	Snippet  string         // source line containing the error
+	Expected string         // expected token (empty if unknown)
+	Msg      string         // description of the error
+	Others   []*SyntaxError // errors found after recovering from this one
+	offset   int            // position of the error in the runes of the parser
+	eof      bool           // input ended before the statement (more input may complete it)
+}
+
+// Error returns the position and the description of the error
+func (e *SyntaxError) Error() (msg string) {
+	msg = "line " + strconv.Itoa(e.Line) + ", column " + strconv.Itoa(e.Column) + ": " + e.Msg
+	if e.Expected != "" {
+		msg += " (expected " + strconv.Quote(e.Expected) + ")"
+	}
+	if e.Snippet != "" {
+		msg += "\n\t" + e.Snippet
+	}
+	if len(e.Others) > 0 {
+		msg += "\n(and " + strconv.Itoa(len(e.Others)) + " more errors)"
+	}
+	return
+}
+
+// sourceLine maps runes of the parser to a line of the document
+type sourceLine struct {
+	start int // position of the first rune of the line (including dropped runes)
+	num   int // line number (starting at 1)
+}
+
+// syntaxError creates a syntax error at the specified position of the runes
+func (p *parser) syntaxError(pos int, expected string, msg string) (err error) {
+	// an error behind the last rune is caused by the end of the input (e.g. a missing dot after
+	// a comment ending with a dot)
+	e := &SyntaxError{Expected: expected, Msg: msg, offset: pos, eof: pos >= len(p.runes)}
+	abs := pos + p.dropped
+	i := sort.Search(len(p.lines), func(i int) bool { return p.lines[i].start > abs }) - 1
+	if i < 0 {
+		e.Line, e.Column = 1, pos+1
+		err = e
+		return
+	}
+	e.Line = p.lines[i].num
+	e.Column = abs - p.lines[i].start + 1
+	start := p.lines[i].start - p.dropped
+	if start < 0 {
+		start = 0
+	}
+	end := len(p.runes)
+	if i+1 < len(p.lines) {
+		end = p.lines[i+1].start - p.dropped
+	}
+	e.Snippet = strings.TrimSpace(string(p.runes[start:end]))
+	err = e
+	return
+}
+
+// eofError creates a syntax error for input that ends before the end of the statement
+func (p *parser) eofError(pos int, expected string, msg string) (err error) {
+	err = p.syntaxError(pos, expected, msg)
+	err.(*SyntaxError).eof = true
+	return
+}
+
+// isIncomplete checks if err reports that the input ended before the end of the statement
+func isIncomplete(err error) (ok bool) {
+	e, ok := err.(*SyntaxError)
+	ok = ok && e.eof
+	return
+}
+
+// dropRunes removes parsed runes from the beginning of the runes (positions in the line map are
+// kept)
+func (p *parser) dropRunes(num int) {
+	p.runes = append(p.runes[:0], p.runes[num:]...)
+	p.posStatement -= num
+	p.dropped += num
+	// keep the line containing the first remaining rune
+	i := sort.Search(len(p.lines), func(i int) bool { return p.lines[i].start > p.dropped }) - 1
+	if i > 0 {
+		p.lines = append(p.lines[:0], p.lines[i:]...)
+	}
+}
+
+// parseDocument parses all statements of the runes; after a syntax error the rest of the statement
+// is skipped and parsing continues so that the first error contains all further errors
+func (p *parser) parseDocument() (err error) {
+	var first *SyntaxError
+	// remove whitepaces at beginning
+	p.posStatement = p.consumeWS(0)
+	for p.posStatement < len(p.runes) {
+		numTriples, numQuads := len(p.triples), len(p.quads)
+		e := p.parseStatement()
+		if e == nil {
+			continue
+		}
+		se, ok := e.(*SyntaxError)
+		if !ok {
+			err = e
+			return
+		}
+		// drop triples of the invalid statement
+		p.triples, p.quads = p.triples[:numTriples], p.quads[:numQuads]
+		if first == nil {
+			first = se
+		} else {
+			first.Others = append(first.Others, se)
+		}
+		p.skipStatement(se.offset)
+	}
+	if first != nil {
+		err = first
+	}
+	return
+}
+
+// skipStatement moves the start of the next statement behind the next dot (followed by a white
+// space or the end of the document) after the specified position
+func (p *parser) skipStatement(pos int) {
+	if pos <= p.posStatement {
+		pos = p.posStatement + 1
+	}
+	for i := pos; i < len(p.runes); i++ {
+		if p.runes[i] == '.' && (i+1 == len(p.runes) ||
+			strings.ContainsRune(" \t\r\n", p.runes[i+1])) {
+			p.posStatement = i + 1 + p.consumeWS(i+1)
+			return
+		}
+	}
+	p.posStatement = len(p.runes)
+}
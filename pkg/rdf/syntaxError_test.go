@@ -0,0 +1,95 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSyntaxErrorRecovery(t *testing.T) {
+	input := `@prefix ex: <http://example.org/> .
+ex:a ex:p ex:b .
+ex:c ex:p ex:q ex:r .
+ex:d ex:p ex:e .
+ex:f ex:p no:g .
+ex:i ex:p ex:j .
+`
+	trips, err := DecodeTTL(strings.NewReader(input))
+	e, ok := err.(*SyntaxError)
+	if !ok {
+		t.Fatalf("expected syntax error, got %v", err)
+	}
+	lines := []int{e.Line}
+	for _, other := range e.Others {
+		lines = append(lines, other.Line)
+	}
+	if len(lines) != 2 || lines[0] != 3 || lines[1] != 5 {
+		t.Errorf("got errors in lines %v, want [3 5]:\n%v", lines, err)
+	}
+	var subj []string
+	for _, trip := range trips {
+		subj = append(subj, strings.TrimPrefix(trip.Sub.String(), "http://example.org/"))
+	}
+	if strings.Join(subj, " ") != "a d i" {
+		t.Errorf("got triples with subjects %v, want [a d i]", subj)
+	}
+}
+
+func TestReplacementCharacter(t *testing.T) {
+	trips, err := DecodeTTL(strings.NewReader("<http://example.org/s> <http://example.org/p> " +
+		"\"a\uFFFDb\" ."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trips) != 1 || trips[0].Obj.String() != "a\uFFFDb" {
+		t.Errorf("got %v, want literal a\\uFFFDb", trips)
+	}
+	_, err = DecodeTTL(strings.NewReader("<http://example.org/s> <http://example.org/p> " +
+		"\"a\xffb\" ."))
+	if _, ok := err.(*SyntaxError); !ok {
+		t.Errorf("expected syntax error for invalid utf-8, got %v", err)
+	}
+}
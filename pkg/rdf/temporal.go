@@ -0,0 +1,687 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lexical forms of the xsd date, time and duration datatypes
+var (
+	yearPattern      = `(?P<y>-?(?:[1-9][0-9]{4,}|[0-9]{4}))`
+	datePattern      = yearPattern + `-(?P<m>[0-9]{2})-(?P<d>[0-9]{2})`
+	timePattern      = `(?P<h>[0-9]{2}):(?P<i>[0-9]{2}):(?P<s>[0-9]{2})(?P<f>\.[0-9]+)?`
+	tzPattern        = `(?P<z>Z|[+-][0-9]{2}:[0-9]{2})?`
+	dateTimeRegexp   = regexp.MustCompile(`^` + datePattern + `T` + timePattern + tzPattern + `$`)
+	dateRegexp       = regexp.MustCompile(`^` + datePattern + tzPattern + `$`)
+	timeRegexp       = regexp.MustCompile(`^` + timePattern + tzPattern + `$`)
+	gYearMonthRegexp = regexp.MustCompile(`^` + yearPattern + `-(?P<m>[0-9]{2})` + tzPattern + `$`)
+	gYearRegexp      = regexp.MustCompile(`^` + yearPattern + tzPattern + `$`)
+	gMonthDayRegexp  = regexp.MustCompile(`^--(?P<m>[0-9]{2})-(?P<d>[0-9]{2})` + tzPattern + `$`)
+	gMonthRegexp     = regexp.MustCompile(`^--(?P<m>[0-9]{2})` + tzPattern + `$`)
+	gDayRegexp       = regexp.MustCompile(`^---(?P<d>[0-9]{2})` + tzPattern + `$`)
+	durationRegexp   = regexp.MustCompile(`^(-)?P(?:([0-9]+)Y)?(?:([0-9]+)M)?(?:([0-9]+)D)?` +
+		`(?:T(?:([0-9]+)H)?(?:([0-9]+)M)?(?:([0-9]+(?:\.[0-9]*)?|\.[0-9]+)S)?)?$`)
+)
+
+// errors of the temporal datatypes
+var (
+	errInvalidLexical   = errors.New("invalid lexical form")
+	errDurationEmpty    = errors.New("duration without components")
+	errDurationMonths   = errors.New("duration with years or months cannot be converted")
+	errDurationOverflow = errors.New("duration exceeds time.Duration")
+)
+
+// Timezone is the optional timezone of a date or time value
+type Timezone struct {
+	Valid  bool // timezone is present
+	Offset int  // offset from UTC in minutes
+}
+
+// String returns the timezone in lexical form (Z, +hh:mm, -hh:mm or empty)
+func (tz Timezone) String() (str string) {
+	if !tz.Valid {
+		return
+	}
+	if tz.Offset == 0 {
+		str = "Z"
+		return
+	}
+	sign, off := "+", tz.Offset
+	if off < 0 {
+		sign, off = "-", -off
+	}
+	str = fmt.Sprintf("%v%02d:%02d", sign, off/60, off%60)
+	return
+}
+
+// Location returns the timezone as time.Location (UTC if no timezone is present)
+func (tz Timezone) Location() (loc *time.Location) {
+	if !tz.Valid || tz.Offset == 0 {
+		loc = time.UTC
+		return
+	}
+	loc = time.FixedZone(tz.String(), tz.Offset*60)
+	return
+}
+
+// timezoneOf returns the timezone of t
+func timezoneOf(t time.Time) (tz Timezone) {
+	_, offset := t.Zone()
+	tz = Timezone{Valid: true, Offset: offset / 60}
+	return
+}
+
+// temporal holds the components of a date or time lexical form
+type temporal struct {
+	year, month, day, hour, minute, second, nanosecond int
+	tz                                                 Timezone
+}
+
+// parseTemporal parses a date or time lexical form with the regular expression re and checks the
+// ranges of the components
+func parseTemporal(re *regexp.Regexp, lexical string) (t temporal, err error) {
+	match := re.FindStringSubmatch(strings.TrimSpace(lexical))
+	if match == nil {
+		err = errInvalidLexical
+		return
+	}
+	t.month, t.day = 1, 1
+	hasYear, hasDay := false, false
+	names := re.SubexpNames()
+	for i := range names {
+		hasYear = hasYear || names[i] == "y"
+		hasDay = hasDay || names[i] == "d"
+		if match[i] == "" {
+			continue
+		}
+		switch names[i] {
+		case "y":
+			t.year, err = strconv.Atoi(match[i])
+		case "m":
+			t.month, err = strconv.Atoi(match[i])
+		case "d":
+			t.day, err = strconv.Atoi(match[i])
+		case "h":
+			t.hour, err = strconv.Atoi(match[i])
+		case "i":
+			t.minute, err = strconv.Atoi(match[i])
+		case "s":
+			t.second, err = strconv.Atoi(match[i])
+		case "f":
+			t.nanosecond, err = strconv.Atoi((match[i][1:] + "000000000")[:9])
+		case "z":
+			t.tz, err = parseTimezone(match[i])
+		}
+		if err != nil {
+			return
+		}
+	}
+	maxDay := 31
+	switch {
+	case hasYear && hasDay:
+		maxDay = time.Date(t.year, time.Month(t.month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	case t.month == 2:
+		maxDay = 29
+	case t.month == 4 || t.month == 6 || t.month == 9 || t.month == 11:
+		maxDay = 30
+	}
+	if t.month < 1 || t.month > 12 || t.day < 1 || t.day > maxDay || t.minute > 59 ||
+		t.second > 59 || t.hour > 24 || t.hour == 24 && (t.minute != 0 || t.second != 0 ||
+		t.nanosecond != 0) {
+		err = errors.New("component out of range")
+	}
+	return
+}
+
+// parseTimezone parses a timezone (Z, +hh:mm or -hh:mm)
+func parseTimezone(str string) (tz Timezone, err error) {
+	tz.Valid = true
+	if str == "Z" {
+		return
+	}
+	h, _ := strconv.Atoi(str[1:3])
+	m, _ := strconv.Atoi(str[4:6])
+	if m > 59 || h*60+m > 14*60 {
+		err = errors.New("timezone out of range")
+		return
+	}
+	tz.Offset = h*60 + m
+	if str[0] == '-' {
+		tz.Offset = -tz.Offset
+	}
+	return
+}
+
+// formatYear writes a year with at least four digits
+func formatYear(year int) (str string) {
+	if year < 0 {
+		str = fmt.Sprintf("-%04d", -year)
+	} else {
+		str = fmt.Sprintf("%04d", year)
+	}
+	return
+}
+
+// formatClock writes hours, minutes and seconds with the fraction of a second (without trailing
+// zeros)
+func formatClock(hour int, minute int, second int, nanosecond int) (str string) {
+	str = fmt.Sprintf("%02d:%02d:%02d", hour, minute, second)
+	if nanosecond != 0 {
+		str += strings.TrimRight(fmt.Sprintf(".%09d", nanosecond), "0")
+	}
+	return
+}
+
+// DateTime is a xsd:dateTime value; unlike time.Time it keeps whether a timezone is present
+type DateTime struct {
+	Year       int
+	Month      int
+	Day        int
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+	Timezone   Timezone
+}
+
+// ParseDateTime parses a xsd:dateTime (e.g. 2020-05-01T10:00:00 or 2020-05-01T10:00:00.5Z);
+// 24:00:00 is mapped to 00:00:00 of the next day
+func ParseDateTime(lexical string) (dt DateTime, err error) {
+	temp, err := parseTemporal(dateTimeRegexp, lexical)
+	if err != nil {
+		return
+	}
+	if temp.hour == 24 {
+		next := time.Date(temp.year, time.Month(temp.month), temp.day+1, 0, 0, 0, 0, time.UTC)
+		temp.year, temp.month, temp.day, temp.hour = next.Year(), int(next.Month()), next.Day(), 0
+	}
+	dt = DateTime{Year: temp.year, Month: temp.month, Day: temp.day, Hour: temp.hour,
+		Minute: temp.minute, Second: temp.second, Nanosecond: temp.nanosecond, Timezone: temp.tz}
+	return
+}
+
+// ParseDateTimeStamp parses a xsd:dateTimeStamp (a xsd:dateTime with required timezone)
+func ParseDateTimeStamp(lexical string) (dt DateTime, err error) {
+	dt, err = ParseDateTime(lexical)
+	if err == nil && !dt.Timezone.Valid {
+		err = errors.New("missing timezone")
+	}
+	return
+}
+
+// NewDateTime returns the date and time (with timezone) of t
+func NewDateTime(t time.Time) (dt DateTime) {
+	dt = DateTime{Year: t.Year(), Month: int(t.Month()), Day: t.Day(), Hour: t.Hour(),
+		Minute: t.Minute(), Second: t.Second(), Nanosecond: t.Nanosecond(), Timezone: timezoneOf(t)}
+	return
+}
+
+// FormatDateTime writes t as xsd:dateTime (e.g. 2020-05-01T10:00:00.5Z)
+func FormatDateTime(t time.Time) (lexical string) {
+	lexical = NewDateTime(t).String()
+	return
+}
+
+// String returns the canonical lexical form; the timezone is omitted if it is not present
+func (dt DateTime) String() (str string) {
+	str = formatYear(dt.Year) + fmt.Sprintf("-%02d-%02dT", dt.Month, dt.Day) +
+		formatClock(dt.Hour, dt.Minute, dt.Second, dt.Nanosecond) + dt.Timezone.String()
+	return
+}
+
+// Time returns the date and time as time.Time (in UTC if the value has no timezone)
+func (dt DateTime) Time() (t time.Time) {
+	t = time.Date(dt.Year, time.Month(dt.Month), dt.Day, dt.Hour, dt.Minute, dt.Second,
+		dt.Nanosecond, dt.Timezone.Location())
+	return
+}
+
+// IsZero reports whether dt is the zero value
+func (dt DateTime) IsZero() (zero bool) {
+	zero = dt == DateTime{}
+	return
+}
+
+// Date is a xsd:date value
+type Date struct {
+	Year     int
+	Month    int
+	Day      int
+	Timezone Timezone
+}
+
+// ParseDate parses a xsd:date (e.g. 2020-05-01 or 2020-05-01+02:00)
+func ParseDate(lexical string) (d Date, err error) {
+	t, err := parseTemporal(dateRegexp, lexical)
+	d = Date{Year: t.year, Month: t.month, Day: t.day, Timezone: t.tz}
+	return
+}
+
+// NewDate returns the date (with timezone) of t
+func NewDate(t time.Time) (d Date) {
+	d = Date{Year: t.Year(), Month: int(t.Month()), Day: t.Day(), Timezone: timezoneOf(t)}
+	return
+}
+
+// String returns the canonical lexical form
+func (d Date) String() (str string) {
+	str = formatYear(d.Year) + fmt.Sprintf("-%02d-%02d", d.Month, d.Day) + d.Timezone.String()
+	return
+}
+
+// Time returns the start of the date (in UTC if the date has no timezone)
+func (d Date) Time() (t time.Time) {
+	t = time.Date(d.Year, time.Month(d.Month), d.Day, 0, 0, 0, 0, d.Timezone.Location())
+	return
+}
+
+// IsZero reports whether d is the zero value
+func (d Date) IsZero() (zero bool) {
+	zero = d == Date{}
+	return
+}
+
+// Time is a xsd:time value (time of day)
+type Time struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+	Timezone   Timezone
+}
+
+// ParseTime parses a xsd:time (e.g. 10:00:00 or 10:00:00.5Z); 24:00:00 is mapped to 00:00:00
+func ParseTime(lexical string) (t Time, err error) {
+	temp, err := parseTemporal(timeRegexp, lexical)
+	t = Time{Hour: temp.hour % 24, Minute: temp.minute, Second: temp.second,
+		Nanosecond: temp.nanosecond, Timezone: temp.tz}
+	return
+}
+
+// NewTime returns the time of day (with timezone) of t
+func NewTime(t time.Time) (tod Time) {
+	tod = Time{Hour: t.Hour(), Minute: t.Minute(), Second: t.Second(), Nanosecond: t.Nanosecond(),
+		Timezone: timezoneOf(t)}
+	return
+}
+
+// String returns the canonical lexical form
+func (t Time) String() (str string) {
+	str = formatClock(t.Hour, t.Minute, t.Second, t.Nanosecond) + t.Timezone.String()
+	return
+}
+
+// Time returns the time of day at 0000-01-01 (in UTC if the time has no timezone)
+func (t Time) Time() (tt time.Time) {
+	tt = time.Date(0, 1, 1, t.Hour, t.Minute, t.Second, t.Nanosecond, t.Timezone.Location())
+	return
+}
+
+// IsZero reports whether t is the zero value
+func (t Time) IsZero() (zero bool) {
+	zero = t == Time{}
+	return
+}
+
+// GYear is a xsd:gYear value
+type GYear struct {
+	Year     int
+	Timezone Timezone
+}
+
+// ParseGYear parses a xsd:gYear (e.g. 2020)
+func ParseGYear(lexical string) (g GYear, err error) {
+	t, err := parseTemporal(gYearRegexp, lexical)
+	g = GYear{Year: t.year, Timezone: t.tz}
+	return
+}
+
+// NewGYear returns the year of t (without timezone)
+func NewGYear(t time.Time) (g GYear) {
+	g = GYear{Year: t.Year()}
+	return
+}
+
+// String returns the canonical lexical form
+func (g GYear) String() (str string) {
+	str = formatYear(g.Year) + g.Timezone.String()
+	return
+}
+
+// Time returns the start of the year (in UTC if the year has no timezone)
+func (g GYear) Time() (t time.Time) {
+	t = time.Date(g.Year, 1, 1, 0, 0, 0, 0, g.Timezone.Location())
+	return
+}
+
+// IsZero reports whether g is the zero value
+func (g GYear) IsZero() (zero bool) {
+	zero = g == GYear{}
+	return
+}
+
+// GYearMonth is a xsd:gYearMonth value
+type GYearMonth struct {
+	Year     int
+	Month    int
+	Timezone Timezone
+}
+
+// ParseGYearMonth parses a xsd:gYearMonth (e.g. 2020-05)
+func ParseGYearMonth(lexical string) (g GYearMonth, err error) {
+	t, err := parseTemporal(gYearMonthRegexp, lexical)
+	g = GYearMonth{Year: t.year, Month: t.month, Timezone: t.tz}
+	return
+}
+
+// NewGYearMonth returns the year and month of t (without timezone)
+func NewGYearMonth(t time.Time) (g GYearMonth) {
+	g = GYearMonth{Year: t.Year(), Month: int(t.Month())}
+	return
+}
+
+// String returns the canonical lexical form
+func (g GYearMonth) String() (str string) {
+	str = formatYear(g.Year) + fmt.Sprintf("-%02d", g.Month) + g.Timezone.String()
+	return
+}
+
+// Time returns the start of the month (in UTC if the value has no timezone)
+func (g GYearMonth) Time() (t time.Time) {
+	t = time.Date(g.Year, time.Month(g.Month), 1, 0, 0, 0, 0, g.Timezone.Location())
+	return
+}
+
+// IsZero reports whether g is the zero value
+func (g GYearMonth) IsZero() (zero bool) {
+	zero = g == GYearMonth{}
+	return
+}
+
+// GMonth is a xsd:gMonth value (a recurring month of the year)
+type GMonth struct {
+	Month    int
+	Timezone Timezone
+}
+
+// ParseGMonth parses a xsd:gMonth (e.g. --05)
+func ParseGMonth(lexical string) (g GMonth, err error) {
+	t, err := parseTemporal(gMonthRegexp, lexical)
+	g = GMonth{Month: t.month, Timezone: t.tz}
+	return
+}
+
+// NewGMonth returns the month of t (without timezone)
+func NewGMonth(t time.Time) (g GMonth) {
+	g = GMonth{Month: int(t.Month())}
+	return
+}
+
+// String returns the canonical lexical form
+func (g GMonth) String() (str string) {
+	str = fmt.Sprintf("--%02d", g.Month) + g.Timezone.String()
+	return
+}
+
+// Time returns the start of the month in year 0 (in UTC if the value has no timezone)
+func (g GMonth) Time() (t time.Time) {
+	t = time.Date(0, time.Month(g.Month), 1, 0, 0, 0, 0, g.Timezone.Location())
+	return
+}
+
+// IsZero reports whether g is the zero value
+func (g GMonth) IsZero() (zero bool) {
+	zero = g == GMonth{}
+	return
+}
+
+// GMonthDay is a xsd:gMonthDay value (a recurring day of the year)
+type GMonthDay struct {
+	Month    int
+	Day      int
+	Timezone Timezone
+}
+
+// ParseGMonthDay parses a xsd:gMonthDay (e.g. --05-01)
+func ParseGMonthDay(lexical string) (g GMonthDay, err error) {
+	t, err := parseTemporal(gMonthDayRegexp, lexical)
+	g = GMonthDay{Month: t.month, Day: t.day, Timezone: t.tz}
+	return
+}
+
+// NewGMonthDay returns the month and day of t (without timezone)
+func NewGMonthDay(t time.Time) (g GMonthDay) {
+	g = GMonthDay{Month: int(t.Month()), Day: t.Day()}
+	return
+}
+
+// String returns the canonical lexical form
+func (g GMonthDay) String() (str string) {
+	str = fmt.Sprintf("--%02d-%02d", g.Month, g.Day) + g.Timezone.String()
+	return
+}
+
+// Time returns the start of the day in year 0 (in UTC if the value has no timezone)
+func (g GMonthDay) Time() (t time.Time) {
+	t = time.Date(0, time.Month(g.Month), g.Day, 0, 0, 0, 0, g.Timezone.Location())
+	return
+}
+
+// IsZero reports whether g is the zero value
+func (g GMonthDay) IsZero() (zero bool) {
+	zero = g == GMonthDay{}
+	return
+}
+
+// GDay is a xsd:gDay value (a recurring day of the month)
+type GDay struct {
+	Day      int
+	Timezone Timezone
+}
+
+// ParseGDay parses a xsd:gDay (e.g. ---01)
+func ParseGDay(lexical string) (g GDay, err error) {
+	t, err := parseTemporal(gDayRegexp, lexical)
+	g = GDay{Day: t.day, Timezone: t.tz}
+	return
+}
+
+// NewGDay returns the day of t (without timezone)
+func NewGDay(t time.Time) (g GDay) {
+	g = GDay{Day: t.Day()}
+	return
+}
+
+// String returns the canonical lexical form
+func (g GDay) String() (str string) {
+	str = fmt.Sprintf("---%02d", g.Day) + g.Timezone.String()
+	return
+}
+
+// Time returns the start of the day in January of year 0 (in UTC if the value has no timezone)
+func (g GDay) Time() (t time.Time) {
+	t = time.Date(0, 1, g.Day, 0, 0, 0, 0, g.Timezone.Location())
+	return
+}
+
+// IsZero reports whether g is the zero value
+func (g GDay) IsZero() (zero bool) {
+	zero = g == GDay{}
+	return
+}
+
+// Duration is a xsd:duration value; years and months are counted as months, days, hours and
+// minutes as seconds (a day has 86400 seconds)
+type Duration struct {
+	Negative    bool
+	Months      int64
+	Seconds     int64
+	Nanoseconds int64 // fraction of a second (0 to 999999999)
+}
+
+// ParseDuration parses a xsd:duration (e.g. P1Y2M3DT4H5M6.7S); fractions of a second are limited to
+// nanoseconds
+func ParseDuration(lexical string) (d Duration, err error) {
+	str := strings.TrimSpace(lexical)
+	match := durationRegexp.FindStringSubmatch(str)
+	if match == nil {
+		err = errInvalidLexical
+		return
+	}
+	if str == "P" || str == "-P" || strings.HasSuffix(str, "T") {
+		err = errDurationEmpty
+		return
+	}
+	parts := make([]int64, 6)
+	for i := 0; i < 5; i++ {
+		if match[i+2] == "" {
+			continue
+		}
+		parts[i], err = strconv.ParseInt(match[i+2], 10, 64)
+		if err != nil {
+			return
+		}
+	}
+	if match[7] != "" {
+		sec := strings.SplitN(match[7], ".", 2)
+		if sec[0] != "" {
+			parts[5], err = strconv.ParseInt(sec[0], 10, 64)
+			if err != nil {
+				return
+			}
+		}
+		if len(sec) > 1 {
+			d.Nanoseconds, _ = strconv.ParseInt((sec[1] + "000000000")[:9], 10, 64)
+		}
+	}
+	d.Negative = match[1] == "-"
+	d.Months = parts[0]*12 + parts[1]
+	d.Seconds = parts[2]*86400 + parts[3]*3600 + parts[4]*60 + parts[5]
+	return
+}
+
+// NewDuration returns the xsd duration of d
+func NewDuration(d time.Duration) (dur Duration) {
+	if d < 0 {
+		dur.Negative = true
+		dur.Seconds = -int64(d / time.Second)
+		dur.Nanoseconds = -int64(d % time.Second)
+	} else {
+		dur.Seconds = int64(d / time.Second)
+		dur.Nanoseconds = int64(d % time.Second)
+	}
+	return
+}
+
+// String returns the canonical lexical form (e.g. P1Y2MT3H or PT0S)
+func (d Duration) String() (str string) {
+	if d.Months != 0 {
+		if y := d.Months / 12; y != 0 {
+			str += strconv.FormatInt(y, 10) + "Y"
+		}
+		if m := d.Months % 12; m != 0 {
+			str += strconv.FormatInt(m, 10) + "M"
+		}
+	}
+	if days := d.Seconds / 86400; days != 0 {
+		str += strconv.FormatInt(days, 10) + "D"
+	}
+	clock := ""
+	if h := d.Seconds % 86400 / 3600; h != 0 {
+		clock += strconv.FormatInt(h, 10) + "H"
+	}
+	if m := d.Seconds % 3600 / 60; m != 0 {
+		clock += strconv.FormatInt(m, 10) + "M"
+	}
+	if s := d.Seconds % 60; s != 0 || d.Nanoseconds != 0 {
+		clock += strconv.FormatInt(s, 10)
+		if d.Nanoseconds != 0 {
+			clock += strings.TrimRight(fmt.Sprintf(".%09d", d.Nanoseconds), "0")
+		}
+		clock += "S"
+	}
+	if clock != "" {
+		str += "T" + clock
+	}
+	if str == "" {
+		str = "PT0S"
+		return
+	}
+	str = "P" + str
+	if d.Negative {
+		str = "-" + str
+	}
+	return
+}
+
+// ToDuration converts the duration to time.Duration; this fails for durations with years or months
+func (d Duration) ToDuration() (td time.Duration, err error) {
+	if d.Months != 0 {
+		err = errDurationMonths
+		return
+	}
+	if d.Seconds >= int64(math.MaxInt64/time.Second) {
+		err = errDurationOverflow
+		return
+	}
+	td = time.Duration(d.Seconds)*time.Second + time.Duration(d.Nanoseconds)
+	if d.Negative {
+		td = -td
+	}
+	return
+}
+
+// IsZero reports whether d is a zero duration
+func (d Duration) IsZero() (zero bool) {
+	zero = d.Months == 0 && d.Seconds == 0 && d.Nanoseconds == 0
+	return
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateTimeTimezone(t *testing.T) {
+	tests := []struct {
+		lexical string
+		want    string
+		tz      bool
+	}{
+		{"2020-05-01T10:00:00", "2020-05-01T10:00:00", false},
+		{"2020-05-01T10:00:00.500Z", "2020-05-01T10:00:00.5Z", true},
+		{"2020-05-01T10:00:00-05:00", "2020-05-01T10:00:00-05:00", true},
+		{"2020-12-31T24:00:00", "2021-01-01T00:00:00", false},
+	}
+	for _, test := range tests {
+		dt, err := ParseDateTime(test.lexical)
+		if err != nil {
+			t.Fatalf("%s: %v", test.lexical, err)
+		}
+		if dt.String() != test.want || dt.Timezone.Valid != test.tz {
+			t.Errorf("%s: got %s (timezone %v)", test.lexical, dt, dt.Timezone.Valid)
+		}
+		lit, err := NewLiteral(dt, "")
+		if err != nil || lit.String() != test.want || lit.Datatype() != XsdDateTime {
+			t.Errorf("%s: literal %v, %v", test.lexical, lit, err)
+		}
+		if v, err := NewTypedLiteral(test.lexical, XsdDateTime).Parse(); err != nil || v != dt {
+			t.Errorf("%s: parsed %v, %v", test.lexical, v, err)
+		}
+	}
+	local, _ := ParseDateTime("2020-05-01T10:00:00")
+	if _, err := NewLiteral(local, XsdDateTimeStamp); err == nil {
+		t.Errorf("dateTimeStamp without timezone accepted")
+	}
+	if _, err := ParseDateTimeStamp("2020-05-01T10:00:00"); err == nil {
+		t.Errorf("dateTimeStamp without timezone parsed")
+	}
+	zone := time.FixedZone("", 90*60)
+	lit, _ := NewLiteral(time.Date(2020, 5, 1, 10, 0, 0, 0, zone), XsdDateTime)
+	if lit.String() != "2020-05-01T10:00:00+01:30" {
+		t.Errorf("time.Time written as %s", lit)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"bufio"
+	"io"
+)
+
+// DecodeTriG decodes a trig input to rdf quads; syntax errors are returned as *SyntaxError;
+// optional decode options set the base iri of the document
+func DecodeTriG(input io.Reader, opts ...DecodeOptions) (quad []Quad, err error) {
+	p := &parser{reader: bufio.NewReader(input), prefix: make(map[string]string),
+		blank: make(map[string]BlankNode), trig: true, base: decodeOptions(opts).Base}
+	err = p.parseRunes()
+	if err != nil {
+		return
+	}
+	err = p.parseDocument()
+	quad = p.quads
+	return
+}
+
+// EncodeTriG serializes quads in trig format
+func EncodeTriG(quad []Quad, output io.Writer) (err error) {
+	var def []Triple
+	var names []Term
+	var graphIRI []string
+	named := make(map[string][]Triple)
+	for i := range quad {
+		if quad[i].Graph == nil {
+			def = append(def, quad[i].Triple)
+			continue
+		}
+		name := quad[i].Graph.String()
+		if _, ok := named[name]; !ok {
+			names = append(names, quad[i].Graph)
+			if quad[i].Graph.Type() == TermIRI {
+				graphIRI = append(graphIRI, name)
+			}
+		}
+		named[name] = append(named[name], quad[i].Triple)
+	}
+
+	prefix := collectPrefixes(quadsToTriples(quad), nil, graphIRI...)
+	w := bufio.NewWriter(output)
+	writePrefixes(prefix, w)
+
+	for i := range def {
+		w.WriteString(def[i].SerializeTTL(prefix) + "\n")
+	}
+	for i := range names {
+		w.WriteString("\n" + names[i].SerializeTTL(prefix) + " {\n")
+		trip := named[names[i].String()]
+		for j := range trip {
+			w.WriteString("\t" + trip[j].SerializeTTL(prefix) + "\n")
+		}
+		w.WriteString("}\n")
+	}
+	err = w.Flush()
+	return
+}
+
+// parseBlock parses a trig block (triples | wrappedGraph | 'GRAPH' labelOrSubject wrappedGraph)
+func (p *parser) parseBlock(pos int) (length int, err error) {
+	if len(p.runes) <= pos {
+		err = p.eofError(pos, "graph", "reached eof before end of block")
+		return
+	}
+	var label Term
+	var tempLength int
+	if p.isKeywordFold(pos, "GRAPH") {
+		length = 5
+		length += p.consumeWS(pos + length)
+		label, tempLength, err = p.parseGraphLabel(pos + length)
+		if err != nil {
+			return
+		}
+		length += tempLength
+		length += p.consumeWS(pos + length)
+		tempLength, err = p.parseWrappedGraph(pos+length, label)
+		length += tempLength
+		return
+	}
+	if p.runes[pos] == '{' {
+		length, err = p.parseWrappedGraph(pos, nil)
+		return
+	}
+	if p.runes[pos] != '[' && p.runes[pos] != '(' {
+		// label of a wrapped graph or subject of triples
+		label, tempLength, err = p.parseGraphLabel(pos)
+		if err == nil {
+			tempLength += p.consumeWS(pos + tempLength)
+			if p.isEqual(pos+tempLength, "{") {
+				length, err = p.parseWrappedGraph(pos+tempLength, label)
+				length += tempLength
+				return
+			}
+		}
+		err = nil
+	}
+	length, err = p.parseTriples(pos)
+	return
+}
+
+// parseGraphLabel parses the label of a graph (iri | BlankNode)
+func (p *parser) parseGraphLabel(pos int) (label Term, length int, err error) {
+	if len(p.runes) <= pos+1 {
+		err = p.eofError(pos, "graph label", "reached eof before end of graph label")
+		return
+	}
+	if p.isEqual(pos, "_:") {
+		label, length, err = p.parseBlankNode(pos)
+	} else {
+		label, length, err = p.parseIRI(pos)
+	}
+	return
+}
+
+// parseWrappedGraph parses a wrapped graph ('{' triplesBlock? '}') and adds all triples to the
+// specified graph
+func (p *parser) parseWrappedGraph(pos int, label Term) (length int, err error) {
+	if len(p.runes) <= pos || p.runes[pos] != '{' {
+		err = p.syntaxError(pos, "{", "no wrapped graph; missing {")
+		return
+	}
+	p.curGraph = label
+	p.inGraph = true
+	defer func() {
+		p.curGraph = nil
+		p.inGraph = false
+	}()
+	length = 1
+	length += p.consumeWS(pos + length)
+	for !p.isEqual(pos+length, "}") {
+		if len(p.runes) <= pos+length {
+			err = p.eofError(pos+length, "}", "reached eof before end of graph; missing }")
+			return
+		}
+		var tempLength int
+		tempLength, err = p.parseTriples(pos + length)
+		if err != nil {
+			return
+		}
+		length += tempLength
+	}
+	length++
+	length += p.consumeWS(pos + length)
+	return
+}
@@ -62,6 +62,7 @@ type token struct {
 // parser parses a ttl document
 type parser struct {
 	reader       *bufio.Reader        // reader of turtle document
+	eof          bool                 // reader reached end of turtle document
 	runes        []rune               // turtle document as rune slice
 	posStatement int                  // starting position of current statement
 	prefix       map[string]string    // prefixes
@@ -71,6 +72,13 @@ type parser struct {
 	triples      []Triple             // list of all extracted triples
 	bnCounter    int                  // blank node counter
 	blank        map[string]BlankNode // blankNode map
+	trig         bool                 // parse trig document (graphs allowed)
+	inGraph      bool                 // parser is inside of a wrapped graph ('{' ... '}')
+	curGraph     Term                 // name of current graph (nil = default graph)
+	quads        []Quad               // list of all extracted quads (only trig)
+	lines        []sourceLine         // lines of the document the runes belong to
+	lineNum      int                  // number of read lines
+	dropped      int                  // number of parsed runes removed from runes (only stream)
 }
 
 // predObjList is a Predicate Object List
@@ -79,81 +87,74 @@ type predObjList struct {
 	obj  []Object
 }
 
-// DecodeTTL decodes a ttl input to rdf triples
-func DecodeTTL(input io.Reader) (trip []Triple, err error) {
+// DecodeTTL decodes a ttl input to rdf triples; syntax errors are returned as *SyntaxError;
+// optional decode options set the base iri of the document
+func DecodeTTL(input io.Reader, opts ...DecodeOptions) (trip []Triple, err error) {
+	trip, _, err = DecodeTTLPrefixes(input, opts...)
+	return
+}
+
+// DecodeTTLPrefixes decodes a ttl input to rdf triples and returns the prefixes and the base iri
+// of the document as encode options (e.g. to encode the triples with the same prefixes again);
+// optional decode options set the base iri of the document
+func DecodeTTLPrefixes(input io.Reader, opts ...DecodeOptions) (trip []Triple, opt EncodeOptions,
+	err error) {
 	p := &parser{reader: bufio.NewReader(input), prefix: make(map[string]string),
-		blank: make(map[string]BlankNode)}
+		blank: make(map[string]BlankNode), base: decodeOptions(opts).Base}
 	err = p.parseRunes()
 	if err != nil {
 		return
 	}
-	// remove whitepaces at beginning
-	p.posStatement = p.consumeWS(0)
-	for {
-		err = p.parseStatement()
-		if err != nil {
-			break
-		}
-		if p.posStatement >= len(p.runes) {
-			break
-		}
-	}
+	err = p.parseDocument()
 	trip = p.triples
+	opt = p.encodeOptions()
+	return
+}
 
+// encodeOptions returns the prefixes and the base iri read so far
+func (p *parser) encodeOptions() (opt EncodeOptions) {
+	opt.Prefixes = make(PrefixMap)
+	for i := range p.prefix {
+		opt.Prefixes[i] = p.prefix[i]
+	}
+	opt.Base = p.base
 	return
 }
 
-// parseRunes parses all runes from the reader and omits empty lines and comments
+// parseRunes parses all runes from the reader
 func (p *parser) parseRunes() (err error) {
-	eof := false
-	for {
-		var line []byte
-		line, err = p.reader.ReadBytes('\n')
+	for !p.eof {
+		err = p.readLine()
 		if err != nil {
-			if err == io.EOF {
-				err = nil
-			} else {
-				err = errors.New("Error parsing runes: " + err.Error())
-				return
-			}
-			eof = true
-		}
-		if len(line) == 0 {
-			if eof {
-				break
-			}
-			continue
+			return
 		}
-		pos := 0
-		// omit new line at end of slice (but only if not last line)
-		stop := len(line) - 1
-		if eof {
-			stop = len(line)
-		}
-		for pos < stop {
-			var r rune
-			var s int
-			r, s = utf8.DecodeRune(line[pos:])
-			if r == utf8.RuneError {
-				err = errors.New("Error parsing runes: Rune error")
-			}
-			if pos == 0 && r == '#' {
-				break
-			}
-			if r == '\t' {
-				r = ' '
-			} else if r == '\n' {
-				r = ' '
-				// continue
-			} else if r == '\r' {
-				r = ' '
-			}
-			p.runes = append(p.runes, r)
-			pos += s
+	}
+	return
+}
+
+// readLine reads the next line from the reader and appends its runes
+func (p *parser) readLine() (err error) {
+	var line []byte
+	line, err = p.reader.ReadBytes('\n')
+	if err != nil {
+		if err == io.EOF {
+			err = nil
+		} else {
+			err = errors.New("Error parsing runes: " + err.Error())
+			return
 		}
-		if eof {
-			break
+		p.eof = true
+	}
+	p.lineNum++
+	p.lines = append(p.lines, sourceLine{start: p.dropped + len(p.runes), num: p.lineNum})
+	pos := 0
+	for pos < len(line) {
+		r, s := utf8.DecodeRune(line[pos:])
+		if r == utf8.RuneError && s == 1 && err == nil {
+			err = p.syntaxError(len(p.runes), "", "invalid utf-8 encoding")
 		}
+		p.runes = append(p.runes, r)
+		pos += s
 	}
 	return
 }
@@ -164,18 +165,16 @@ func (p *parser) parseStatement() (err error) {
 		return
 	}
 	length := 0
-	switch p.runes[p.posStatement] {
-	case '@':
-		// @prefix or @base
-		length, err = p.parseDirective(p.posStatement + 1)
-		length++
+	switch {
+	case p.isKeyword(p.posStatement, "@prefix") || p.isKeyword(p.posStatement, "@base"):
+		length, err = p.parseDirective(p.posStatement)
+	case p.isKeywordFold(p.posStatement, "PREFIX") || p.isKeywordFold(p.posStatement, "BASE"):
+		// sparqlBase or sparqlPrefix
+		length, err = p.parseDirective(p.posStatement)
+	case p.trig:
+		length, err = p.parseBlock(p.posStatement)
 	default:
-		if p.isEqual(p.posStatement, "BASE ") || p.isEqual(p.posStatement, "PREFIX ") {
-			// sparqlBase or sparqlPrefix
-			length, err = p.parseDirective(p.posStatement)
-		} else {
-			length, err = p.parseTriples(p.posStatement)
-		}
+		length, err = p.parseTriples(p.posStatement)
 	}
 	if err != nil {
 		return
@@ -185,111 +184,75 @@ func (p *parser) parseStatement() (err error) {
 }
 
 // parseDirective decodes one directive (prefix, base, sparql prefix or sparql base) beginning from
-// the specified position
+// the specified position; only the turtle directives (@prefix and @base) end with a dot
 func (p *parser) parseDirective(pos int) (length int, err error) {
 	if len(p.runes) <= pos {
-		err = errors.New("reached eof before end of directive")
+		err = p.eofError(pos, "directive", "reached eof before end of directive")
 		return
 	}
-	switch p.runes[pos] {
-	case 'p':
-		var prefix, iri string
-		var tempLength int
-		if p.isEqual(pos, "prefix") {
-			length = 6
-			length += p.consumeWS(pos + length)
-			prefix, tempLength, err = p.parsePrefix(pos + length)
-			if err != nil {
-				return
-			}
-			length += tempLength
-			length += p.consumeWS(pos + length)
-			iri, tempLength, err = p.parseIRIRef(pos + length)
-			if err != nil {
-				return
-			}
-			length += tempLength
-			p.prefix[prefix] = iri
-		} else {
-			err = errors.New("wrong prefix")
-			return
-		}
-	case 'b':
-		var iri string
-		var tempLength int
-		if p.isEqual(pos, "base") {
-			length = 4
-			length += p.consumeWS(pos + length)
-			iri, tempLength, err = p.parseIRIRef(pos + length)
-			if err != nil {
-				return
-			}
-			length += tempLength
-			p.base = iri
-		} else {
-			err = errors.New("wrong base")
+	sparql := p.runes[pos] != '@'
+	if !sparql {
+		length++
+	}
+	var iri string
+	var tempLength int
+	switch {
+	case p.isKeywordFold(pos+length, "prefix"):
+		length += 6
+		length += p.consumeWS(pos + length)
+		var prefix string
+		prefix, tempLength, err = p.parsePrefix(pos + length)
+		if err != nil {
 			return
 		}
-	case 'P':
-		var prefix, iri string
-		var tempLength int
-		if p.isEqual(pos, "PREFIX") {
-			length = 6
-			length += p.consumeWS(pos + length)
-			prefix, tempLength, err = p.parsePrefix(pos + length)
-			if err != nil {
-				return
-			}
-			length += tempLength
-			length += p.consumeWS(pos + length)
-			iri, tempLength, err = p.parseIRIRef(pos + length)
-			if err != nil {
-				return
-			}
-			length += tempLength
-			p.prefix[prefix] = iri
-		} else {
-			err = errors.New("wrong PREFIX")
+		length += tempLength
+		length += p.consumeWS(pos + length)
+		iri, tempLength, err = p.parseIRIRef(pos + length)
+		if err != nil {
 			return
 		}
-	case 'B':
-		var iri string
-		var tempLength int
-		if p.isEqual(pos, "BASE") {
-			length = 4
-			length += p.consumeWS(pos + length)
-			iri, tempLength, err = p.parseIRIRef(pos + length)
-			if err != nil {
-				return
-			}
-			length += tempLength
-			p.base = iri
-		} else {
-			err = errors.New("wrong BASE")
+		length += tempLength
+		p.prefix[prefix] = iri
+	case p.isKeywordFold(pos+length, "base"):
+		length += 4
+		length += p.consumeWS(pos + length)
+		iri, tempLength, err = p.parseIRIRef(pos + length)
+		if err != nil {
 			return
 		}
+		length += tempLength
+		p.base = iri
 	default:
-		err = errors.New("Invalid directive " + string(p.runes[pos]))
+		err = p.syntaxError(pos, "prefix", "invalid directive")
 		return
 	}
-	// consumer dot
 	length += p.consumeWS(pos + length)
-	if p.isEqual(pos+length, ".") {
-		length++
-	} else {
-		err = errors.New("missing dot at end of directive")
+	if sparql {
 		return
 	}
+	// consume dot
+	if !p.isEqual(pos+length, ".") {
+		err = p.syntaxError(pos+length, ".", "missing dot at end of directive")
+		return
+	}
+	length++
 	length += p.consumeWS(pos + length)
 	return
 }
 
-// parsePrefix parses one prefix beginning from the specified position
+// parsePrefix parses one prefix including the colon (PN_PREFIX? ':') beginning from the specified
+// position
 func (p *parser) parsePrefix(pos int) (prefix string, length int, err error) {
 	if len(p.runes) <= pos {
-		err = errors.New("reached eof before end of prefix")
+		err = p.eofError(pos, "prefix", "reached eof before end of prefix")
+		return
 	}
-	prefix, length, err = p.parseUntil(pos, ':')
+	length = p.nameLength(pos, isPNCharsBase, isPNChars)
+	if !p.isEqual(pos+length, ":") {
+		err = p.syntaxError(pos+length, ":", "invalid prefix")
+		return
+	}
+	prefix = string(p.runes[pos : pos+length])
 	length++
 	return
 }
@@ -298,7 +261,7 @@ func (p *parser) parsePrefix(pos int) (prefix string, length int, err error) {
 // p.triples
 func (p *parser) parseTriples(pos int) (length int, err error) {
 	if len(p.runes) <= pos {
-		err = errors.New("reached eof before end of triples")
+		err = p.eofError(pos, "subject", "reached eof before end of triples")
 		return
 	}
 	var trip Triple
@@ -310,31 +273,33 @@ func (p *parser) parseTriples(pos int) (length int, err error) {
 	}
 	length += p.consumeWS(pos + length)
 
-	// predicateObjectList
+	// predicateObjectList (optional for a blank node property list as subject)
 	var tempLength int
 	var poList []predObjList
-	poList, tempLength, err = p.parsePredicateObjectList(pos + length)
-	if err != nil {
-		return
+	if p.runes[pos] != '[' || p.isAnon(pos) || !p.isStatementEnd(pos+length) {
+		poList, tempLength, err = p.parsePredicateObjectList(pos + length)
+		if err != nil {
+			return
+		}
+		length += tempLength
+		length += p.consumeWS(pos + length)
 	}
-	length += tempLength
-	length += p.consumeWS(pos + length)
 
 	// add triples
 	for i := range poList {
 		trip.Pred = poList[i].pred
 		for j := range poList[i].obj {
 			trip.Obj = poList[i].obj[j]
-			p.triples = append(p.triples, trip)
+			p.addTriple(trip)
 		}
 	}
 
-	// consume dot
-	length += p.consumeWS(pos + length)
+	// consume dot (optional for last triples in a wrapped graph)
 	if p.isEqual(pos+length, ".") {
 		length++
-	} else {
-		err = errors.New("missing dot at end of triples with subject " + trip.Sub.String())
+	} else if !p.inGraph || !p.isEqual(pos+length, "}") {
+		err = p.syntaxError(pos+length, ".",
+			"missing dot at end of triples with subject "+trip.Sub.String())
 		return
 	}
 	length += p.consumeWS(pos + length)
@@ -342,13 +307,19 @@ func (p *parser) parseTriples(pos int) (length int, err error) {
 	return
 }
 
+// isStatementEnd checks if the runes at the position end a statement ('.' or '}' in a graph)
+func (p *parser) isStatementEnd(pos int) (ok bool) {
+	ok = p.isEqual(pos, ".") || p.inGraph && p.isEqual(pos, "}")
+	return
+}
+
 // parseSubject parses the subject (iri | BlankNode | collection) of a triple
 func (p *parser) parseSubject(pos int) (subj Subject, length int, err error) {
-	if len(p.runes) <= pos+1 {
-		err = errors.New("reached eof before end of subject")
+	if len(p.runes) <= pos {
+		err = p.eofError(pos, "subject", "reached eof before end of subject")
 		return
 	}
-	if p.runes[pos] == '_' && p.runes[pos+1] == ':' {
+	if p.isEqual(pos, "_:") {
 		subj, length, err = p.parseBlankNode(pos)
 	} else if p.runes[pos] == '(' {
 		subj, length, err = p.parseCollection(pos)
@@ -363,7 +334,8 @@ func (p *parser) parseSubject(pos int) (subj Subject, length int, err error) {
 // parsePredicateObjectList parses a predicateObjectList (verb objectList (';' (verb objectList)?)*)
 func (p *parser) parsePredicateObjectList(pos int) (list []predObjList, length int, err error) {
 	if len(p.runes) <= pos {
-		err = errors.New("reached eof before end of predicate object list")
+		err = p.eofError(pos, "predicate",
+			"reached eof before end of predicate object list")
 		return
 	}
 	length = 0
@@ -391,27 +363,25 @@ func (p *parser) parsePredicateObjectList(pos int) (list []predObjList, length i
 		if !p.isEqual(pos+length, ";") {
 			break
 		}
-		length++
-		length += p.consumeWS(pos + length)
-		if p.isEqual(pos+length, ".") || p.isEqual(pos+length, "]") {
+		for p.isEqual(pos+length, ";") {
+			length++
+			length += p.consumeWS(pos + length)
+		}
+		if pos+length >= len(p.runes) || p.isStatementEnd(pos+length) ||
+			p.isEqual(pos+length, "]") {
 			break
 		}
 	}
 	return
 }
 
-// parsePredicate parses the next predicate
+// parsePredicate parses the next predicate (iri | 'a')
 func (p *parser) parsePredicate(pos int) (pred Predicate, length int, err error) {
 	if len(p.runes) <= pos {
-		err = errors.New("reached eof before end of predicate")
+		err = p.eofError(pos, "predicate", "reached eof before end of predicate")
 		return
 	}
-	var temp string
-	temp, _, err = p.parseUntil(pos+length, ' ')
-	if err != nil {
-		return
-	}
-	if temp == "a" {
+	if p.isKeyword(pos, "a") {
 		pred = IRI{name: "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"}
 		length = 1
 	} else {
@@ -423,7 +393,7 @@ func (p *parser) parsePredicate(pos int) (pred Predicate, length int, err error)
 // parseObjectList parses an objectList (object (',' object)*)
 func (p *parser) parseObjectList(pos int) (obj []Object, length int, err error) {
 	if len(p.runes) <= pos {
-		err = errors.New("reached eof before end of object list")
+		err = p.eofError(pos, "object", "reached eof before end of object list")
 		return
 	}
 	for {
@@ -448,20 +418,19 @@ func (p *parser) parseObjectList(pos int) (obj []Object, length int, err error)
 // parseObject parses one object (iri | BlankNode | collection | blankNodePropertyList | literal)
 func (p *parser) parseObject(pos int) (obj Object, length int, err error) {
 	if len(p.runes) <= pos {
-		err = errors.New("reached eof before end of object")
+		err = p.eofError(pos, "object", "reached eof before end of object")
 		return
 	}
-	if p.runes[pos] == '_' && p.runes[pos+1] == ':' {
+	r := p.runes[pos]
+	if p.isEqual(pos, "_:") {
 		obj, length, err = p.parseBlankNode(pos)
-	} else if p.runes[pos] == '(' {
+	} else if r == '(' {
 		obj, length, err = p.parseCollection(pos)
-	} else if p.runes[pos] == '[' {
+	} else if r == '[' {
 		obj, length, err = p.parseBlankNodePropertyList(pos)
-	} else if p.runes[pos] == '+' || p.runes[pos] == '-' || p.runes[pos] == '0' ||
-		p.runes[pos] == '1' || p.runes[pos] == '2' || p.runes[pos] == '3' || p.runes[pos] == '4' ||
-		p.runes[pos] == '5' || p.runes[pos] == '6' || p.runes[pos] == '7' || p.runes[pos] == '8' ||
-		p.runes[pos] == '9' || p.runes[pos] == '"' || p.runes[pos] == '\'' ||
-		p.isEqual(pos, "true") || p.isEqual(pos, "false") || p.runes[pos] == '.' {
+	} else if r == '"' || r == '\'' || r == '+' || r == '-' || isDigit(r) ||
+		r == '.' && pos+1 < len(p.runes) && isDigit(p.runes[pos+1]) ||
+		p.isKeyword(pos, "true") || p.isKeyword(pos, "false") {
 		obj, length, err = p.parseLiteral(pos)
 	} else {
 		obj, length, err = p.parseIRI(pos)
@@ -472,7 +441,7 @@ func (p *parser) parseObject(pos int) (obj Object, length int, err error) {
 // parseIRI parses the next iri (IRIRef | prefixedName)
 func (p *parser) parseIRI(pos int) (iri IRI, length int, err error) {
 	if len(p.runes) <= pos {
-		err = errors.New("reached eof before end of iri")
+		err = p.eofError(pos, "iri", "reached eof before end of iri")
 		return
 	}
 	var i string
@@ -488,25 +457,53 @@ func (p *parser) parseIRI(pos int) (iri IRI, length int, err error) {
 	return
 }
 
-// parseIRI parses IRIRef (<iri>)
+// parseIRIRef parses IRIRef (<iri>) and resolves relative iris against the base iri
 func (p *parser) parseIRIRef(pos int) (iri string, length int, err error) {
 	if len(p.runes) <= pos {
-		err = errors.New("reached eof before end of iri")
+		err = p.eofError(pos, "iri", "reached eof before end of iri")
 		return
 	}
 	if p.runes[pos] != '<' {
-		err = errors.New("No IRI; missing <")
+		err = p.syntaxError(pos, "<", "no iri; missing <")
 		return
 	}
-	iri, length, err = p.parseUntil(pos+1, '>')
-	length += 2
+	var r []rune
+	length = 1
+	for {
+		if len(p.runes) <= pos+length {
+			err = p.eofError(pos+length, ">", "reached eof before end of iri")
+			return
+		}
+		c := p.runes[pos+length]
+		if c == '>' {
+			break
+		}
+		if c == '\\' {
+			var tempLength int
+			c, tempLength, err = p.parseUChar(pos + length)
+			if err != nil {
+				return
+			}
+			length += tempLength
+		} else {
+			length++
+		}
+		if c <= 0x20 || strings.ContainsRune("<>\"{}|^`\\", c) {
+			err = p.syntaxError(pos+length-1, ">", "invalid character in iri")
+			return
+		}
+		r = append(r, c)
+	}
+	length++
+	// relative iri
+	iri = resolveIRI(p.base, string(r))
 	return
 }
 
 // parsePrefixedName parses prefixed name (prefix:name)
 func (p *parser) parsePrefixedName(pos int) (iri string, length int, err error) {
 	if len(p.runes) <= pos {
-		err = errors.New("reached eof before end of iri")
+		err = p.eofError(pos, "iri", "reached eof before end of iri")
 		return
 	}
 	var prefix string
@@ -516,18 +513,12 @@ func (p *parser) parsePrefixedName(pos int) (iri string, length int, err error)
 	}
 	ok := false
 	if iri, ok = p.prefix[prefix]; !ok {
-		err = errors.New("no such prefix " + prefix)
+		err = p.syntaxError(pos, "", "no such prefix "+prefix)
+		return
 	}
 	var name string
 	var tempLength int
-	name, tempLength, err = p.parseUntil(pos+length, ' ')
-	if strings.ContainsRune(name, ',') {
-		name, tempLength, err = p.parseUntil(pos+length, ',')
-	} else if strings.ContainsRune(name, ';') {
-		name, tempLength, err = p.parseUntil(pos+length, ';')
-	} else if strings.ContainsRune(name, '.') {
-		name, tempLength, err = p.parseUntil(pos+length, '.')
-	}
+	name, tempLength, err = p.parseLocalName(pos + length)
 	if err != nil {
 		return
 	}
@@ -536,15 +527,56 @@ func (p *parser) parsePrefixedName(pos int) (iri string, length int, err error)
 	return
 }
 
+// parseLocalName parses the local part of a prefixed name (PN_LOCAL) and removes escapes
+func (p *parser) parseLocalName(pos int) (name string, length int, err error) {
+	var r []rune
+	end := 0
+	i := pos
+loop:
+	for i < len(p.runes) {
+		c := p.runes[i]
+		switch {
+		case c == '\\':
+			if i+1 >= len(p.runes) || !strings.ContainsRune("_~.-!$&'()*+,;=/?#@%", p.runes[i+1]) {
+				err = p.syntaxError(i, "", "invalid escape sequence in local name")
+				return
+			}
+			r = append(r, p.runes[i+1])
+			i += 2
+		case c == '%':
+			if i+2 >= len(p.runes) || !isHex(p.runes[i+1]) || !isHex(p.runes[i+2]) {
+				err = p.syntaxError(i, "", "invalid percent encoding in local name")
+				return
+			}
+			r = append(r, p.runes[i:i+3]...)
+			i += 3
+		case c == ':' || isPNCharsU(c) || isDigit(c) || i > pos && isPNChars(c):
+			r = append(r, c)
+			i++
+		case c == '.' && i > pos:
+			r = append(r, c)
+			i++
+			continue
+		default:
+			break loop
+		}
+		// a local name must not end with a dot
+		end = i - pos
+		name = string(r)
+	}
+	length = end
+	return
+}
+
 // parseLiteral parses a literal (RDFLiteral | NumericLiteral | BooleanLiteral)
 func (p *parser) parseLiteral(pos int) (lit Literal, length int, err error) {
 	if len(p.runes) <= pos {
-		err = errors.New("reached eof before end of literal")
+		err = p.eofError(pos, "literal", "reached eof before end of literal")
 		return
 	}
 	if p.runes[pos] == '"' || p.runes[pos] == '\'' {
 		lit, length, err = p.parseRDFLiteral(pos)
-	} else if p.isEqual(pos, "true") || p.isEqual(pos, "false") {
+	} else if p.isKeyword(pos, "true") || p.isKeyword(pos, "false") {
 		lit, length, err = p.parseBooleanLiteral(pos)
 	} else {
 		lit, length, err = p.parseNumericLiteral(pos)
@@ -554,51 +586,7 @@ func (p *parser) parseLiteral(pos int) (lit Literal, length int, err error) {
 
 // parseRDFLiteral parses a rdf literal (String (LANGTAG | '^^' iri))
 func (p *parser) parseRDFLiteral(pos int) (lit Literal, length int, err error) {
-	if len(p.runes) <= pos+1 {
-		err = errors.New("reached eof before end of literal")
-		return
-	}
-	if p.runes[pos] == '"' {
-		if p.runes[pos+1] == '"' {
-			lit.str, length, err = p.parseUntil(pos+3, '"')
-			for {
-				if p.runes[pos+3+length+1] != '"' {
-					var tempLength int
-					var tempStr string
-					tempStr, tempLength, err = p.parseUntil(pos+3+length+1, '"')
-					lit.str += "\"" + tempStr
-					length += 1 + tempLength
-					continue
-				}
-				break
-			}
-			length += 6
-		} else {
-			lit.str, length, err = p.parseUntil(pos+1, '"')
-			length += 2
-		}
-	} else if p.runes[pos] != '\'' {
-		if p.runes[pos+1] == '\'' {
-			lit.str, length, err = p.parseUntil(pos+3, '\'')
-			for {
-				if p.runes[pos+3+length+1] != '\'' {
-					var tempLength int
-					var tempStr string
-					tempStr, tempLength, err = p.parseUntil(pos+3+length+1, '\'')
-					lit.str += "'" + tempStr
-					length += 1 + tempLength
-					continue
-				}
-				break
-			}
-			length += 6
-		} else {
-			lit.str, length, err = p.parseUntil(pos+1, '\'')
-			length += 2
-		}
-	} else {
-		err = errors.New("no rdf literal; missing quotes")
-	}
+	lit.str, length, err = p.parseString(pos)
 	if err != nil {
 		return
 	}
@@ -609,21 +597,15 @@ func (p *parser) parseRDFLiteral(pos int) (lit Literal, length int, err error) {
 	if p.runes[pos+length] == '@' {
 		length++
 		var tempLength int
-		lit.langTag, tempLength, err = p.parseUntil(pos+length, ' ')
-		if strings.ContainsRune(lit.langTag, ',') {
-			lit.langTag, tempLength, err = p.parseUntil(pos+length, ',')
-		} else if strings.ContainsRune(lit.langTag, ';') {
-			lit.langTag, tempLength, err = p.parseUntil(pos+length, ';')
-		} else if strings.ContainsRune(lit.langTag, '.') {
-			lit.langTag, tempLength, err = p.parseUntil(pos+length, '.')
-		}
+		lit.langTag, tempLength, err = p.parseLangTag(pos + length)
 		if err != nil {
 			return
 		}
 		length += tempLength
+		return
 	}
 	// type iri
-	if p.runes[pos+length] == '^' {
+	if p.isEqual(pos+length, "^^") {
 		length += 2
 		var tempLength int
 		var iriTemp IRI
@@ -634,175 +616,236 @@ func (p *parser) parseRDFLiteral(pos int) (lit Literal, length int, err error) {
 		lit.typeIRI = iriTemp.name
 		length += tempLength
 	}
-
 	return
 }
 
-// parseBooleanLiteral parses a boolean literal
-func (p *parser) parseBooleanLiteral(pos int) (lit Literal, length int, err error) {
+// parseString parses a string in single or double quotes (STRING_LITERAL_QUOTE,
+// STRING_LITERAL_SINGLE_QUOTE, STRING_LITERAL_LONG_QUOTE or STRING_LITERAL_LONG_SINGLE_QUOTE)
+// and removes escapes
+func (p *parser) parseString(pos int) (str string, length int, err error) {
 	if len(p.runes) <= pos {
-		err = errors.New("reached eof before end of boolean literal")
+		err = p.eofError(pos, "literal", "reached eof before end of literal")
 		return
 	}
-	if p.isEqual(pos, "true") {
-		lit = Literal{str: "true", value: true}
-		length = 4
-	} else if p.isEqual(pos, "false") {
-		lit = Literal{str: "false", value: false}
-		length = 5
-	} else {
-		err = errors.New("no boolean literal")
+	quote := p.runes[pos]
+	if quote != '"' && quote != '\'' {
+		err = p.syntaxError(pos, "\"", "no rdf literal; missing quotes")
+		return
 	}
+	delim := string(quote)
+	if p.isEqual(pos, strings.Repeat(delim, 3)) {
+		delim = strings.Repeat(delim, 3)
+	}
+	var r []rune
+	length = len(delim)
+	for {
+		if len(p.runes) <= pos+length {
+			err = p.eofError(pos+length, delim, "reached eof before end of string")
+			return
+		}
+		c := p.runes[pos+length]
+		if c == '\\' {
+			var tempLength int
+			c, tempLength, err = p.parseEscape(pos + length)
+			if err != nil {
+				return
+			}
+			r = append(r, c)
+			length += tempLength
+			continue
+		}
+		if p.isEqual(pos+length, delim) {
+			break
+		}
+		if len(delim) == 1 && (c == '\n' || c == '\r') {
+			err = p.syntaxError(pos+length, delim, "line break in string")
+			return
+		}
+		r = append(r, c)
+		length++
+	}
+	length += len(delim)
+	str = string(r)
 	return
 }
 
-// parseNumericLiteral parses a numeric literal
-func (p *parser) parseNumericLiteral(pos int) (lit Literal, length int, err error) {
-	if len(p.runes) <= pos {
-		err = errors.New("reached eof before end of numerical literl")
+// parseEscape parses an escape sequence in a string (ECHAR | UCHAR)
+func (p *parser) parseEscape(pos int) (r rune, length int, err error) {
+	if len(p.runes) <= pos+1 {
+		err = p.eofError(pos, "", "reached eof before end of escape sequence")
 		return
 	}
-	// get length of literal
-	var value float64
-	tempLength := 0
-	lit.str, length, err = p.parseUntil(pos, ' ')
-	if err != nil {
+	length = 2
+	switch p.runes[pos+1] {
+	case 't':
+		r = '\t'
+	case 'b':
+		r = '\b'
+	case 'n':
+		r = '\n'
+	case 'r':
+		r = '\r'
+	case 'f':
+		r = '\f'
+	case '"':
+		r = '"'
+	case '\'':
+		r = '\''
+	case '\\':
+		r = '\\'
+	case 'u', 'U':
+		r, length, err = p.parseUChar(pos)
+	default:
+		err = p.syntaxError(pos, "", "invalid escape sequence \\"+string(p.runes[pos+1]))
+	}
+	return
+}
+
+// parseUChar parses a \uXXXX or \UXXXXXXXX escape sequence
+func (p *parser) parseUChar(pos int) (r rune, length int, err error) {
+	if p.isEqual(pos, "\\u") {
+		length = 6
+	} else if p.isEqual(pos, "\\U") {
+		length = 10
+	}
+	if length == 0 {
+		err = p.syntaxError(pos, "", "invalid unicode escape sequence")
 		return
 	}
-	// could be object list
-	if p.runes[pos+length-1] == ',' {
-		lit.str, length, err = p.parseUntil(pos, ',')
-		if err != nil {
-			return
-		}
+	if len(p.runes) < pos+length {
+		err = p.eofError(pos, "", "reached eof before end of unicode escape sequence")
+		return
 	}
-
-	// + or -
-	if p.runes[pos] == '-' {
-		value = -1
-		tempLength++
-	} else if p.runes[pos] == '+' {
-		value = 1
-		tempLength++
-	} else {
-		value = 1
+	code, e := strconv.ParseUint(string(p.runes[pos+2:pos+length]), 16, 32)
+	if e != nil || !utf8.ValidRune(rune(code)) {
+		err = p.syntaxError(pos, "", "invalid unicode escape sequence")
+		return
 	}
+	r = rune(code)
+	return
+}
 
-	// look for number before dot or exp
-	numLen := 0
-	var num []rune
-	for i := pos + tempLength; i < pos+length; i++ {
-		if p.runes[i] == '.' || p.runes[i] == 'e' || p.runes[i] == 'E' {
-			break
-		}
-		num = append(num, p.runes[i])
-		numLen++
+// parseLangTag parses a language tag without '@' ([a-zA-Z]+ ('-' [a-zA-Z0-9]+)*)
+func (p *parser) parseLangTag(pos int) (tag string, length int, err error) {
+	for pos+length < len(p.runes) && isAlpha(p.runes[pos+length]) {
+		length++
 	}
-	if numLen > 0 {
-		var temp int
-		temp, err = strconv.Atoi(string(num))
-		if err != nil {
+	if length == 0 {
+		err = p.syntaxError(pos, "language tag", "invalid language tag")
+		return
+	}
+	for p.isEqual(pos+length, "-") {
+		sub := 0
+		for pos+length+1+sub < len(p.runes) && (isAlpha(p.runes[pos+length+1+sub]) ||
+			isDigit(p.runes[pos+length+1+sub])) {
+			sub++
+		}
+		if sub == 0 {
+			err = p.syntaxError(pos+length+1, "language tag", "invalid language tag")
 			return
 		}
-		value = value * float64(temp)
+		length += 1 + sub
+	}
+	tag = string(p.runes[pos : pos+length])
+	return
+}
+
+// parseBooleanLiteral parses a boolean literal
+func (p *parser) parseBooleanLiteral(pos int) (lit Literal, length int, err error) {
+	if len(p.runes) <= pos {
+		err = p.eofError(pos, "boolean", "reached eof before end of boolean literal")
+		return
+	}
+	if p.isKeyword(pos, "true") {
+		lit = Literal{str: "true", typeIRI: XsdBoolean, value: true}
+		length = 4
+	} else if p.isKeyword(pos, "false") {
+		lit = Literal{str: "false", typeIRI: XsdBoolean, value: false}
+		length = 5
 	} else {
-		value = 0
+		err = p.syntaxError(pos, "boolean", "no boolean literal")
 	}
-	tempLength += numLen
+	return
+}
 
-	// is integer?
-	if tempLength == length {
-		lit.value = int(value)
-		lit.typeIRI = "http://www.w3.org/2001/XMLSchema#integer"
+// parseNumericLiteral parses a numeric literal (INTEGER | DECIMAL | DOUBLE)
+func (p *parser) parseNumericLiteral(pos int) (lit Literal, length int, err error) {
+	if len(p.runes) <= pos {
+		err = p.eofError(pos, "number", "reached eof before end of numerical literal")
 		return
 	}
+	// + or -
+	if p.runes[pos] == '-' || p.runes[pos] == '+' {
+		length++
+	}
+	digits := p.digits(pos + length)
+	length += digits
+	lit.typeIRI = XsdInteger
 
-	// look for dot
-	if p.runes[pos+tempLength] == '.' {
-		tempLength++
-		numLen = 0
-		num = nil
-		for i := pos + tempLength; i < pos+length; i++ {
-			if p.runes[i] == '.' || p.runes[i] == 'e' || p.runes[i] == 'E' {
-				break
-			}
-			num = append(num, p.runes[i])
-			numLen++
-		}
-		if numLen > 0 {
-			var temp int
-			temp, err = strconv.Atoi(string(num))
-			if err != nil {
-				return
-			}
-			// divisor
-			div := 1
-			for i := 0; i < numLen; i++ {
-				div = div * 10
-			}
-			value = value + (float64(temp) / float64(div))
+	// look for dot (a dot without following digits or exponent ends the statement)
+	if p.isEqual(pos+length, ".") {
+		frac := p.digits(pos + length + 1)
+		if frac > 0 {
+			length += 1 + frac
+			lit.typeIRI = XsdDecimal
+		} else if digits > 0 && p.isExponent(pos+length+1) {
+			length++
 		}
-		tempLength += numLen
 	}
-	if tempLength == length {
-		lit.value = value
-		lit.typeIRI = "http://www.w3.org/2001/XMLSchema#decimal"
+	if digits == 0 && lit.typeIRI == XsdInteger {
+		err = p.syntaxError(pos, "number", "invalid numeric literal")
 		return
 	}
 
 	// look for exp
-	if p.runes[pos+tempLength] == 'E' || p.runes[pos+tempLength] == 'e' {
-		tempLength++
-		exp := 1.0
-		// + or -?
-		if p.runes[pos+tempLength] == '-' {
-			exp = -exp
-			tempLength++
-		} else if p.runes[pos+tempLength] == '+' {
-			tempLength++
-		}
-		numLen = 0
-		num = nil
-		for i := pos + tempLength; i < pos+length; i++ {
-			num = append(num, p.runes[i])
-			numLen++
-		}
-		if numLen > 0 {
-			var temp int
-			temp, err = strconv.Atoi(string(num))
-			if err != nil {
-				return
-			}
-			// multiplicator
-			mul := 1
-			for i := 0; i < temp; i++ {
-				mul = mul * 10
-			}
-			value = value * float64(mul)
+	if p.isExponent(pos + length) {
+		length++
+		if p.isEqual(pos+length, "-") || p.isEqual(pos+length, "+") {
+			length++
 		}
-		tempLength += numLen
+		exp := p.digits(pos + length)
+		if exp == 0 {
+			err = p.syntaxError(pos+length, "number", "invalid exponent of numeric literal")
+			return
+		}
+		length += exp
+		lit.typeIRI = XsdDouble
+	}
+	lit.str = string(p.runes[pos : pos+length])
+	return
+}
+
+// digits returns the number of consecutive digits at the position
+func (p *parser) digits(pos int) (num int) {
+	for pos+num < len(p.runes) && isDigit(p.runes[pos+num]) {
+		num++
 	}
+	return
+}
 
-	lit.value = value
-	lit.typeIRI = "http://www.w3.org/2001/XMLSchema#double"
+// isExponent checks if the rune at the position starts an exponent
+func (p *parser) isExponent(pos int) (ok bool) {
+	ok = p.isEqual(pos, "e") || p.isEqual(pos, "E")
 	return
 }
 
 // parseBlankNode parses a blank node
 func (p *parser) parseBlankNode(pos int) (blank BlankNode, length int, err error) {
 	if len(p.runes) <= pos+1 {
-		err = errors.New("reached eof before end of blank node")
+		err = p.eofError(pos, "_:", "reached eof before end of blank node")
 		return
 	}
-	if p.runes[pos] != '_' || p.runes[pos+1] != ':' {
-		err = errors.New("no blank node")
+	if !p.isEqual(pos, "_:") {
+		err = p.syntaxError(pos, "_:", "no blank node")
 		return
 	}
-	var blankName string
-	blankName, length, err = p.parseUntil(pos+2, ' ')
-	if err != nil {
+	length = p.nameLength(pos+2, func(r rune) bool { return isPNCharsU(r) || isDigit(r) },
+		isPNChars)
+	if length == 0 {
+		err = p.syntaxError(pos+2, "blank node label", "invalid blank node label")
 		return
 	}
+	blankName := string(p.runes[pos+2 : pos+2+length])
 	length += 2
 	var ok bool
 	blank, ok = p.blank[blankName]
@@ -813,19 +856,25 @@ func (p *parser) parseBlankNode(pos int) (blank BlankNode, length int, err error
 	return
 }
 
-// parseCollection parses a collection ('(' object* ')')
-func (p *parser) parseCollection(pos int) (blank BlankNode, length int, err error) {
+// parseCollection parses a collection ('(' object* ')'); the empty collection is rdf:nil
+func (p *parser) parseCollection(pos int) (list Term, length int, err error) {
 	if len(p.runes) <= pos+1 {
-		err = errors.New("reached eof before end of collection")
+		err = p.eofError(pos, ")", "reached eof before end of collection")
 		return
 	}
-	blank = p.blankNode()
 	if p.runes[pos] != '(' {
-		err = errors.New("no collection; missing (")
+		err = p.syntaxError(pos, "(", "no collection; missing (")
 		return
 	}
 	length = p.consumeWS(pos + 1)
 	length++
+	if p.isEqual(pos+length, ")") {
+		list = IRI{name: "http://www.w3.org/1999/02/22-rdf-syntax-ns#nil"}
+		length++
+		return
+	}
+	blank := p.blankNode()
+	list = blank
 	var trip Triple
 	trip.Sub = blank
 	for {
@@ -836,7 +885,7 @@ func (p *parser) parseCollection(pos int) (blank BlankNode, length int, err erro
 			return
 		}
 		length += tempLength
-		p.triples = append(p.triples, trip)
+		p.addTriple(trip)
 
 		tempLength = p.consumeWS(pos + length)
 		length += tempLength
@@ -846,29 +895,35 @@ func (p *parser) parseCollection(pos int) (blank BlankNode, length int, err erro
 		trip.Pred = IRI{name: "http://www.w3.org/1999/02/22-rdf-syntax-ns#rest"}
 		bNext := p.blankNode()
 		trip.Obj = bNext
-		p.triples = append(p.triples, trip)
+		p.addTriple(trip)
 		trip.Sub = bNext
 	}
 	trip.Pred = IRI{name: "http://www.w3.org/1999/02/22-rdf-syntax-ns#rest"}
 	trip.Obj = IRI{name: "http://www.w3.org/1999/02/22-rdf-syntax-ns#nil"}
-	p.triples = append(p.triples, trip)
+	p.addTriple(trip)
 	length++
 	return
 }
 
-// parseBlankNodePropertyList parses a blankNodePropertyList ('[' predicateObjectList ']')
+// parseBlankNodePropertyList parses a blankNodePropertyList ('[' predicateObjectList ']') or an
+// anonymous blank node ('[' ']')
 func (p *parser) parseBlankNodePropertyList(pos int) (blank BlankNode, length int, err error) {
 	if len(p.runes) <= pos+1 {
-		err = errors.New("reached eof before end of blank node property list")
+		err = p.eofError(pos, "]",
+			"reached eof before end of blank node property list")
 		return
 	}
 	if p.runes[pos] != '[' {
-		err = errors.New("no BlankNodePropertyList; missing [")
+		err = p.syntaxError(pos, "[", "no blank node property list; missing [")
 		return
 	}
 	length = p.consumeWS(pos + 1)
 	length++
 	blank = p.blankNode()
+	if p.isEqual(pos+length, "]") {
+		length++
+		return
+	}
 	var trip Triple
 	trip.Sub = blank
 
@@ -885,11 +940,11 @@ func (p *parser) parseBlankNodePropertyList(pos int) (blank BlankNode, length in
 		trip.Pred = poList[i].pred
 		for j := range poList[i].obj {
 			trip.Obj = poList[i].obj[j]
-			p.triples = append(p.triples, trip)
+			p.addTriple(trip)
 		}
 	}
-	if p.runes[pos+length] != ']' {
-		err = errors.New("no BlankNodePropertyList; missing ]")
+	if !p.isEqual(pos+length, "]") {
+		err = p.syntaxError(pos+length, "]", "no blank node property list; missing ]")
 		return
 	}
 	length++
@@ -897,6 +952,21 @@ func (p *parser) parseBlankNodePropertyList(pos int) (blank BlankNode, length in
 	return
 }
 
+// isAnon checks if the runes at the position are an anonymous blank node ('[' ']')
+func (p *parser) isAnon(pos int) (ok bool) {
+	ok = p.isEqual(pos, "[") && p.isEqual(pos+1+p.consumeWS(pos+1), "]")
+	return
+}
+
+// addTriple adds a triple to the list of extracted triples (or quads if parsing trig)
+func (p *parser) addTriple(trip Triple) {
+	if p.trig {
+		p.quads = append(p.quads, Quad{Triple: trip, Graph: p.curGraph})
+	} else {
+		p.triples = append(p.triples, trip)
+	}
+}
+
 // blankNode creates a new blank node and increments the counter
 func (p *parser) blankNode() (blank BlankNode) {
 	blank = BlankNode{name: "bn" + strconv.Itoa(p.bnCounter)}
@@ -907,11 +977,8 @@ func (p *parser) blankNode() (blank BlankNode) {
 // isEqual checks if runes at position equal specified string
 func (p *parser) isEqual(pos int, comp string) (ok bool) {
 	ok = false
-	compRune, err := toRunes([]byte(comp))
-	if err != nil {
-		return
-	}
-	if len(p.runes) < pos+len(compRune) {
+	compRune := []rune(comp)
+	if pos < 0 || len(p.runes) < pos+len(compRune) {
 		return
 	}
 	for i := range compRune {
@@ -923,53 +990,95 @@ func (p *parser) isEqual(pos int, comp string) (ok bool) {
 	return
 }
 
-// toRunes transforms a byte slice to a rune slice
-func toRunes(in []byte) (out []rune, err error) {
-	pos := 0
-	for pos < len(in) {
-		r, s := utf8.DecodeRune(in[pos:])
-		if r == utf8.RuneError {
-			err = errors.New("Rune error")
-		}
-		out = append(out, r)
-		pos += s
+// isKeyword checks if runes at position equal the keyword and the keyword is not the beginning of
+// a name (e.g. prefix "true:")
+func (p *parser) isKeyword(pos int, keyword string) (ok bool) {
+	ok = p.isEqual(pos, keyword) && !p.continuesName(pos+len(keyword))
+	return
+}
+
+// isKeywordFold checks like isKeyword but ignores the case (e.g. sparql PREFIX)
+func (p *parser) isKeywordFold(pos int, keyword string) (ok bool) {
+	if pos < 0 || len(p.runes) < pos+len(keyword) {
+		return
+	}
+	ok = strings.EqualFold(string(p.runes[pos:pos+len(keyword)]), keyword) &&
+		!p.continuesName(pos+len(keyword))
+	return
+}
+
+// continuesName checks if the rune at the position can be part of a name
+func (p *parser) continuesName(pos int) (ok bool) {
+	if len(p.runes) <= pos {
+		return
 	}
+	r := p.runes[pos]
+	ok = isPNChars(r) || r == ':' || r == '%' || r == '\\'
 	return
 }
 
-// consumeWS returns number of consecutive white spaces
+// consumeWS returns number of consecutive white spaces and comments
 func (p *parser) consumeWS(pos int) (num int) {
 	num = 0
-	for {
-		if len(p.runes) <= pos {
-			break
-		}
-		if p.runes[pos] == ' ' {
+	for len(p.runes) > pos+num {
+		switch p.runes[pos+num] {
+		case ' ', '\t', '\r', '\n':
 			num++
-			pos++
-		} else {
-			break
+		case '#':
+			for len(p.runes) > pos+num && p.runes[pos+num] != '\n' {
+				num++
+			}
+		default:
+			return
 		}
 	}
 	return
 }
 
-// parseUntil returns a string from current position to next occurance of specified rune
-func (p *parser) parseUntil(pos int, delim rune) (res string, length int, err error) {
-	length = 0
-	var r []rune
-	for {
-		if len(p.runes) <= pos+length {
-			err = errors.New("reached eof before delimiter")
-			return
+// nameLength returns the length of a name beginning at the position; the first rune must match
+// first, all other runes must match rest or be a dot (but not the last rune)
+func (p *parser) nameLength(pos int, first func(rune) bool, rest func(rune) bool) (length int) {
+	if len(p.runes) <= pos || !first(p.runes[pos]) {
+		return
+	}
+	length = 1
+	for i := pos + 1; i < len(p.runes); i++ {
+		if p.runes[i] == '.' {
+			continue
 		}
-		if p.runes[pos+length] == delim && p.runes[pos+length-1] != '\\' {
+		if !rest(p.runes[i]) {
 			break
-		} else {
-			r = append(r, p.runes[pos+length])
-			length++
 		}
+		length = i - pos + 1
 	}
-	res = string(r)
+	return
+}
+
+// isPNCharsBase checks if the rune is a PN_CHARS_BASE of the turtle grammar
+func isPNCharsBase(r rune) (ok bool) {
+	ok = r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= 0xC0 && r <= 0xD6 ||
+		r >= 0xD8 && r <= 0xF6 || r >= 0xF8 && r <= 0x2FF || r >= 0x370 && r <= 0x37D ||
+		r >= 0x37F && r <= 0x1FFF || r >= 0x200C && r <= 0x200D || r >= 0x2070 && r <= 0x218F ||
+		r >= 0x2C00 && r <= 0x2FEF || r >= 0x3001 && r <= 0xD7FF || r >= 0xF900 && r <= 0xFDCF ||
+		r >= 0xFDF0 && r <= 0xFFFD || r >= 0x10000 && r <= 0xEFFFF
+	return
+}
+
+// isPNCharsU checks if the rune is a PN_CHARS_U (PN_CHARS_BASE | '_') of the turtle grammar
+func isPNCharsU(r rune) (ok bool) {
+	ok = r == '_' || isPNCharsBase(r)
+	return
+}
+
+// isPNChars checks if the rune is a PN_CHARS of the turtle grammar
+func isPNChars(r rune) (ok bool) {
+	ok = isPNCharsU(r) || r == '-' || isDigit(r) || r == 0xB7 || r >= 0x300 && r <= 0x36F ||
+		r >= 0x203F && r <= 0x2040
+	return
+}
+
+// isHex checks if the rune is a hexadecimal digit
+func isHex(r rune) (ok bool) {
+	ok = isDigit(r) || r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F'
 	return
 }
@@ -0,0 +1,253 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+)
+
+// prettyWriter groups triples by subject and writes them in ttl format
+type prettyWriter struct {
+	w       *bufio.Writer       // buffered output
+	opt     EncodeOptions       // encode options
+	prefix  map[string]string   // prefixes
+	subj    map[string][]Triple // triples grouped by subject
+	order   []Term              // subjects in order of first occurrence
+	refs    map[string]int      // number of occurrences of blank nodes as object
+	nested  map[string]bool     // blank nodes that are written inline
+	written map[string]bool     // subjects that have already been written
+	list    map[string][]Object // blank nodes that are heads of rdf lists with list items
+}
+
+// EncodeTTLPretty serializes triples in ttl format grouped by subject; blank nodes that are used
+// only once are nested ('[ ... ]'), rdf lists are written as collections ('( ... )') and subjects
+// and predicates are sorted; optional encode options set prefixes and base iri
+func EncodeTTLPretty(triple []Triple, output io.Writer, opts ...EncodeOptions) (err error) {
+	opt := encodeOptions(opts)
+	pw := &prettyWriter{
+		w:       bufio.NewWriter(output),
+		opt:     opt,
+		prefix:  opt.prefixes(triple),
+		subj:    make(map[string][]Triple),
+		refs:    make(map[string]int),
+		nested:  make(map[string]bool),
+		written: make(map[string]bool),
+		list:    make(map[string][]Object),
+	}
+	for i := range triple {
+		name := triple[i].Sub.String()
+		if _, ok := pw.subj[name]; !ok {
+			pw.order = append(pw.order, triple[i].Sub)
+		}
+		pw.subj[name] = append(pw.subj[name], triple[i])
+		if triple[i].Obj.Type() == TermBlankNode {
+			pw.refs[triple[i].Obj.String()]++
+		}
+	}
+	for i := range pw.order {
+		name := pw.order[i].String()
+		if pw.order[i].Type() == TermBlankNode && pw.refs[name] == 1 {
+			pw.nested[name] = true
+		}
+	}
+	for i := range pw.order {
+		pw.checkList(pw.order[i])
+	}
+	sort.SliceStable(pw.order, func(i, j int) bool {
+		a, b := pw.order[i], pw.order[j]
+		if a.Type() != b.Type() {
+			return a.Type() == TermIRI
+		}
+		if a.Type() == TermIRI {
+			return a.String() < b.String()
+		}
+		return false
+	})
+
+	pw.opt.writeBase(pw.w)
+	writePrefixes(pw.prefix, pw.w)
+	if len(pw.prefix) > 0 || pw.opt.Base != "" {
+		pw.w.WriteString("\n")
+	}
+	for i := range pw.order {
+		name := pw.order[i].String()
+		if pw.nested[name] || pw.written[name] {
+			continue
+		}
+		pw.writeSubject(pw.order[i])
+	}
+	// nested blank nodes that reference each other in a cycle
+	for i := range pw.order {
+		name := pw.order[i].String()
+		if pw.written[name] {
+			continue
+		}
+		delete(pw.nested, name)
+		delete(pw.list, name)
+		pw.writeSubject(pw.order[i])
+	}
+	err = pw.w.Flush()
+	return
+}
+
+// checkList checks if a blank node is the head of a well formed rdf list that can be written as
+// collection
+func (pw *prettyWriter) checkList(head Term) {
+	var items []Object
+	var nodes []string
+	node := head
+	for {
+		name := node.String()
+		if node.Type() != TermBlankNode || !pw.nested[name] {
+			return
+		}
+		trip := pw.subj[name]
+		if len(trip) != 2 {
+			return
+		}
+		var first, rest Object
+		for i := range trip {
+			switch trip[i].Pred.String() {
+			case rdfNS + "first":
+				first = trip[i].Obj
+			case rdfNS + "rest":
+				rest = trip[i].Obj
+			}
+		}
+		if first == nil || rest == nil {
+			return
+		}
+		items = append(items, first)
+		nodes = append(nodes, name)
+		if rest.Type() == TermIRI && rest.String() == rdfNS+"nil" {
+			break
+		}
+		node = rest
+	}
+	pw.list[head.String()] = items
+	// list nodes are written as part of the collection
+	for i := range nodes {
+		pw.written[nodes[i]] = true
+	}
+}
+
+// writeSubject writes a subject with all its predicates and objects
+func (pw *prettyWriter) writeSubject(subj Term) {
+	pw.written[subj.String()] = true
+	pw.w.WriteString(pw.opt.serializeTTL(subj, pw.prefix) + " ")
+	pw.writePredicateObjectList(subj, 1)
+	pw.w.WriteString(" .\n\n")
+}
+
+// writePredicateObjectList writes all predicates and objects of subject
+func (pw *prettyWriter) writePredicateObjectList(subj Term, depth int) {
+	trip := pw.subj[subj.String()]
+	var preds []Predicate
+	objs := make(map[string][]Object)
+	for i := range trip {
+		name := trip[i].Pred.String()
+		if _, ok := objs[name]; !ok {
+			preds = append(preds, trip[i].Pred)
+		}
+		objs[name] = append(objs[name], trip[i].Obj)
+	}
+	sort.SliceStable(preds, func(i, j int) bool {
+		if preds[i].String() == rdfNS+"type" {
+			return preds[j].String() != rdfNS+"type"
+		}
+		if preds[j].String() == rdfNS+"type" {
+			return false
+		}
+		return preds[i].String() < preds[j].String()
+	})
+	indent := strings.Repeat("\t", depth)
+	for i := range preds {
+		if i > 0 {
+			pw.w.WriteString(" ;\n" + indent)
+		}
+		if preds[i].String() == rdfNS+"type" {
+			pw.w.WriteString("a ")
+		} else {
+			pw.w.WriteString(pw.opt.serializeTTL(preds[i], pw.prefix) + " ")
+		}
+		obj := objs[preds[i].String()]
+		sort.SliceStable(obj, func(i, j int) bool {
+			return obj[i].String() < obj[j].String()
+		})
+		for j := range obj {
+			if j > 0 {
+				pw.w.WriteString(", ")
+			}
+			pw.writeObject(obj[j], depth)
+		}
+	}
+}
+
+// writeObject writes an object; nested blank nodes and lists are written inline
+func (pw *prettyWriter) writeObject(obj Term, depth int) {
+	name := obj.String()
+	if obj.Type() != TermBlankNode || !pw.nested[name] || pw.written[name] &&
+		pw.list[name] == nil {
+		pw.w.WriteString(pw.opt.serializeTTL(obj, pw.prefix))
+		return
+	}
+	if items, ok := pw.list[name]; ok {
+		pw.w.WriteString("(")
+		for i := range items {
+			pw.w.WriteString(" ")
+			pw.writeObject(items[i], depth)
+		}
+		pw.w.WriteString(" )")
+		return
+	}
+	pw.written[name] = true
+	indent := strings.Repeat("\t", depth)
+	pw.w.WriteString("[\n" + indent + "\t")
+	pw.writePredicateObjectList(obj, depth+1)
+	pw.w.WriteString("\n" + indent + "]")
+}
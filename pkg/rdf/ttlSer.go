@@ -46,74 +46,110 @@ package rdf
 
 import (
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 )
 
-// EncodeTTL serializes triples in ttl format
-func EncodeTTL(triple []Triple, output io.Writer) (err error) {
-	prefix := make(map[string]string)
+// EncodeTTL serializes triples in ttl format; optional encode options set prefixes and base iri
+func EncodeTTL(triple []Triple, output io.Writer, opts ...EncodeOptions) (err error) {
+	opt := encodeOptions(opts)
+	prefix := opt.prefixes(triple)
+	opt.writeBase(output)
+	writePrefixes(prefix, output)
+
+	for i := range triple {
+		_, err = output.Write([]byte(opt.serializeTripleTTL(triple[i], prefix) + "\n"))
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// collectPrefixes determines prefixes for all iris used in the triples and the additional iris;
+// namespaces for which skip returns true get no prefix (skip may be nil)
+func collectPrefixes(triple []Triple, skip func(string) bool, extra ...string) (
+	prefix map[string]string) {
+	prefix = make(map[string]string)
 	singleOccurrence := make(map[string]interface{})
 	prCounter := 0
+	check := func(iri string) {
+		if skip == nil || !skip(getPrefix(iri)) {
+			prCounter = checkPrefix(iri, prefix, singleOccurrence, prCounter)
+		}
+	}
 	for i := range triple {
 		if triple[i].Sub.Type() == TermIRI {
-			iri := triple[i].Sub.(IRI).name
-			prCounter = checkPrefix(iri, prefix, singleOccurrence, prCounter)
+			check(triple[i].Sub.(IRI).name)
 		}
 		if triple[i].Pred.Type() == TermIRI {
-			iri := triple[i].Pred.(IRI).name
-			prCounter = checkPrefix(iri, prefix, singleOccurrence, prCounter)
+			check(triple[i].Pred.(IRI).name)
 		}
 		if triple[i].Obj.Type() == TermIRI {
-			iri := triple[i].Obj.(IRI).name
-			prCounter = checkPrefix(iri, prefix, singleOccurrence, prCounter)
+			check(triple[i].Obj.(IRI).name)
 		} else if triple[i].Obj.Type() == TermLiteral && triple[i].Obj.(Literal).typeIRI != "" {
-			iri := triple[i].Obj.(Literal).typeIRI
-			prCounter = checkPrefix(iri, prefix, singleOccurrence, prCounter)
+			check(triple[i].Obj.(Literal).typeIRI)
 		}
 	}
+	for i := range extra {
+		check(extra[i])
+	}
+	return
+}
 
+// writePrefixes writes the prefix directives sorted by prefix name
+func writePrefixes(prefix map[string]string, output io.Writer) {
+	iri := make([]string, 0, len(prefix))
 	for i := range prefix {
-		output.Write([]byte("@prefix " + prefix[i] + ": <" + i + "> .\n"))
+		iri = append(iri, i)
 	}
-
-	for i := range triple {
-		_, err = output.Write([]byte(triple[i].SerializeTTL(prefix) + "\n"))
-		if err != nil {
-			return
-		}
+	sort.Slice(iri, func(i, j int) bool {
+		return prefix[iri[i]] < prefix[iri[j]]
+	})
+	for i := range iri {
+		output.Write([]byte("@prefix " + prefix[iri[i]] + ": <" + escapeIRI(iri[i]) + "> .\n"))
 	}
-	return
 }
 
-// SerializeTTL serializes IRI in ttl format
+// SerializeTTL serializes IRI in ttl format; a prefixed name is used if the iri starts with a
+// prefix and the rest is a valid local name
 func (iri IRI) SerializeTTL(prefix map[string]string) (ret string) {
 	pr := getPrefix(iri.name)
-	if temp, ok := prefix[pr]; ok {
-		rest := strings.Split(iri.name, pr)
-		ret = temp + ":" + rest[len(rest)-1]
+	if temp, ok := prefix[pr]; ok && isLocalName(strings.TrimPrefix(iri.name, pr)) {
+		ret = temp + ":" + strings.TrimPrefix(iri.name, pr)
 	} else {
-		ret = "<" + iri.name + ">"
+		ret = "<" + escapeIRI(iri.name) + ">"
 	}
 	return
 }
 
 // SerializeTTL serializes Literal in ttl format
 func (lit Literal) SerializeTTL(prefix map[string]string) (ret string) {
-	ret = "\"" + lit.str + "\""
+	ret = "\"" + escapeString(lit.str) + "\""
 	if lit.langTag != "" {
 		ret += "@" + lit.langTag
 	}
 	if lit.typeIRI != "" {
-		ret += "^^"
-		pr := getPrefix(lit.typeIRI)
-		if temp, ok := prefix[pr]; ok {
-			rest := strings.Split(lit.typeIRI, pr)
-			ret += temp + ":" + rest[len(rest)-1]
-		} else {
-			ret += "<" + lit.typeIRI + ">"
+		ret += "^^" + IRI{name: lit.typeIRI}.SerializeTTL(prefix)
+	}
+	return
+}
+
+// isLocalName checks if a string can be written as local part of a prefixed name without escapes
+func isLocalName(name string) (ok bool) {
+	r := []rune(name)
+	for i := range r {
+		switch {
+		case r[i] == ':' || isPNCharsU(r[i]) || isDigit(r[i]):
+		case i > 0 && isPNChars(r[i]):
+		case i > 0 && i < len(r)-1 && r[i] == '.':
+		case r[i] == '%' && i+2 < len(r) && isHex(r[i+1]) && isHex(r[i+2]):
+		default:
+			return
 		}
 	}
+	ok = true
 	return
 }
 
@@ -0,0 +1,188 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"bufio"
+	"io"
+)
+
+// TripleHandler is called for every triple of a streamed ttl document
+type TripleHandler func(trip Triple) (err error)
+
+// Decoder decodes a ttl document statement by statement; only the current statement is held in
+// memory while prefixes and blank nodes are kept for the whole document
+type Decoder struct {
+	p       *parser  // ttl parser
+	pending []Triple // decoded but not yet returned triples
+	done    bool     // end of document reached
+}
+
+// NewDecoder creates a new streaming ttl decoder reading from input
+func NewDecoder(input io.Reader) (dec *Decoder) {
+	dec = &Decoder{
+		p: &parser{reader: bufio.NewReader(input), prefix: make(map[string]string),
+			blank: make(map[string]BlankNode)},
+	}
+	return
+}
+
+// Next returns the next triple of the document; io.EOF is returned after the last triple
+func (dec *Decoder) Next() (trip Triple, err error) {
+	for len(dec.pending) == 0 {
+		if dec.done {
+			err = io.EOF
+			return
+		}
+		dec.pending, dec.done, err = dec.p.nextStatement()
+		if err != nil {
+			dec.done = true
+			dec.pending = nil
+			return
+		}
+	}
+	trip = dec.pending[0]
+	dec.pending = dec.pending[1:]
+	return
+}
+
+// Prefixes returns the prefixes read so far
+func (dec *Decoder) Prefixes() (prefix PrefixMap) {
+	prefix = dec.p.encodeOptions().Prefixes
+	return
+}
+
+// Base returns the current base iri
+func (dec *Decoder) Base() (base string) {
+	base = dec.p.base
+	return
+}
+
+// DecodeGraph decodes all remaining triples into a graph
+func (dec *Decoder) DecodeGraph() (graph Graph, err error) {
+	graph.Nodes = make(map[string]*Node)
+	for {
+		var trip Triple
+		trip, err = dec.Next()
+		if err == io.EOF {
+			err = nil
+			return
+		}
+		if err != nil {
+			return
+		}
+		graph.AddTriple(trip)
+	}
+}
+
+// DecodeTTLStream decodes a ttl input and calls handler for every triple as soon as its statement
+// is complete; decoding stops at the first error returned by handler
+func DecodeTTLStream(input io.Reader, handler TripleHandler) (err error) {
+	dec := NewDecoder(input)
+	for {
+		var trip Triple
+		trip, err = dec.Next()
+		if err == io.EOF {
+			err = nil
+			return
+		}
+		if err != nil {
+			return
+		}
+		err = handler(trip)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// NewGraphFromTTL creates a graph from a ttl input without collecting all triples first
+func NewGraphFromTTL(input io.Reader) (graph Graph, err error) {
+	graph, err = NewDecoder(input).DecodeGraph()
+	return
+}
+
+// nextStatement reads lines until the next statement is complete, parses it and returns its
+// triples; done is set if the end of the document is reached
+func (p *parser) nextStatement() (trip []Triple, done bool, err error) {
+	for {
+		p.posStatement += p.consumeWS(p.posStatement)
+		if p.posStatement < len(p.runes) && (p.eof || p.statementEnd()) {
+			err = p.parseStatement()
+			if err == nil {
+				trip = p.triples
+				p.triples = nil
+				// drop parsed runes
+				p.dropRunes(p.posStatement)
+				return
+			}
+			// statement is not complete yet (e.g. '.' at the end of a line of a multi-line string)
+			p.triples = nil
+			if p.eof || !isIncomplete(err) {
+				return
+			}
+			err = nil
+		} else if p.eof {
+			done = true
+			return
+		}
+		err = p.readLine()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// statementEnd checks if the read runes end with a statement terminator
+func (p *parser) statementEnd() (ok bool) {
+	for i := len(p.runes) - 1; i >= p.posStatement; i-- {
+		switch p.runes[i] {
+		case ' ', '\t', '\r', '\n':
+		default:
+			return p.runes[i] == '.'
+		}
+	}
+	return
+}
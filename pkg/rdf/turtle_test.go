@@ -0,0 +1,240 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/internal/testsuite"
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// turtleTests is the base iri of the turtle fixtures in testdata/turtle (written for this package
+// in the format of the W3C turtle test suite)
+const turtleTests = "http://example.org/owl2go/turtle/"
+
+func TestTurtleFixtures(t *testing.T) {
+	dir := filepath.Join("testdata", "turtle")
+	_, entries := testsuite.ReadManifest(t, filepath.Join(dir, "manifest.ttl"),
+		turtleTests+"manifest.ttl")
+	for _, test := range entries {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			action := testsuite.File(test.Action, turtleTests)
+			trips, err := testsuite.DecodeTTLFile(t, filepath.Join(dir, action),
+				turtleTests+action)
+			switch test.Type {
+			case "TestTurtlePositiveSyntax":
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			case "TestTurtleNegativeSyntax", "TestTurtleNegativeEval":
+				if err == nil {
+					t.Errorf("expected error, got %d triples", len(trips))
+				}
+			case "TestTurtleEval":
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				result := testsuite.File(test.Result, turtleTests)
+				data, err := ioutil.ReadFile(filepath.Join(dir, result))
+				if err != nil {
+					t.Fatal(err)
+				}
+				want, err := rdf.DecodeNTriples(bytes.NewReader(data))
+				if err != nil {
+					t.Fatalf("%s: %v", result, err)
+				}
+				got, _ := rdf.NewGraph(trips)
+				exp, _ := rdf.NewGraph(want)
+				if !rdf.Isomorphic(&got, &exp) {
+					var buf bytes.Buffer
+					rdf.EncodeNTriples(trips, &buf)
+					t.Errorf("graphs differ, got:\n%s", buf.String())
+				}
+			default:
+				t.Fatalf("unknown test type %s", test.Type)
+			}
+		})
+	}
+}
+
+func TestEncodeBase(t *testing.T) {
+	input := `@prefix ex: <http://example.org/onto#> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+ex:a ex:knows ex:b .
+ex:b ex:size "3"^^ex:unit .
+ex:b ex:name "b" .
+<http://example.org/other#c> ex:knows <http://other.org/d> .
+`
+	trips, err := rdf.DecodeTTL(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		opt  rdf.EncodeOptions
+		want []string // lines of the flat encoding
+	}{
+		{
+			name: "document",
+			opt:  rdf.EncodeOptions{Base: "http://example.org/onto"},
+			want: []string{
+				"@base <http://example.org/onto> .",
+				"<#a> <#knows> <#b> .",
+				"<#b> <#size> \"3\"^^<#unit> .",
+				"<other#c> <#knows> <http://other.org/d> .",
+			},
+		},
+		{
+			name: "fragment",
+			opt:  rdf.EncodeOptions{Base: "http://example.org/onto#"},
+			want: []string{"<#a> <#knows> <#b> ."},
+		},
+		{
+			name: "declared prefix",
+			opt: rdf.EncodeOptions{Base: "http://example.org/onto",
+				Prefixes: rdf.PrefixMap{"ex": "http://example.org/onto#"}},
+			want: []string{
+				"@prefix ex: <http://example.org/onto#> .",
+				"ex:a ex:knows ex:b .",
+				"<other#c> ex:knows <http://other.org/d> .",
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			for _, pretty := range []bool{false, true} {
+				var buf bytes.Buffer
+				if pretty {
+					err = rdf.EncodeTTLPretty(trips, &buf, test.opt)
+				} else {
+					err = rdf.EncodeTTL(trips, &buf, test.opt)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				out := buf.String()
+				if strings.Contains(out, "pr0:") {
+					t.Errorf("generated prefix for base namespace:\n%s", out)
+				}
+				lines := strings.Split(out, "\n")
+				for _, line := range test.want {
+					if !pretty && !contains(lines, line) {
+						t.Errorf("missing line %q in:\n%s", line, out)
+					}
+				}
+				dec, err := rdf.DecodeTTL(strings.NewReader(out))
+				if err != nil {
+					t.Fatalf("%v in:\n%s", err, out)
+				}
+				got, _ := rdf.NewGraph(dec)
+				exp, _ := rdf.NewGraph(trips)
+				if !rdf.Isomorphic(&got, &exp) {
+					t.Errorf("round trip differs:\n%s", out)
+				}
+			}
+		})
+	}
+}
+
+// contains checks if the string is in the slice
+func contains(list []string, s string) (ok bool) {
+	for i := range list {
+		if list[i] == s {
+			ok = true
+			return
+		}
+	}
+	return
+}
+
+// streamInput is a document with statements that continue after a line ending with a dot
+const streamInput = `@prefix ex: <http://example.org/> .
+ex:a ex:text """first line.
+second line.""" ; # comment ending with a dot.
+	ex:list ( 1.5 "x" [ ex:p ex:q ] ) .
+_:b ex:name "bé" ; ex:size 2.
+<http://example.org/c> a ex:C .
+`
+
+func TestDecoderIncomplete(t *testing.T) {
+	want, err := rdf.DecodeTTL(strings.NewReader(streamInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []rdf.Triple
+	err = rdf.DecodeTTLStream(strings.NewReader(streamInput), func(trip rdf.Triple) (err error) {
+		got = append(got, trip)
+		return
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g1, _ := rdf.NewGraph(got)
+	g2, _ := rdf.NewGraph(want)
+	if !rdf.Isomorphic(&g1, &g2) {
+		t.Errorf("streamed triples differ: got %d, want %d", len(got), len(want))
+	}
+
+	// truncated documents end with an error instead of a panic
+	runes := []rune(streamInput)
+	for i := range runes {
+		input := string(runes[:i])
+		rdf.DecodeTriG(strings.NewReader(input))
+		if _, err = rdf.DecodeTTL(strings.NewReader(input)); err == nil {
+			continue
+		}
+		err = rdf.DecodeTTLStream(strings.NewReader(input), func(rdf.Triple) (err error) { return })
+		if err == nil {
+			t.Errorf("no error for truncated input %q", input)
+		}
+	}
+}
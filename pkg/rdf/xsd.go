@@ -0,0 +1,907 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rdf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Datatype describes a literal datatype: the Go type of its values, how lexical forms are validated
+// and converted to values and how values are written in canonical lexical form
+type Datatype struct {
+	IRI    string // datatype iri
+	GoType string // Go type of the values (as used in generated code)
+	parse  func(lexical string) (value interface{}, err error)
+	format func(value interface{}) (lexical string, err error)
+}
+
+// datatypes is the registry of known datatypes (key: datatype iri)
+var datatypes = xsdDatatypes()
+
+// lexical forms of xsd datatypes
+var (
+	integerRegexp  = regexp.MustCompile(`^[+-]?[0-9]+$`)
+	decimalRegexp  = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+	floatRegexp    = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+	languageRegexp = regexp.MustCompile(`^[a-zA-Z]{1,8}(-[a-zA-Z0-9]{1,8})*$`)
+	nameRegexp     = regexp.MustCompile(`^[\pL_:][\pL\pN_:.\-\x{B7}]*$`)
+	ncNameRegexp   = regexp.MustCompile(`^[\pL_][\pL\pN_.\-\x{B7}]*$`)
+	nmtokenRegexp  = regexp.MustCompile(`^[\pL\pN_:.\-\x{B7}]+$`)
+)
+
+// RegisterDatatype adds a datatype to the registry (or replaces a registered one); parse converts
+// a lexical form to a value of type goType and format returns the canonical lexical form of a value
+func RegisterDatatype(iri string, goType string,
+	parse func(lexical string) (value interface{}, err error),
+	format func(value interface{}) (lexical string, err error)) {
+	datatypes[iri] = Datatype{IRI: iri, GoType: goType, parse: parse, format: format}
+	return
+}
+
+// LookupDatatype returns the registered datatype with the specified iri
+func LookupDatatype(iri string) (dt Datatype, ok bool) {
+	dt, ok = datatypes[iri]
+	return
+}
+
+// Datatypes returns all registered datatypes sorted by iri
+func Datatypes() (dts []Datatype) {
+	for i := range datatypes {
+		dts = append(dts, datatypes[i])
+	}
+	sort.Slice(dts, func(i, j int) bool {
+		return dts[i].IRI < dts[j].IRI
+	})
+	return
+}
+
+// Parse validates a lexical form and converts it to a value of the Go type of the datatype
+func (dt Datatype) Parse(lexical string) (value interface{}, err error) {
+	value, err = dt.parse(lexical)
+	if err != nil {
+		value = nil
+		err = errors.New("invalid lexical form \"" + lexical + "\" of datatype " + dt.IRI + ": " +
+			err.Error())
+	}
+	return
+}
+
+// Format returns the canonical lexical form of a value; besides the Go type of the datatype all
+// types that can be converted without loss are accepted (e.g. int for xsd:integer)
+func (dt Datatype) Format(value interface{}) (lexical string, err error) {
+	if str, ok := value.(string); ok && dt.GoType != "string" {
+		value, err = dt.Parse(str)
+		if err != nil {
+			return
+		}
+	}
+	lexical, err = dt.format(value)
+	if err != nil {
+		err = errors.New("invalid value " + fmt.Sprintf("%v", value) + " of datatype " + dt.IRI +
+			": " + err.Error())
+	}
+	return
+}
+
+// Canonical returns the canonical representation of a lexical form
+func (dt Datatype) Canonical(lexical string) (canonical string, err error) {
+	value, err := dt.Parse(lexical)
+	if err != nil {
+		return
+	}
+	canonical, err = dt.format(value)
+	return
+}
+
+// EqualValues compares two literal values; big numbers and decimals are compared by value
+func EqualValues(a interface{}, b interface{}) (equal bool) {
+	switch t := a.(type) {
+	case *big.Int:
+		other, ok := b.(*big.Int)
+		equal = ok && (t == other || t != nil && other != nil && t.Cmp(other) == 0)
+	case Decimal:
+		other, ok := b.(Decimal)
+		equal = ok && t.Cmp(other) == 0
+	case []byte:
+		other, ok := b.([]byte)
+		equal = ok && bytes.Equal(t, other)
+	case *url.URL:
+		other, ok := b.(*url.URL)
+		equal = ok && (t == other || t != nil && other != nil && t.String() == other.String())
+	default:
+		equal = a == b
+	}
+	return
+}
+
+// xsdDatatypes returns the registry of all supported xsd datatypes
+func xsdDatatypes() (dts map[string]Datatype) {
+	dts = make(map[string]Datatype)
+	add := func(dt Datatype) {
+		dts[dt.IRI] = dt
+	}
+
+	// strings
+	add(stringDatatype(XsdString, nil))
+	add(Datatype{IRI: RdfLangString, GoType: "rdf.LangString", parse: parseLangString,
+		format: formatLangString})
+	add(stringDatatype(XsdNormalizedString, isNormalizedString))
+	add(stringDatatype(XsdToken, isToken))
+	add(stringDatatype(XsdLanguage, languageRegexp.MatchString))
+	add(stringDatatype(XsdName, nameRegexp.MatchString))
+	add(stringDatatype(XsdNCName, ncNameRegexp.MatchString))
+	add(stringDatatype(XsdNMTOKEN, nmtokenRegexp.MatchString))
+
+	// boolean
+	add(Datatype{IRI: XsdBoolean, GoType: "bool", parse: parseBoolean, format: formatBoolean})
+
+	// numbers
+	add(Datatype{IRI: XsdDecimal, GoType: "rdf.Decimal", parse: parseDecimal,
+		format: formatDecimal})
+	add(floatDatatype(XsdFloat, 32))
+	add(floatDatatype(XsdDouble, 64))
+	add(integerDatatype(XsdInteger, "*big.Int", "", ""))
+	add(integerDatatype(XsdNonPositiveInteger, "*big.Int", "", "0"))
+	add(integerDatatype(XsdNegativeInteger, "*big.Int", "", "-1"))
+	add(integerDatatype(XsdNonNegativeInteger, "*big.Int", "0", ""))
+	add(integerDatatype(XsdPositiveInteger, "*big.Int", "1", ""))
+	add(integerDatatype(XsdLong, "int64", "-9223372036854775808", "9223372036854775807"))
+	add(integerDatatype(XsdInt, "int32", "-2147483648", "2147483647"))
+	add(integerDatatype(XsdShort, "int16", "-32768", "32767"))
+	add(integerDatatype(XsdByte, "int8", "-128", "127"))
+	add(integerDatatype(XsdUnsignedLong, "uint64", "0", "18446744073709551615"))
+	add(integerDatatype(XsdUnsignedInt, "uint32", "0", "4294967295"))
+	add(integerDatatype(XsdUnsignedShort, "uint16", "0", "65535"))
+	add(integerDatatype(XsdUnsignedByte, "uint8", "0", "255"))
+
+	// binary data and uris
+	add(Datatype{IRI: XsdHexBinary, GoType: "[]byte", parse: parseHexBinary,
+		format: formatHexBinary})
+	add(Datatype{IRI: XsdBase64Binary, GoType: "[]byte", parse: parseBase64Binary,
+		format: formatBase64Binary})
+	add(Datatype{IRI: XsdAnyURI, GoType: "*url.URL", parse: parseAnyURI, format: formatAnyURI})
+
+	// date, time and duration
+	add(dateTimeDatatype(XsdDateTime, parseDateTime))
+	add(dateTimeDatatype(XsdDateTimeStamp, parseDateTimeStamp))
+	add(temporalDatatype(XsdDate, "rdf.Date", parseDate, func(t time.Time) fmt.Stringer {
+		return NewDate(t)
+	}))
+	add(temporalDatatype(XsdTime, "rdf.Time", parseTime, func(t time.Time) fmt.Stringer {
+		return NewTime(t)
+	}))
+	add(temporalDatatype(XsdYear, "rdf.GYear", parseGYear, func(t time.Time) fmt.Stringer {
+		return NewGYear(t)
+	}))
+	add(temporalDatatype(XsdYearMonth, "rdf.GYearMonth", parseGYearMonth,
+		func(t time.Time) fmt.Stringer {
+			return NewGYearMonth(t)
+		}))
+	add(temporalDatatype(XsdMonth, "rdf.GMonth", parseGMonth, func(t time.Time) fmt.Stringer {
+		return NewGMonth(t)
+	}))
+	add(temporalDatatype(XsdMonthDay, "rdf.GMonthDay", parseGMonthDay,
+		func(t time.Time) fmt.Stringer {
+			return NewGMonthDay(t)
+		}))
+	add(temporalDatatype(XsdDay, "rdf.GDay", parseGDay, func(t time.Time) fmt.Stringer {
+		return NewGDay(t)
+	}))
+	add(durationDatatype(XsdDuration, nil))
+	add(durationDatatype(XsdDayTimeDuration, func(d Duration) bool {
+		return d.Months == 0
+	}))
+	add(durationDatatype(XsdYearMonthDuration, func(d Duration) bool {
+		return d.Seconds == 0 && d.Nanoseconds == 0
+	}))
+	return
+}
+
+// stringDatatype returns a string based datatype; valid checks the lexical form (nil accepts all)
+func stringDatatype(iri string, valid func(str string) bool) (dt Datatype) {
+	dt = Datatype{IRI: iri, GoType: "string"}
+	dt.parse = func(lexical string) (value interface{}, err error) {
+		if valid != nil && !valid(lexical) {
+			err = errors.New("pattern mismatch")
+			return
+		}
+		value = lexical
+		return
+	}
+	dt.format = func(value interface{}) (lexical string, err error) {
+		str, ok := value.(string)
+		if !ok {
+			err = errors.New("no string")
+			return
+		}
+		if valid != nil && !valid(str) {
+			err = errors.New("pattern mismatch")
+			return
+		}
+		lexical = str
+		return
+	}
+	return
+}
+
+// LangString is a rdf:langString value (a string with language tag)
+type LangString struct {
+	Text string
+	Lang string
+}
+
+// String returns the text with language tag (e.g. chat@fr)
+func (s LangString) String() (str string) {
+	str = s.Text + "@" + s.Lang
+	return
+}
+
+// IsZero reports whether s is the zero value
+func (s LangString) IsZero() (zero bool) {
+	zero = s == LangString{}
+	return
+}
+
+// parseLangString parses the lexical form of a rdf:langString (the language tag is set by
+// Literal.Parse)
+func parseLangString(lexical string) (value interface{}, err error) {
+	value = LangString{Text: lexical}
+	return
+}
+
+// formatLangString writes the text of a rdf:langString; values without language tag are rejected
+func formatLangString(value interface{}) (lexical string, err error) {
+	s, ok := value.(LangString)
+	if !ok || !languageRegexp.MatchString(s.Lang) {
+		err = errors.New("no string with language tag")
+		return
+	}
+	lexical = s.Text
+	return
+}
+
+// isNormalizedString checks that str contains no carriage return, line feed or tab
+func isNormalizedString(str string) (ok bool) {
+	ok = !strings.ContainsAny(str, "\r\n\t")
+	return
+}
+
+// isToken checks that str is normalized and contains no leading, trailing or double spaces
+func isToken(str string) (ok bool) {
+	ok = isNormalizedString(str) && strings.TrimSpace(str) == str &&
+		!strings.Contains(str, "  ")
+	return
+}
+
+// parseBoolean parses a xsd:boolean
+func parseBoolean(lexical string) (value interface{}, err error) {
+	switch strings.TrimSpace(lexical) {
+	case "true", "1":
+		value = true
+	case "false", "0":
+		value = false
+	default:
+		err = errors.New("no boolean")
+	}
+	return
+}
+
+// formatBoolean writes a xsd:boolean
+func formatBoolean(value interface{}) (lexical string, err error) {
+	b, ok := value.(bool)
+	if !ok {
+		err = errors.New("no boolean")
+		return
+	}
+	lexical = strconv.FormatBool(b)
+	return
+}
+
+// integerDatatype returns a datatype derived from xsd:integer with values in [min, max] (empty
+// bounds are unbounded) and the Go type goType
+func integerDatatype(iri string, goType string, min string, max string) (dt Datatype) {
+	var lo, hi *big.Int
+	if min != "" {
+		lo, _ = new(big.Int).SetString(min, 10)
+	}
+	if max != "" {
+		hi, _ = new(big.Int).SetString(max, 10)
+	}
+	inRange := func(i *big.Int) (err error) {
+		if lo != nil && i.Cmp(lo) < 0 || hi != nil && i.Cmp(hi) > 0 {
+			err = errors.New("out of range")
+		}
+		return
+	}
+	dt = Datatype{IRI: iri, GoType: goType}
+	dt.parse = func(lexical string) (value interface{}, err error) {
+		str := strings.TrimSpace(lexical)
+		if !integerRegexp.MatchString(str) {
+			err = errors.New("no integer")
+			return
+		}
+		i, _ := new(big.Int).SetString(str, 10)
+		if err = inRange(i); err != nil {
+			return
+		}
+		switch goType {
+		case "int64":
+			value = i.Int64()
+		case "int32":
+			value = int32(i.Int64())
+		case "int16":
+			value = int16(i.Int64())
+		case "int8":
+			value = int8(i.Int64())
+		case "uint64":
+			value = i.Uint64()
+		case "uint32":
+			value = uint32(i.Uint64())
+		case "uint16":
+			value = uint16(i.Uint64())
+		case "uint8":
+			value = uint8(i.Uint64())
+		default:
+			value = i
+		}
+		return
+	}
+	dt.format = func(value interface{}) (lexical string, err error) {
+		i, ok := toBigInt(value)
+		if !ok {
+			err = errors.New("no integer")
+			return
+		}
+		if err = inRange(i); err != nil {
+			return
+		}
+		lexical = i.String()
+		return
+	}
+	return
+}
+
+// toBigInt converts Go integers to *big.Int
+func toBigInt(value interface{}) (i *big.Int, ok bool) {
+	ok = true
+	switch t := value.(type) {
+	case int:
+		i = big.NewInt(int64(t))
+	case int8:
+		i = big.NewInt(int64(t))
+	case int16:
+		i = big.NewInt(int64(t))
+	case int32:
+		i = big.NewInt(int64(t))
+	case int64:
+		i = big.NewInt(t)
+	case uint:
+		i = new(big.Int).SetUint64(uint64(t))
+	case uint8:
+		i = new(big.Int).SetUint64(uint64(t))
+	case uint16:
+		i = new(big.Int).SetUint64(uint64(t))
+	case uint32:
+		i = new(big.Int).SetUint64(uint64(t))
+	case uint64:
+		i = new(big.Int).SetUint64(t)
+	case *big.Int:
+		ok = t != nil
+		i = t
+	case big.Int:
+		i = &t
+	default:
+		ok = false
+	}
+	return
+}
+
+// floatDatatype returns xsd:float (bits = 32) or xsd:double (bits = 64)
+func floatDatatype(iri string, bits int) (dt Datatype) {
+	dt = Datatype{IRI: iri, GoType: "float64"}
+	if bits == 32 {
+		dt.GoType = "float32"
+	}
+	dt.parse = func(lexical string) (value interface{}, err error) {
+		var f float64
+		switch str := strings.TrimSpace(lexical); str {
+		case "INF", "+INF":
+			f = math.Inf(1)
+		case "-INF":
+			f = math.Inf(-1)
+		case "NaN":
+			f = math.NaN()
+		default:
+			if !floatRegexp.MatchString(str) {
+				err = errors.New("no floating point number")
+				return
+			}
+			f, err = strconv.ParseFloat(str, bits)
+			if err != nil {
+				return
+			}
+		}
+		if bits == 32 {
+			value = float32(f)
+		} else {
+			value = f
+		}
+		return
+	}
+	dt.format = func(value interface{}) (lexical string, err error) {
+		var f float64
+		switch t := value.(type) {
+		case float32:
+			f = float64(t)
+		case float64:
+			f = t
+		case Decimal:
+			f = t.Float64()
+		default:
+			i, ok := toBigInt(value)
+			if !ok {
+				err = errors.New("no floating point number")
+				return
+			}
+			f, _ = new(big.Float).SetInt(i).Float64()
+		}
+		lexical = formatFloat(f, bits)
+		return
+	}
+	return
+}
+
+// formatFloat writes the canonical form of a xsd:float or xsd:double (e.g. 1.25E1)
+func formatFloat(f float64, bits int) (lexical string) {
+	switch {
+	case math.IsNaN(f):
+		lexical = "NaN"
+	case math.IsInf(f, 1):
+		lexical = "INF"
+	case math.IsInf(f, -1):
+		lexical = "-INF"
+	case f == 0 && math.Signbit(f):
+		lexical = "-0.0E0"
+	case f == 0:
+		lexical = "0.0E0"
+	default:
+		temp := strings.SplitN(strconv.FormatFloat(f, 'E', -1, bits), "E", 2)
+		mantissa := temp[0]
+		if !strings.Contains(mantissa, ".") {
+			mantissa += ".0"
+		}
+		exp, _ := strconv.Atoi(temp[1])
+		lexical = mantissa + "E" + strconv.Itoa(exp)
+	}
+	return
+}
+
+// Decimal is an arbitrary precision xsd:decimal value (unscaled * 10^-scale); the zero value is 0
+type Decimal struct {
+	unscaled *big.Int
+	scale    int
+}
+
+// NewDecimal returns the decimal unscaled * 10^-scale
+func NewDecimal(unscaled *big.Int, scale int) (d Decimal) {
+	d = Decimal{unscaled: new(big.Int).Set(unscaled), scale: scale}
+	d.normalize()
+	return
+}
+
+// ParseDecimal parses a xsd:decimal lexical form (e.g. -12.50)
+func ParseDecimal(lexical string) (d Decimal, err error) {
+	str := strings.TrimSpace(lexical)
+	if !decimalRegexp.MatchString(str) {
+		err = errors.New("invalid decimal " + lexical)
+		return
+	}
+	temp := strings.SplitN(str, ".", 2)
+	digits := temp[0]
+	if len(temp) > 1 {
+		digits += temp[1]
+		d.scale = len(temp[1])
+	}
+	d.unscaled, _ = new(big.Int).SetString(digits, 10)
+	d.normalize()
+	return
+}
+
+// normalize removes trailing zeros of the fraction
+func (d *Decimal) normalize() {
+	if d.unscaled == nil {
+		d.unscaled = new(big.Int)
+	}
+	ten := big.NewInt(10)
+	for d.scale < 0 {
+		d.unscaled.Mul(d.unscaled, ten)
+		d.scale++
+	}
+	mod := new(big.Int)
+	for d.scale > 0 {
+		quo, _ := new(big.Int).QuoRem(d.unscaled, ten, mod)
+		if mod.Sign() != 0 {
+			break
+		}
+		d.unscaled = quo
+		d.scale--
+	}
+	return
+}
+
+// String returns the canonical form of the decimal (always with a decimal point, e.g. 12.5, 1.0)
+func (d Decimal) String() (str string) {
+	d.normalize()
+	digits := new(big.Int).Abs(d.unscaled).String()
+	for len(digits) <= d.scale {
+		digits = "0" + digits
+	}
+	if d.scale == 0 {
+		str = digits + ".0"
+	} else {
+		str = digits[:len(digits)-d.scale] + "." + digits[len(digits)-d.scale:]
+	}
+	if d.unscaled.Sign() < 0 {
+		str = "-" + str
+	}
+	return
+}
+
+// Rat returns the decimal as rational number
+func (d Decimal) Rat() (r *big.Rat) {
+	d.normalize()
+	r = new(big.Rat).SetInt(d.unscaled)
+	if d.scale > 0 {
+		r.Quo(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(
+			int64(d.scale)), nil)))
+	}
+	return
+}
+
+// Float64 returns the nearest float64 value of the decimal
+func (d Decimal) Float64() (f float64) {
+	f, _ = d.Rat().Float64()
+	return
+}
+
+// Cmp compares two decimals and returns -1, 0 or +1
+func (d Decimal) Cmp(other Decimal) (c int) {
+	c = d.Rat().Cmp(other.Rat())
+	return
+}
+
+// parseDecimal parses a xsd:decimal
+func parseDecimal(lexical string) (value interface{}, err error) {
+	value, err = ParseDecimal(lexical)
+	return
+}
+
+// formatDecimal writes a xsd:decimal
+func formatDecimal(value interface{}) (lexical string, err error) {
+	switch t := value.(type) {
+	case Decimal:
+		lexical = t.String()
+	case float32:
+		lexical, err = formatDecimal(float64(t))
+	case float64:
+		if math.IsInf(t, 0) || math.IsNaN(t) {
+			err = errors.New("no decimal")
+			return
+		}
+		var d Decimal
+		d, err = ParseDecimal(strconv.FormatFloat(t, 'f', -1, 64))
+		lexical = d.String()
+	default:
+		i, ok := toBigInt(value)
+		if !ok {
+			err = errors.New("no decimal")
+			return
+		}
+		lexical = NewDecimal(i, 0).String()
+	}
+	return
+}
+
+// parseHexBinary parses a xsd:hexBinary
+func parseHexBinary(lexical string) (value interface{}, err error) {
+	value, err = hex.DecodeString(strings.TrimSpace(lexical))
+	return
+}
+
+// formatHexBinary writes a xsd:hexBinary (upper case digits)
+func formatHexBinary(value interface{}) (lexical string, err error) {
+	b, ok := value.([]byte)
+	if !ok {
+		err = errors.New("no byte slice")
+		return
+	}
+	lexical = strings.ToUpper(hex.EncodeToString(b))
+	return
+}
+
+// parseBase64Binary parses a xsd:base64Binary
+func parseBase64Binary(lexical string) (value interface{}, err error) {
+	value, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(lexical), ""))
+	return
+}
+
+// formatBase64Binary writes a xsd:base64Binary
+func formatBase64Binary(value interface{}) (lexical string, err error) {
+	b, ok := value.([]byte)
+	if !ok {
+		err = errors.New("no byte slice")
+		return
+	}
+	lexical = base64.StdEncoding.EncodeToString(b)
+	return
+}
+
+// parseAnyURI parses a xsd:anyURI
+func parseAnyURI(lexical string) (value interface{}, err error) {
+	value, err = url.Parse(strings.TrimSpace(lexical))
+	return
+}
+
+// formatAnyURI writes a xsd:anyURI
+func formatAnyURI(value interface{}) (lexical string, err error) {
+	switch t := value.(type) {
+	case *url.URL:
+		if t == nil {
+			err = errors.New("no uri")
+			return
+		}
+		lexical = t.String()
+	case url.URL:
+		lexical = t.String()
+	default:
+		err = errors.New("no uri")
+	}
+	return
+}
+
+// parseDateTime parses a xsd:dateTime
+func parseDateTime(lexical string) (value interface{}, err error) {
+	value, err = ParseDateTime(lexical)
+	return
+}
+
+// parseDateTimeStamp parses a xsd:dateTimeStamp
+func parseDateTimeStamp(lexical string) (value interface{}, err error) {
+	value, err = ParseDateTimeStamp(lexical)
+	return
+}
+
+// dateTimeDatatype returns xsd:dateTime or xsd:dateTimeStamp with DateTime values; time.Time
+// values are converted and written lexical forms are checked with parse (e.g. for the timezone
+// required by xsd:dateTimeStamp)
+func dateTimeDatatype(iri string,
+	parse func(lexical string) (value interface{}, err error)) (dt Datatype) {
+	dt = temporalDatatype(iri, "rdf.DateTime", parse, func(t time.Time) fmt.Stringer {
+		return NewDateTime(t)
+	})
+	format := dt.format
+	dt.format = func(value interface{}) (lexical string, err error) {
+		lexical, err = format(value)
+		if err != nil {
+			return
+		}
+		_, err = parse(lexical)
+		return
+	}
+	return
+}
+
+// parseDate parses a xsd:date
+func parseDate(lexical string) (value interface{}, err error) {
+	value, err = ParseDate(lexical)
+	return
+}
+
+// parseTime parses a xsd:time
+func parseTime(lexical string) (value interface{}, err error) {
+	value, err = ParseTime(lexical)
+	return
+}
+
+// parseGYear parses a xsd:gYear
+func parseGYear(lexical string) (value interface{}, err error) {
+	value, err = ParseGYear(lexical)
+	return
+}
+
+// parseGYearMonth parses a xsd:gYearMonth
+func parseGYearMonth(lexical string) (value interface{}, err error) {
+	value, err = ParseGYearMonth(lexical)
+	return
+}
+
+// parseGMonth parses a xsd:gMonth
+func parseGMonth(lexical string) (value interface{}, err error) {
+	value, err = ParseGMonth(lexical)
+	return
+}
+
+// parseGMonthDay parses a xsd:gMonthDay
+func parseGMonthDay(lexical string) (value interface{}, err error) {
+	value, err = ParseGMonthDay(lexical)
+	return
+}
+
+// parseGDay parses a xsd:gDay
+func parseGDay(lexical string) (value interface{}, err error) {
+	value, err = ParseGDay(lexical)
+	return
+}
+
+// temporalDatatype returns a date or time datatype with values of type goType; time.Time values are
+// converted with fromTime
+func temporalDatatype(iri string, goType string,
+	parse func(lexical string) (value interface{}, err error),
+	fromTime func(t time.Time) fmt.Stringer) (dt Datatype) {
+	dt = Datatype{IRI: iri, GoType: goType, parse: parse}
+	dt.format = func(value interface{}) (lexical string, err error) {
+		if t, ok := value.(time.Time); ok {
+			lexical = fromTime(t).String()
+			return
+		}
+		str, ok := value.(fmt.Stringer)
+		if !ok || fmt.Sprintf("%T", value) != goType {
+			err = errors.New("no " + goType)
+			return
+		}
+		lexical = str.String()
+		return
+	}
+	return
+}
+
+// durationDatatype returns a datatype derived from xsd:duration; valid restricts the value space
+// (nil accepts all durations)
+func durationDatatype(iri string, valid func(d Duration) bool) (dt Datatype) {
+	dt = Datatype{IRI: iri, GoType: "rdf.Duration"}
+	dt.parse = func(lexical string) (value interface{}, err error) {
+		d, err := ParseDuration(lexical)
+		if err == nil && valid != nil && !valid(d) {
+			err = errors.New("value out of range")
+		}
+		value = d
+		return
+	}
+	dt.format = func(value interface{}) (lexical string, err error) {
+		var d Duration
+		switch t := value.(type) {
+		case Duration:
+			d = t
+		case time.Duration:
+			d = NewDuration(t)
+		default:
+			err = errors.New("no duration")
+			return
+		}
+		if valid != nil && !valid(d) {
+			err = errors.New("value out of range")
+			return
+		}
+		lexical = d.String()
+		if iri == XsdYearMonthDuration && d.IsZero() {
+			lexical = "P0M"
+		}
+		return
+	}
+	return
+}
+
+// defaultDatatype returns the datatype that is used for a Go value if no datatype is specified
+func defaultDatatype(value interface{}) (iri string, err error) {
+	switch value.(type) {
+	case string:
+		iri = XsdString
+	case bool:
+		iri = XsdBoolean
+	case int, *big.Int, big.Int:
+		iri = XsdInteger
+	case int64:
+		iri = XsdLong
+	case int32:
+		iri = XsdInt
+	case int16:
+		iri = XsdShort
+	case int8:
+		iri = XsdByte
+	case uint, uint64:
+		iri = XsdUnsignedLong
+	case uint32:
+		iri = XsdUnsignedInt
+	case uint16:
+		iri = XsdUnsignedShort
+	case uint8:
+		iri = XsdUnsignedByte
+	case float32:
+		iri = XsdFloat
+	case float64:
+		iri = XsdDouble
+	case Decimal:
+		iri = XsdDecimal
+	case []byte:
+		iri = XsdBase64Binary
+	case *url.URL, url.URL:
+		iri = XsdAnyURI
+	case LangString:
+		iri = RdfLangString
+	case time.Time, DateTime:
+		iri = XsdDateTime
+	case Date:
+		iri = XsdDate
+	case Time:
+		iri = XsdTime
+	case GYear:
+		iri = XsdYear
+	case GYearMonth:
+		iri = XsdYearMonth
+	case GMonth:
+		iri = XsdMonth
+	case GMonthDay:
+		iri = XsdMonthDay
+	case GDay:
+		iri = XsdDay
+	case Duration, time.Duration:
+		iri = XsdDuration
+	default:
+		err = fmt.Errorf("invalid rdf literal type %v", value)
+	}
+	return
+}
@@ -0,0 +1,436 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package reasoner
+
+import (
+	"errors"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// Inference is the materialization of the entailments of a graph. It is kept up to date when
+// triples are added or removed with Add and Remove; the graph must not be changed otherwise.
+type Inference struct {
+	reasoner        *Reasoner
+	graph           *rdf.Graph              // materialized graph
+	closure         *rdf.Graph              // inferred triples that only follow from the schema
+	hidden          *rdf.Graph              // inferred triples with blank nodes of the schema
+	graphs          []*rdf.Graph            // schema, closure, hidden and materialized graph
+	rules           []*rule                 // rules of the reasoner and compiled rules
+	compiled        map[string]bool         // keys of the compiled rules
+	derivations     map[string][]Derivation // derivations by the key of the inferred triple
+	dependents      map[string][]rdf.Triple // triples derived from a triple by its key
+	inconsistencies map[string]Derivation   // derivations of inconsistencies by their key
+	queue           []rdf.Triple            // triples the rules have not been applied to yet
+	inferred        []rdf.Triple            // triples inferred by the last update
+}
+
+// Derivation is an application of a rule which infers a triple (or an inconsistency) from
+// premises
+type Derivation struct {
+	Rule     string       // name of the rule (e.g. cax-sco)
+	Premises []rdf.Triple // triples matched by the rule
+	key      string
+}
+
+// String returns the rule and the premises of the derivation
+func (d Derivation) String() (str string) {
+	str = d.Rule + ":"
+	for _, p := range d.Premises {
+		str += " " + p.SerializeNTriples()
+	}
+	return
+}
+
+// Infer materializes the entailments of the graph like Materialize and returns an inference
+// which keeps them up to date
+func (r *Reasoner) Infer(graph *rdf.Graph) (inf *Inference) {
+	inf = &Inference{
+		reasoner:        r,
+		graph:           graph,
+		closure:         &rdf.Graph{},
+		hidden:          &rdf.Graph{},
+		rules:           r.rules,
+		compiled:        make(map[string]bool),
+		derivations:     make(map[string][]Derivation),
+		dependents:      make(map[string][]rdf.Triple),
+		inconsistencies: make(map[string]Derivation),
+	}
+	inf.graphs = []*rdf.Graph{r.schema, inf.closure, inf.hidden, graph}
+	inf.queue = append(r.schema.ToTriples(), graph.ToTriples()...)
+	inf.run()
+	return
+}
+
+// Add adds the triples to the graph and returns the triples which are inferred additionally;
+// inferred triples which are added are marked as asserted
+func (inf *Inference) Add(triple ...rdf.Triple) (inferred []rdf.Triple) {
+	for _, trip := range triple {
+		edge, added := inf.graph.AddTriple(trip)
+		if added {
+			inf.queue = append(inf.queue, trip)
+		}
+		edge.Inferred = false
+	}
+	inf.run()
+	inferred = inf.inferred
+	return
+}
+
+// Remove removes the asserted triples from the graph together with all inferred triples which are
+// no longer entailed and returns the removed triples. Asserted triples which are still entailed
+// are kept as inferred triples; triples which are not asserted are ignored.
+func (inf *Inference) Remove(triple ...rdf.Triple) (removed []rdf.Triple) {
+	// overdelete all triples which depend on a removed triple
+	deleted := make(map[string]rdf.Triple)
+	var order []string // keys of the deleted triples in the order they have been found
+	for _, trip := range triple {
+		key := tripleKey(trip)
+		it := inf.graph.Match(trip.Sub, trip.Pred, trip.Obj)
+		if _, ok := deleted[key]; !ok && it.Next() && !it.Edge().Inferred {
+			deleted[key] = trip
+			order = append(order, key)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		for _, dep := range inf.dependents[order[i]] {
+			key := tripleKey(dep)
+			if _, ok := deleted[key]; !ok && inf.isInferred(dep) {
+				deleted[key] = dep
+				order = append(order, key)
+			}
+		}
+	}
+	trips := make([]rdf.Triple, len(order))
+	for i, key := range order {
+		trips[i] = deleted[key]
+	}
+	for _, g := range []*rdf.Graph{inf.graph, inf.closure, inf.hidden} {
+		g.RemoveTriple(trips...)
+	}
+
+	// rederive triples which have a derivation from the remaining triples
+	for changed := true; changed; {
+		changed = false
+		for _, key := range order {
+			trip, ok := deleted[key]
+			if !ok {
+				continue
+			}
+			for _, d := range inf.derivations[key] {
+				if inf.containsAll(d.Premises) {
+					inf.add(trip, d.Premises)
+					delete(deleted, key)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	// drop the derivations and inconsistencies of the removed triples
+	for _, key := range order {
+		trip, ok := deleted[key]
+		if !ok {
+			continue
+		}
+		for _, dep := range inf.dependents[key] {
+			depKey := tripleKey(dep)
+			var kept []Derivation
+			for _, d := range inf.derivations[depKey] {
+				if !hasPremise(d, key) {
+					kept = append(kept, d)
+				}
+			}
+			inf.derivations[depKey] = kept
+		}
+		delete(inf.dependents, key)
+		removed = append(removed, trip)
+	}
+	for key, d := range inf.inconsistencies {
+		if !inf.containsAll(d.Premises) {
+			delete(inf.inconsistencies, key)
+		}
+	}
+	return
+}
+
+// Explain returns the derivations of a triple; asserted triples have derivations if they are
+// entailed by other triples as well
+func (inf *Inference) Explain(trip rdf.Triple) (derivations []Derivation) {
+	derivations = append(derivations, inf.derivations[tripleKey(trip)]...)
+	return
+}
+
+// Inconsistencies returns the derivations of all inconsistencies of the graph
+func (inf *Inference) Inconsistencies() (derivations []Derivation) {
+	for _, d := range inf.inconsistencies {
+		derivations = append(derivations, d)
+	}
+	return
+}
+
+// Err returns an error if the graph is inconsistent
+func (inf *Inference) Err() (err error) {
+	for _, d := range inf.inconsistencies {
+		err = errors.New("Inconsistent graph (" + d.String() + ")")
+		return
+	}
+	return
+}
+
+// run applies the rules to the queued triples until no new triples are inferred
+func (inf *Inference) run() {
+	inf.inferred = nil
+	for len(inf.queue) > 0 {
+		trip := inf.queue[0]
+		inf.queue = inf.queue[1:]
+		for _, r := range inf.rules {
+			if !inf.containsAll(r.extra) {
+				continue
+			}
+			for i := range r.body {
+				if b, ok := r.body[i].unify(trip, nil); ok {
+					done := make([]bool, len(r.body))
+					done[i] = true
+					inf.join(r, done, b)
+				}
+			}
+		}
+		for _, c := range inf.reasoner.compilers {
+			c(inf, trip)
+		}
+	}
+	return
+}
+
+// addRule adds a compiled rule and applies it to all triples
+func (inf *Inference) addRule(r *rule) {
+	key := r.name
+	for _, trip := range r.extra {
+		key += "|" + tripleKey(trip)
+	}
+	if !inf.compiled[key] {
+		inf.compiled[key] = true
+		inf.rules = append(inf.rules[:len(inf.rules):len(inf.rules)], r)
+	}
+	inf.join(r, make([]bool, len(r.body)), binding{})
+	return
+}
+
+// join matches the body patterns of the rule which are not done yet and fires the rule for each
+// complete binding; the pattern with the most bound nodes is matched first
+func (inf *Inference) join(r *rule, done []bool, b binding) {
+	next, bound := -1, -1
+	for i, p := range r.body {
+		if done[i] {
+			continue
+		}
+		n := 0
+		for _, node := range p {
+			if node.resolve(b) != nil {
+				n++
+			}
+		}
+		if n > bound {
+			next, bound = i, n
+		}
+	}
+	if next < 0 {
+		inf.fire(r, b)
+		return
+	}
+	p := r.body[next]
+	done[next] = true
+	for _, trip := range inf.triples(p[0].resolve(b), p[1].resolve(b), p[2].resolve(b)) {
+		if nb, ok := p.unify(trip, b); ok {
+			inf.join(r, done, nb)
+		}
+	}
+	done[next] = false
+	return
+}
+
+// fire infers the head of the rule for a complete binding of the body
+func (inf *Inference) fire(r *rule, b binding) {
+	for _, f := range r.filters {
+		if !f(b) {
+			return
+		}
+	}
+	var premises []rdf.Triple
+	for _, p := range r.body {
+		premises = append(premises, p.instantiate(b))
+	}
+	premises = append(premises, r.extra...)
+	if len(r.head) == 0 {
+		d := newDerivation(r.name, premises)
+		inf.inconsistencies[d.key] = d
+		return
+	}
+	for _, p := range r.head {
+		inf.infer(r.name, p.instantiate(b), premises)
+	}
+	return
+}
+
+// newDerivation returns a derivation; the key contains the rule and the keys of the premises
+func newDerivation(name string, premises []rdf.Triple) (d Derivation) {
+	d = Derivation{Rule: name, Premises: premises, key: name + "|"}
+	for _, p := range premises {
+		d.key += tripleKey(p) + "|"
+	}
+	return
+}
+
+// hasPremise checks if the triple with the key is a premise of the derivation
+func hasPremise(d Derivation, key string) (ok bool) {
+	for _, p := range d.Premises {
+		if tripleKey(p) == key {
+			ok = true
+			return
+		}
+	}
+	return
+}
+
+// infer records the derivation of a triple and adds the triple if it is not known yet.
+// Generalized triples (literal subjects or predicates which are no IRIs) are ignored.
+func (inf *Inference) infer(name string, trip rdf.Triple, premises []rdf.Triple) {
+	if trip.Sub.Type() == rdf.TermLiteral || trip.Pred.Type() != rdf.TermIRI ||
+		inf.reasoner.schema.Match(trip.Sub, trip.Pred, trip.Obj).Next() {
+		return
+	}
+	key := tripleKey(trip)
+	d := newDerivation(name, premises)
+	for _, known := range inf.derivations[key] {
+		if known.key == d.key {
+			return
+		}
+	}
+	inf.derivations[key] = append(inf.derivations[key], d)
+	for _, p := range premises {
+		pKey := tripleKey(p)
+		inf.dependents[pKey] = append(inf.dependents[pKey], trip)
+	}
+	if !inf.contains(trip) {
+		inf.add(trip, premises)
+		inf.queue = append(inf.queue, trip)
+	}
+	return
+}
+
+// add adds an inferred triple to the materialized graph. Triples which only follow from the
+// schema are added to the closure and triples which refer to blank nodes of the schema are added
+// to the hidden graph.
+func (inf *Inference) add(trip rdf.Triple, premises []rdf.Triple) {
+	g := inf.closure
+	for _, p := range premises {
+		if inf.graph.Match(p.Sub, p.Pred, p.Obj).Next() ||
+			inf.hidden.Match(p.Sub, p.Pred, p.Obj).Next() {
+			g = inf.graph
+			break
+		}
+	}
+	if g == inf.graph && (isSchemaBlank(trip.Sub) || isSchemaBlank(trip.Obj)) {
+		g = inf.hidden
+	}
+	edge, _ := g.AddTriple(trip)
+	edge.Inferred = true
+	if g == inf.graph {
+		inf.inferred = append(inf.inferred, trip)
+	}
+	return
+}
+
+// isInferred checks if a triple is an inferred triple of the materialized graph, the closure or the
+// hidden graph
+func (inf *Inference) isInferred(trip rdf.Triple) (ok bool) {
+	if inf.closure.Match(trip.Sub, trip.Pred, trip.Obj).Next() ||
+		inf.hidden.Match(trip.Sub, trip.Pred, trip.Obj).Next() {
+		ok = true
+		return
+	}
+	it := inf.graph.Match(trip.Sub, trip.Pred, trip.Obj)
+	ok = it.Next() && it.Edge().Inferred
+	return
+}
+
+// triples returns all triples of the schema, the closure, the hidden and the materialized graph
+// matching subject, predicate and object; nil terms are wildcards
+func (inf *Inference) triples(s, p, o rdf.Term) (ret []rdf.Triple) {
+	if p != nil && p.Type() != rdf.TermIRI {
+		return
+	}
+	for _, g := range inf.graphs {
+		for it := g.Match(s, p, o); it.Next(); {
+			ret = append(ret, it.Triple())
+		}
+	}
+	return
+}
+
+// contains checks if the schema, the closure, the hidden or the materialized graph contains the
+// triple
+func (inf *Inference) contains(trip rdf.Triple) (ok bool) {
+	for _, g := range inf.graphs {
+		if g.Match(trip.Sub, trip.Pred, trip.Obj).Next() {
+			ok = true
+			return
+		}
+	}
+	return
+}
+
+// containsAll checks if all triples are contained
+func (inf *Inference) containsAll(triples []rdf.Triple) (ok bool) {
+	for _, trip := range triples {
+		if !inf.contains(trip) {
+			return
+		}
+	}
+	ok = true
+	return
+}
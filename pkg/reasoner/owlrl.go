@@ -0,0 +1,351 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package reasoner
+
+import (
+	"strconv"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// owl vocabulary
+var (
+	rdfFirst                 = rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#first")
+	rdfRest                  = rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#rest")
+	rdfNil                   = rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#nil")
+	rdfType                  = rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#type")
+	rdfsSubClassOf           = rdf.NewIRI("http://www.w3.org/2000/01/rdf-schema#subClassOf")
+	owlSameAs                = rdf.NewIRI("http://www.w3.org/2002/07/owl#sameAs")
+	owlIntersectionOf        = rdf.NewIRI("http://www.w3.org/2002/07/owl#intersectionOf")
+	owlUnionOf               = rdf.NewIRI("http://www.w3.org/2002/07/owl#unionOf")
+	owlOneOf                 = rdf.NewIRI("http://www.w3.org/2002/07/owl#oneOf")
+	owlPropertyChainAxiom    = rdf.NewIRI("http://www.w3.org/2002/07/owl#propertyChainAxiom")
+	owlHasKey                = rdf.NewIRI("http://www.w3.org/2002/07/owl#hasKey")
+	owlMembers               = rdf.NewIRI("http://www.w3.org/2002/07/owl#members")
+	owlDistinctMembers       = rdf.NewIRI("http://www.w3.org/2002/07/owl#distinctMembers")
+	owlAllDifferent          = rdf.NewIRI("http://www.w3.org/2002/07/owl#AllDifferent")
+	owlAllDisjointClasses    = rdf.NewIRI("http://www.w3.org/2002/07/owl#AllDisjointClasses")
+	owlAllDisjointProperties = rdf.NewIRI("http://www.w3.org/2002/07/owl#AllDisjointProperties")
+)
+
+// owlRLRules are the rules of the OWL 2 RL/RDF rule set which do not refer to rdf lists
+var owlRLRules = []*rule{
+	// equality
+	newRule("eq-sym", "?x owl:sameAs ?y", "?y owl:sameAs ?x"),
+	newRule("eq-trans", "?x owl:sameAs ?y . ?y owl:sameAs ?z", "?x owl:sameAs ?z"),
+	newRule("eq-rep-s", "?s owl:sameAs ?s2 . ?s ?p ?o", "?s2 ?p ?o"),
+	newRule("eq-rep-p", "?p owl:sameAs ?p2 . ?s ?p ?o", "?s ?p2 ?o"),
+	newRule("eq-rep-o", "?o owl:sameAs ?o2 . ?s ?p ?o", "?s ?p ?o2"),
+	newRule("eq-diff1", "?x owl:sameAs ?y . ?x owl:differentFrom ?y", ""),
+
+	// properties
+	newRule("prp-dom", "?p rdfs:domain ?c . ?x ?p ?y", "?x rdf:type ?c"),
+	newRule("prp-rng", "?p rdfs:range ?c . ?x ?p ?y", "?y rdf:type ?c"),
+	newRule("prp-fp", "?p rdf:type owl:FunctionalProperty . ?x ?p ?y1 . ?x ?p ?y2",
+		"?y1 owl:sameAs ?y2", different("y1", "y2")),
+	newRule("prp-ifp", "?p rdf:type owl:InverseFunctionalProperty . ?x1 ?p ?y . ?x2 ?p ?y",
+		"?x1 owl:sameAs ?x2", different("x1", "x2")),
+	newRule("prp-irp", "?p rdf:type owl:IrreflexiveProperty . ?x ?p ?x", ""),
+	newRule("prp-symp", "?p rdf:type owl:SymmetricProperty . ?x ?p ?y", "?y ?p ?x"),
+	newRule("prp-asyp", "?p rdf:type owl:AsymmetricProperty . ?x ?p ?y . ?y ?p ?x", ""),
+	newRule("prp-trp", "?p rdf:type owl:TransitiveProperty . ?x ?p ?y . ?y ?p ?z", "?x ?p ?z"),
+	newRule("prp-spo1", "?p1 rdfs:subPropertyOf ?p2 . ?x ?p1 ?y", "?x ?p2 ?y"),
+	newRule("prp-eqp1", "?p1 owl:equivalentProperty ?p2 . ?x ?p1 ?y", "?x ?p2 ?y"),
+	newRule("prp-eqp2", "?p1 owl:equivalentProperty ?p2 . ?x ?p2 ?y", "?x ?p1 ?y"),
+	newRule("prp-pdw", "?p1 owl:propertyDisjointWith ?p2 . ?x ?p1 ?y . ?x ?p2 ?y", ""),
+	newRule("prp-inv1", "?p1 owl:inverseOf ?p2 . ?x ?p1 ?y", "?y ?p2 ?x"),
+	newRule("prp-inv2", "?p1 owl:inverseOf ?p2 . ?x ?p2 ?y", "?y ?p1 ?x"),
+	newRule("prp-npa1", "?x owl:sourceIndividual ?i1 . ?x owl:assertionProperty ?p . "+
+		"?x owl:targetIndividual ?i2 . ?i1 ?p ?i2", ""),
+	newRule("prp-npa2", "?x owl:sourceIndividual ?i . ?x owl:assertionProperty ?p . "+
+		"?x owl:targetValue ?lt . ?i ?p ?lt", ""),
+
+	// classes
+	newRule("cls-nothing2", "?x rdf:type owl:Nothing", ""),
+	newRule("cls-com", "?c1 owl:complementOf ?c2 . ?x rdf:type ?c1 . ?x rdf:type ?c2", ""),
+	newRule("cls-svf1", "?x owl:someValuesFrom ?y . ?x owl:onProperty ?p . ?u ?p ?v . "+
+		"?v rdf:type ?y", "?u rdf:type ?x"),
+	newRule("cls-svf2", "?x owl:someValuesFrom owl:Thing . ?x owl:onProperty ?p . ?u ?p ?v",
+		"?u rdf:type ?x"),
+	newRule("cls-avf", "?x owl:allValuesFrom ?y . ?x owl:onProperty ?p . ?u rdf:type ?x . "+
+		"?u ?p ?v", "?v rdf:type ?y"),
+	newRule("cls-hv1", "?x owl:hasValue ?y . ?x owl:onProperty ?p . ?u rdf:type ?x", "?u ?p ?y"),
+	newRule("cls-hv2", "?x owl:hasValue ?y . ?x owl:onProperty ?p . ?u ?p ?y", "?u rdf:type ?x"),
+	newRule("cls-maxc1", "?x owl:maxCardinality ?n . ?x owl:onProperty ?p . ?u rdf:type ?x . "+
+		"?u ?p ?y", "", cardinality("n", 0)),
+	newRule("cls-maxc2", "?x owl:maxCardinality ?n . ?x owl:onProperty ?p . ?u rdf:type ?x . "+
+		"?u ?p ?y1 . ?u ?p ?y2", "?y1 owl:sameAs ?y2", cardinality("n", 1),
+		different("y1", "y2")),
+	newRule("cls-maxqc1", "?x owl:maxQualifiedCardinality ?n . ?x owl:onProperty ?p . "+
+		"?x owl:onClass ?c . ?u rdf:type ?x . ?u ?p ?y . ?y rdf:type ?c", "",
+		cardinality("n", 0)),
+	newRule("cls-maxqc2", "?x owl:maxQualifiedCardinality ?n . ?x owl:onProperty ?p . "+
+		"?x owl:onClass owl:Thing . ?u rdf:type ?x . ?u ?p ?y", "", cardinality("n", 0)),
+	newRule("cls-maxqc3", "?x owl:maxQualifiedCardinality ?n . ?x owl:onProperty ?p . "+
+		"?x owl:onClass ?c . ?u rdf:type ?x . ?u ?p ?y1 . ?y1 rdf:type ?c . ?u ?p ?y2 . "+
+		"?y2 rdf:type ?c", "?y1 owl:sameAs ?y2", cardinality("n", 1), different("y1", "y2")),
+	newRule("cls-maxqc4", "?x owl:maxQualifiedCardinality ?n . ?x owl:onProperty ?p . "+
+		"?x owl:onClass owl:Thing . ?u rdf:type ?x . ?u ?p ?y1 . ?u ?p ?y2",
+		"?y1 owl:sameAs ?y2", cardinality("n", 1), different("y1", "y2")),
+
+	// class axioms
+	newRule("cax-sco", "?c1 rdfs:subClassOf ?c2 . ?x rdf:type ?c1", "?x rdf:type ?c2"),
+	newRule("cax-eqc1", "?c1 owl:equivalentClass ?c2 . ?x rdf:type ?c1", "?x rdf:type ?c2"),
+	newRule("cax-eqc2", "?c1 owl:equivalentClass ?c2 . ?x rdf:type ?c2", "?x rdf:type ?c1"),
+	newRule("cax-dw", "?c1 owl:disjointWith ?c2 . ?x rdf:type ?c1 . ?x rdf:type ?c2", ""),
+
+	// schema
+	newRule("scm-cls", "?c rdf:type owl:Class", "?c rdfs:subClassOf ?c . "+
+		"?c owl:equivalentClass ?c . ?c rdfs:subClassOf owl:Thing . "+
+		"owl:Nothing rdfs:subClassOf ?c"),
+	newRule("scm-sco", "?c1 rdfs:subClassOf ?c2 . ?c2 rdfs:subClassOf ?c3",
+		"?c1 rdfs:subClassOf ?c3"),
+	newRule("scm-eqc1", "?c1 owl:equivalentClass ?c2",
+		"?c1 rdfs:subClassOf ?c2 . ?c2 rdfs:subClassOf ?c1"),
+	newRule("scm-eqc2", "?c1 rdfs:subClassOf ?c2 . ?c2 rdfs:subClassOf ?c1",
+		"?c1 owl:equivalentClass ?c2"),
+	newRule("scm-op", "?p rdf:type owl:ObjectProperty",
+		"?p rdfs:subPropertyOf ?p . ?p owl:equivalentProperty ?p"),
+	newRule("scm-dp", "?p rdf:type owl:DatatypeProperty",
+		"?p rdfs:subPropertyOf ?p . ?p owl:equivalentProperty ?p"),
+	newRule("scm-spo", "?p1 rdfs:subPropertyOf ?p2 . ?p2 rdfs:subPropertyOf ?p3",
+		"?p1 rdfs:subPropertyOf ?p3"),
+	newRule("scm-eqp1", "?p1 owl:equivalentProperty ?p2",
+		"?p1 rdfs:subPropertyOf ?p2 . ?p2 rdfs:subPropertyOf ?p1"),
+	newRule("scm-eqp2", "?p1 rdfs:subPropertyOf ?p2 . ?p2 rdfs:subPropertyOf ?p1",
+		"?p1 owl:equivalentProperty ?p2"),
+	newRule("scm-dom1", "?p rdfs:domain ?c1 . ?c1 rdfs:subClassOf ?c2", "?p rdfs:domain ?c2"),
+	newRule("scm-dom2", "?p2 rdfs:domain ?c . ?p1 rdfs:subPropertyOf ?p2", "?p1 rdfs:domain ?c"),
+	newRule("scm-rng1", "?p rdfs:range ?c1 . ?c1 rdfs:subClassOf ?c2", "?p rdfs:range ?c2"),
+	newRule("scm-rng2", "?p2 rdfs:range ?c . ?p1 rdfs:subPropertyOf ?p2", "?p1 rdfs:range ?c"),
+	newRule("scm-hv", "?c1 owl:hasValue ?i . ?c1 owl:onProperty ?p1 . ?c2 owl:hasValue ?i . "+
+		"?c2 owl:onProperty ?p2 . ?p1 rdfs:subPropertyOf ?p2", "?c1 rdfs:subClassOf ?c2"),
+	newRule("scm-svf1", "?c1 owl:someValuesFrom ?y1 . ?c1 owl:onProperty ?p . "+
+		"?c2 owl:someValuesFrom ?y2 . ?c2 owl:onProperty ?p . ?y1 rdfs:subClassOf ?y2",
+		"?c1 rdfs:subClassOf ?c2"),
+	newRule("scm-svf2", "?c1 owl:someValuesFrom ?y . ?c1 owl:onProperty ?p1 . "+
+		"?c2 owl:someValuesFrom ?y . ?c2 owl:onProperty ?p2 . ?p1 rdfs:subPropertyOf ?p2",
+		"?c1 rdfs:subClassOf ?c2"),
+	newRule("scm-avf1", "?c1 owl:allValuesFrom ?y1 . ?c1 owl:onProperty ?p . "+
+		"?c2 owl:allValuesFrom ?y2 . ?c2 owl:onProperty ?p . ?y1 rdfs:subClassOf ?y2",
+		"?c1 rdfs:subClassOf ?c2"),
+	newRule("scm-avf2", "?c1 owl:allValuesFrom ?y . ?c1 owl:onProperty ?p1 . "+
+		"?c2 owl:allValuesFrom ?y . ?c2 owl:onProperty ?p2 . ?p1 rdfs:subPropertyOf ?p2",
+		"?c2 rdfs:subClassOf ?c1"),
+}
+
+// owlRLCompilers create the rules of the OWL 2 RL/RDF rule set which refer to rdf lists
+var owlRLCompilers = []compiler{compileClassList, compilePropertyList, compileAllAxiom}
+
+// NewOWLRL returns a reasoner for the OWL 2 RL/RDF rule set. The rules eq-ref, prp-ap,
+// cls-thing, cls-nothing1 and the datatype rules (dt-*) are not applied. The schema graphs (e.g.
+// an ontology) are used together with the schema triples of the materialized graph.
+func NewOWLRL(schema ...*rdf.Graph) (r *Reasoner) {
+	r = newReasoner(owlRLRules, owlRLCompilers, schema)
+	return
+}
+
+// compileClassList creates the rules for owl:intersectionOf (cls-int1, cls-int2, scm-int),
+// owl:unionOf (cls-uni, scm-uni) and owl:oneOf (cls-oo)
+func compileClassList(inf *Inference, trip rdf.Triple) {
+	var name string
+	switch {
+	case rdf.EqualTerms(trip.Pred, owlIntersectionOf):
+		name = "int"
+	case rdf.EqualTerms(trip.Pred, owlUnionOf):
+		name = "uni"
+	case rdf.EqualTerms(trip.Pred, owlOneOf):
+		name = "oo"
+	default:
+		return
+	}
+	items, extra, ok := inf.list(trip.Obj)
+	if !ok {
+		return
+	}
+	extra = append([]rdf.Triple{trip}, extra...)
+	c := constant(trip.Sub)
+	typ := constant(rdfType)
+	sco := constant(rdfsSubClassOf)
+	y := variable("y")
+	switch name {
+	case "int":
+		int1 := &rule{name: "cls-int1", head: []pattern{{y, typ, c}}, extra: extra}
+		int2 := &rule{name: "cls-int2", body: []pattern{{y, typ, c}}, extra: extra}
+		scm := &rule{name: "scm-int", extra: extra}
+		for _, item := range items {
+			int1.body = append(int1.body, pattern{y, typ, constant(item)})
+			int2.head = append(int2.head, pattern{y, typ, constant(item)})
+			scm.head = append(scm.head, pattern{c, sco, constant(item)})
+		}
+		inf.addRule(int1)
+		inf.addRule(int2)
+		inf.addRule(scm)
+	case "uni":
+		scm := &rule{name: "scm-uni", extra: extra}
+		for _, item := range items {
+			inf.addRule(&rule{name: "cls-uni", body: []pattern{{y, typ, constant(item)}},
+				head: []pattern{{y, typ, c}}, extra: extra})
+			scm.head = append(scm.head, pattern{constant(item), sco, c})
+		}
+		inf.addRule(scm)
+	case "oo":
+		oo := &rule{name: "cls-oo", extra: extra}
+		for _, item := range items {
+			oo.head = append(oo.head, pattern{constant(item), typ, c})
+		}
+		inf.addRule(oo)
+	}
+	return
+}
+
+// compilePropertyList creates the rules for owl:propertyChainAxiom (prp-spo2) and owl:hasKey
+// (prp-key)
+func compilePropertyList(inf *Inference, trip rdf.Triple) {
+	chain := rdf.EqualTerms(trip.Pred, owlPropertyChainAxiom)
+	if !chain && !rdf.EqualTerms(trip.Pred, owlHasKey) {
+		return
+	}
+	items, extra, ok := inf.list(trip.Obj)
+	if !ok || len(items) == 0 {
+		return
+	}
+	extra = append([]rdf.Triple{trip}, extra...)
+	if chain {
+		r := &rule{name: "prp-spo2", extra: extra}
+		for i, item := range items {
+			r.body = append(r.body, pattern{variable("u" + strconv.Itoa(i)), constant(item),
+				variable("u" + strconv.Itoa(i+1))})
+		}
+		last := variable("u" + strconv.Itoa(len(items)))
+		r.head = []pattern{{variable("u0"), constant(trip.Sub), last}}
+		inf.addRule(r)
+		return
+	}
+	x, y := variable("x"), variable("y")
+	r := &rule{name: "prp-key", body: []pattern{{x, constant(rdfType), constant(trip.Sub)},
+		{y, constant(rdfType), constant(trip.Sub)}}, head: []pattern{{x, constant(owlSameAs), y}},
+		filters: []filter{different("x", "y")}, extra: extra}
+	for i, item := range items {
+		z := variable("z" + strconv.Itoa(i))
+		r.body = append(r.body, pattern{x, constant(item), z}, pattern{y, constant(item), z})
+	}
+	inf.addRule(r)
+	return
+}
+
+// compileAllAxiom creates the rules for owl:AllDifferent (eq-diff2, eq-diff3),
+// owl:AllDisjointClasses (cax-adc) and owl:AllDisjointProperties (prp-adp); it is called for the
+// type and the members of the axiom
+func compileAllAxiom(inf *Inference, trip rdf.Triple) {
+	members := rdf.EqualTerms(trip.Pred, owlMembers) ||
+		rdf.EqualTerms(trip.Pred, owlDistinctMembers)
+	typed := rdf.EqualTerms(trip.Pred, rdfType) && (rdf.EqualTerms(trip.Obj, owlAllDifferent) ||
+		rdf.EqualTerms(trip.Obj, owlAllDisjointClasses) ||
+		rdf.EqualTerms(trip.Obj, owlAllDisjointProperties))
+	if !members && !typed {
+		return
+	}
+	axiom := trip.Sub
+	for _, typ := range inf.triples(axiom, rdfType, nil) {
+		for _, list := range append(inf.triples(axiom, owlMembers, nil),
+			inf.triples(axiom, owlDistinctMembers, nil)...) {
+			items, extra, ok := inf.list(list.Obj)
+			if !ok {
+				continue
+			}
+			extra = append([]rdf.Triple{typ, list}, extra...)
+			for i := range items {
+				for j := i + 1; j < len(items); j++ {
+					a, b := constant(items[i]), constant(items[j])
+					x, y := variable("x"), variable("y")
+					var r *rule
+					switch {
+					case rdf.EqualTerms(typ.Obj, owlAllDifferent):
+						name := "eq-diff2"
+						if rdf.EqualTerms(list.Pred, owlDistinctMembers) {
+							name = "eq-diff3"
+						}
+						r = &rule{name: name, body: []pattern{{a, constant(owlSameAs), b}}}
+					case rdf.EqualTerms(typ.Obj, owlAllDisjointClasses) &&
+						rdf.EqualTerms(list.Pred, owlMembers):
+						r = &rule{name: "cax-adc", body: []pattern{{x, constant(rdfType), a},
+							{x, constant(rdfType), b}}}
+					case rdf.EqualTerms(typ.Obj, owlAllDisjointProperties) &&
+						rdf.EqualTerms(list.Pred, owlMembers):
+						r = &rule{name: "prp-adp", body: []pattern{{x, a, y}, {x, b, y}}}
+					default:
+						continue
+					}
+					r.extra = extra
+					inf.addRule(r)
+				}
+			}
+		}
+	}
+	return
+}
+
+// list returns the items of the rdf list starting at head and the triples of the list; ok is false
+// if the list is not well-formed
+func (inf *Inference) list(head rdf.Term) (items []rdf.Term, triples []rdf.Triple, ok bool) {
+	visited := make(map[string]bool)
+	for !rdf.EqualTerms(head, rdfNil) {
+		if visited[rdf.TermKey(head)] {
+			return
+		}
+		visited[rdf.TermKey(head)] = true
+		first := inf.triples(head, rdfFirst, nil)
+		rest := inf.triples(head, rdfRest, nil)
+		if len(first) != 1 || len(rest) != 1 {
+			return
+		}
+		items = append(items, first[0].Obj)
+		triples = append(triples, first[0], rest[0])
+		head = rest[0].Obj
+	}
+	ok = true
+	return
+}
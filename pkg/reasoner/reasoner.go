@@ -0,0 +1,102 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package reasoner materializes the entailments of rule sets (RDFS and OWL 2 RL) in rdf graphs by
+// forward chaining. Materializations can be updated incrementally and inferred triples can be
+// explained by their derivations.
+package reasoner
+
+import (
+	"strings"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// Reasoner infers triples from the triples of a graph and of optional schema graphs
+type Reasoner struct {
+	rules     []*rule
+	compilers []compiler
+	schema    *rdf.Graph // merged schema graphs (blank nodes are replaced by skolem IRIs)
+}
+
+// compiler creates rules for axioms which refer to rdf lists (e.g. owl:intersectionOf); it is
+// called for each new triple
+type compiler func(inf *Inference, trip rdf.Triple)
+
+// schemaBase is the base of the skolem IRIs of the blank nodes of schema graphs
+const schemaBase = "http://schema.reasoner.invalid"
+
+// newReasoner returns a reasoner with the rules and compilers; the schema graphs are merged and
+// their blank nodes are replaced by skolem IRIs so that they are not confused with the blank nodes
+// of the materialized graph
+func newReasoner(rules []*rule, compilers []compiler, schema []*rdf.Graph) (r *Reasoner) {
+	r = &Reasoner{rules: rules, compilers: compilers, schema: &rdf.Graph{}}
+	for _, g := range schema {
+		r.schema.Merge(g)
+	}
+	r.schema.Skolemize(schemaBase)
+	return
+}
+
+// Materialize adds all triples that are entailed by the graph and the schema graphs to the graph
+// and returns them; the edges of the inferred triples are marked as inferred. An error is returned
+// if the graph is inconsistent (the entailments are added nevertheless).
+func (r *Reasoner) Materialize(graph *rdf.Graph) (inferred []rdf.Triple, err error) {
+	inf := r.Infer(graph)
+	inferred = inf.inferred
+	err = inf.Err()
+	return
+}
+
+// isSchemaBlank checks if the term is a skolemized blank node of a schema graph
+func isSchemaBlank(term rdf.Term) (ok bool) {
+	ok = term.Type() == rdf.TermIRI && strings.HasPrefix(term.String(), schemaBase+"/")
+	return
+}
+
+// tripleKey returns a key which identifies a triple
+func tripleKey(trip rdf.Triple) (key string) {
+	key = rdf.TermKey(trip.Sub) + " " + rdf.TermKey(trip.Pred) + " " + rdf.TermKey(trip.Obj)
+	return
+}
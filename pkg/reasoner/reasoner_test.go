@@ -0,0 +1,208 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package reasoner
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// testSchema is an ontology which uses rules of all groups of OWL 2 RL
+const testSchema = `
+@prefix : <http://example.com/> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+
+:Student rdfs:subClassOf :Person .
+:Person rdfs:subClassOf :Agent .
+:Person owl:equivalentClass :Human .
+:Robot owl:disjointWith :Person .
+:Parent owl:intersectionOf ( :Person :HasChild ) .
+:HasChild owl:onProperty :hasChild ; owl:someValuesFrom :Person .
+:Child owl:unionOf ( :Son :Daughter ) .
+:hasChild rdfs:domain :Person ; rdfs:range :Person ; owl:inverseOf :hasParent .
+:hasSon rdfs:subPropertyOf :hasChild .
+:ancestorOf a owl:TransitiveProperty .
+:hasChild rdfs:subPropertyOf :ancestorOf .
+:knows a owl:SymmetricProperty .
+:hasMother a owl:FunctionalProperty .
+`
+
+// testData are the triples which are added and removed by the tests
+const testData = `
+@prefix : <http://example.com/> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+
+:anna a :Student ; :hasSon :ben ; :knows :carl .
+:ben :hasChild :clara ; :hasMother :anna .
+:clara a :Daughter ; :hasChild :dora .
+:carl a :Robot .
+:dora :hasMother :anna .
+:eve :hasMother :anna , :ann .
+:ann owl:sameAs :anne .
+:dora :knows :eve .
+:eve a :Son .
+`
+
+// decodeTestTriples decodes a turtle string
+func decodeTestTriples(t *testing.T, ttl string) (trips []rdf.Triple) {
+	trips, err := rdf.DecodeTTL(strings.NewReader(ttl))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+// graphKeys returns the sorted keys of the triples of a graph; asserted triples are marked with a
+// leading '!'
+func graphKeys(graph *rdf.Graph) (keys []string) {
+	for _, e := range graph.Edges {
+		key := tripleKey(rdf.Triple{Sub: e.Subject.Term, Pred: e.Pred, Obj: e.Object.Term})
+		if !e.Inferred {
+			key = "!" + key
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
+
+// checkIncremental adds and removes random triples of the pool and compares the materialization
+// after each update with the materialization of the asserted triples from scratch
+func checkIncremental(t *testing.T, newReasoner func() *Reasoner, asserted, pool []rdf.Triple) {
+	r := rand.New(rand.NewSource(1))
+	graph, _ := rdf.NewGraph(asserted)
+	inf := newReasoner().Infer(&graph)
+	state := make(map[string]bool)
+	for _, trip := range asserted {
+		state[tripleKey(trip)] = true
+	}
+	for round := 0; round < 100; round++ {
+		var add, remove []rdf.Triple
+		picked := make(map[string]bool)
+		for n := r.Intn(4) + 1; n > 0; n-- {
+			trip := pool[r.Intn(len(pool))]
+			key := tripleKey(trip)
+			if picked[key] {
+				continue
+			}
+			picked[key] = true
+			if state[key] {
+				remove = append(remove, trip)
+			} else {
+				add = append(add, trip)
+			}
+			state[key] = !state[key]
+		}
+		inf.Remove(remove...)
+		inf.Add(add...)
+
+		var trips []rdf.Triple
+		for _, trip := range pool {
+			if state[tripleKey(trip)] {
+				trips = append(trips, trip)
+			}
+		}
+		fresh, _ := rdf.NewGraph(trips)
+		_, err := newReasoner().Materialize(&fresh)
+		got, want := graphKeys(&graph), graphKeys(&fresh)
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Fatalf("round %d: incremental materialization differs:\n%s", round,
+				diffKeys(got, want))
+		}
+		if (inf.Err() == nil) != (err == nil) {
+			t.Fatalf("round %d: inconsistency %v, want %v", round, inf.Err(), err)
+		}
+	}
+}
+
+// diffKeys lists the keys which are missing (-) or additional (+) in got
+func diffKeys(got, want []string) (diff string) {
+	count := make(map[string]int)
+	for _, key := range want {
+		count[key]++
+	}
+	for _, key := range got {
+		count[key]--
+	}
+	for key, n := range count {
+		if n > 0 {
+			diff += "- " + key + "\n"
+		} else if n < 0 {
+			diff += "+ " + key + "\n"
+		}
+	}
+	return
+}
+
+func TestIncrementalData(t *testing.T) {
+	schemaTrips := decodeTestTriples(t, testSchema)
+	data := decodeTestTriples(t, testData)
+	for name, newReasoner := range map[string]func(schema ...*rdf.Graph) *Reasoner{
+		"rdfs": NewRDFS, "owlrl": NewOWLRL,
+	} {
+		newReasoner := newReasoner
+		t.Run(name, func(t *testing.T) {
+			schema, _ := rdf.NewGraph(schemaTrips)
+			checkIncremental(t, func() *Reasoner { return newReasoner(&schema) }, data[:5], data)
+		})
+	}
+}
+
+func TestIncrementalSchema(t *testing.T) {
+	trips := append(decodeTestTriples(t, testSchema), decodeTestTriples(t, testData)...)
+	for name, newReasoner := range map[string]func(schema ...*rdf.Graph) *Reasoner{
+		"rdfs": NewRDFS, "owlrl": NewOWLRL,
+	} {
+		newReasoner := newReasoner
+		t.Run(name, func(t *testing.T) {
+			checkIncremental(t, func() *Reasoner { return newReasoner() }, trips, trips)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package reasoner
+
+import (
+	"strconv"
+	"strings"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// rule infers the triples of the head for each binding of the variables of the body that matches
+// triples of the graph; a rule without head detects an inconsistency
+type rule struct {
+	name    string
+	body    []pattern
+	head    []pattern
+	filters []filter     // conditions for bindings
+	extra   []rdf.Triple // axioms a compiled rule has been created from
+}
+
+// pattern is a triple pattern
+type pattern [3]node
+
+// node is a term or a variable of a pattern
+type node struct {
+	name string   // variable name (empty for terms)
+	term rdf.Term // term (nil for variables)
+}
+
+// binding maps variable names to terms
+type binding map[string]rdf.Term
+
+// filter is a condition for the binding of a rule
+type filter func(b binding) bool
+
+// prefixes of the rule definitions
+var rulePrefixes = map[string]string{
+	"rdf":  "http://www.w3.org/1999/02/22-rdf-syntax-ns#",
+	"rdfs": "http://www.w3.org/2000/01/rdf-schema#",
+	"owl":  "http://www.w3.org/2002/07/owl#",
+}
+
+// newRule creates a rule from body and head which are triple patterns separated by " . ". The
+// nodes of the patterns are variables (?name) or prefixed names (rdf, rdfs and owl); an empty head
+// detects an inconsistency. newRule panics if a pattern is invalid.
+func newRule(name, body, head string, filters ...filter) (r *rule) {
+	r = &rule{name: name, body: parsePatterns(body), head: parsePatterns(head), filters: filters}
+	return
+}
+
+// parsePatterns parses triple patterns separated by " . "
+func parsePatterns(str string) (patterns []pattern) {
+	if str == "" {
+		return
+	}
+	for _, p := range strings.Split(str, " . ") {
+		fields := strings.Fields(p)
+		if len(fields) != 3 {
+			panic("invalid rule pattern " + p)
+		}
+		var pat pattern
+		for i, f := range fields {
+			if strings.HasPrefix(f, "?") {
+				pat[i] = variable(f[1:])
+				continue
+			}
+			parts := strings.SplitN(f, ":", 2)
+			ns, ok := rulePrefixes[parts[0]]
+			if !ok || len(parts) != 2 {
+				panic("invalid rule node " + f)
+			}
+			pat[i] = constant(rdf.NewIRI(ns + parts[1]))
+		}
+		patterns = append(patterns, pat)
+	}
+	return
+}
+
+// variable returns a variable node
+func variable(name string) (n node) {
+	n = node{name: name}
+	return
+}
+
+// constant returns a term node
+func constant(term rdf.Term) (n node) {
+	n = node{term: term}
+	return
+}
+
+// resolve returns the term of the node under the binding (nil for unbound variables)
+func (n node) resolve(b binding) (term rdf.Term) {
+	if n.name == "" {
+		term = n.term
+	} else {
+		term = b[n.name]
+	}
+	return
+}
+
+// unify extends the binding so that the pattern matches the triple; the binding is copied if it
+// is extended
+func (p pattern) unify(trip rdf.Triple, b binding) (ret binding, ok bool) {
+	ret = b
+	terms := [3]rdf.Term{trip.Sub, trip.Pred, trip.Obj}
+	for i, n := range p {
+		if bound := n.resolve(ret); bound != nil {
+			if !rdf.EqualTerms(bound, terms[i]) {
+				return
+			}
+			continue
+		}
+		if len(ret) == len(b) {
+			ret = make(binding, len(b)+3)
+			for k, v := range b {
+				ret[k] = v
+			}
+		}
+		ret[n.name] = terms[i]
+	}
+	ok = true
+	return
+}
+
+// instantiate returns the triple of the pattern under the binding
+func (p pattern) instantiate(b binding) (trip rdf.Triple) {
+	trip = rdf.Triple{Sub: p[0].resolve(b), Pred: p[1].resolve(b), Obj: p[2].resolve(b)}
+	return
+}
+
+// different returns a filter for bindings with different terms for two variables
+func different(a, b string) (f filter) {
+	f = func(bind binding) bool {
+		return !rdf.EqualTerms(bind[a], bind[b])
+	}
+	return
+}
+
+// cardinality returns a filter for bindings of the variable to a literal with the value num
+func cardinality(name string, num int) (f filter) {
+	f = func(bind binding) bool {
+		term := bind[name]
+		if term.Type() != rdf.TermLiteral {
+			return false
+		}
+		val, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(term.String()), "+"))
+		return err == nil && val == num
+	}
+	return
+}
@@ -0,0 +1,657 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package shacl
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/sparql"
+)
+
+// parameters of the constraint components
+var (
+	shClass            = rdf.NewIRI(shNS + "class")
+	shDatatype         = rdf.NewIRI(shNS + "datatype")
+	shNodeKind         = rdf.NewIRI(shNS + "nodeKind")
+	shMinCount         = rdf.NewIRI(shNS + "minCount")
+	shMaxCount         = rdf.NewIRI(shNS + "maxCount")
+	shMinExclusive     = rdf.NewIRI(shNS + "minExclusive")
+	shMinInclusive     = rdf.NewIRI(shNS + "minInclusive")
+	shMaxExclusive     = rdf.NewIRI(shNS + "maxExclusive")
+	shMaxInclusive     = rdf.NewIRI(shNS + "maxInclusive")
+	shMinLength        = rdf.NewIRI(shNS + "minLength")
+	shMaxLength        = rdf.NewIRI(shNS + "maxLength")
+	shPattern          = rdf.NewIRI(shNS + "pattern")
+	shLanguageIn       = rdf.NewIRI(shNS + "languageIn")
+	shUniqueLang       = rdf.NewIRI(shNS + "uniqueLang")
+	shEquals           = rdf.NewIRI(shNS + "equals")
+	shDisjoint         = rdf.NewIRI(shNS + "disjoint")
+	shLessThan         = rdf.NewIRI(shNS + "lessThan")
+	shLessThanOrEquals = rdf.NewIRI(shNS + "lessThanOrEquals")
+	shNot              = rdf.NewIRI(shNS + "not")
+	shAnd              = rdf.NewIRI(shNS + "and")
+	shOr               = rdf.NewIRI(shNS + "or")
+	shXone             = rdf.NewIRI(shNS + "xone")
+	shNode             = rdf.NewIRI(shNS + "node")
+	shClosed           = rdf.NewIRI(shNS + "closed")
+	shHasValue         = rdf.NewIRI(shNS + "hasValue")
+	shIn               = rdf.NewIRI(shNS + "in")
+)
+
+// constraint is a constraint component applied with the parameter values of a shape
+type constraint struct {
+	component rdf.IRI // e.g. sh:ClassConstraintComponent
+	check     checkFunc
+}
+
+// checkFunc checks the value nodes of a focus node and returns the failures
+type checkFunc func(v *validator, focus rdf.Term, values []rdf.Term) (failures []failure)
+
+// failure is a violation of a constraint
+type failure struct {
+	value   rdf.Term // value node (nil if the failure does not refer to a value node)
+	path    *Path    // path of the result if it is not the path of the shape
+	message string   // message of the result if the shape has no sh:message
+}
+
+// component is a constraint component of SHACL Core; build returns a nil check if the shape lacks
+// further parameters of the component
+type component struct {
+	param rdf.IRI
+	name  string
+	build builder
+}
+
+// builder creates the check of a shape for a value of the parameter of a constraint component
+type builder func(p *parser, s *shape, val rdf.Term) (check checkFunc, err error)
+
+// components are the constraint components in the order of their checks
+var components []component
+
+// init sets the components (the builders of shape-based components refer to them indirectly)
+func init() {
+	components = []component{
+		{shClass, "ClassConstraintComponent", buildClass},
+		{shDatatype, "DatatypeConstraintComponent", buildDatatype},
+		{shNodeKind, "NodeKindConstraintComponent", buildNodeKind},
+		{shMinCount, "MinCountConstraintComponent", buildCount(false)},
+		{shMaxCount, "MaxCountConstraintComponent", buildCount(true)},
+		{shMinExclusive, "MinExclusiveConstraintComponent", buildRange(shMinExclusive, ">")},
+		{shMinInclusive, "MinInclusiveConstraintComponent", buildRange(shMinInclusive, ">=")},
+		{shMaxExclusive, "MaxExclusiveConstraintComponent", buildRange(shMaxExclusive, "<")},
+		{shMaxInclusive, "MaxInclusiveConstraintComponent", buildRange(shMaxInclusive, "<=")},
+		{shMinLength, "MinLengthConstraintComponent", buildLength(false)},
+		{shMaxLength, "MaxLengthConstraintComponent", buildLength(true)},
+		{shPattern, "PatternConstraintComponent", buildPattern},
+		{shLanguageIn, "LanguageInConstraintComponent", buildLanguageIn},
+		{shUniqueLang, "UniqueLangConstraintComponent", buildUniqueLang},
+		{shEquals, "EqualsConstraintComponent", buildPair(shEquals)},
+		{shDisjoint, "DisjointConstraintComponent", buildPair(shDisjoint)},
+		{shLessThan, "LessThanConstraintComponent", buildPair(shLessThan)},
+		{shLessThanOrEquals, "LessThanOrEqualsConstraintComponent", buildPair(shLessThanOrEquals)},
+		{shNot, "NotConstraintComponent", buildNot},
+		{shAnd, "AndConstraintComponent", buildLogical(shAnd)},
+		{shOr, "OrConstraintComponent", buildLogical(shOr)},
+		{shXone, "XoneConstraintComponent", buildLogical(shXone)},
+		{shNode, "NodeConstraintComponent", buildNode},
+		{shQualifiedValueShape, "QualifiedMinCountConstraintComponent", buildQualified(false)},
+		{shQualifiedValueShape, "QualifiedMaxCountConstraintComponent", buildQualified(true)},
+		{shClosed, "ClosedConstraintComponent", buildClosed},
+		{shHasValue, "HasValueConstraintComponent", buildHasValue},
+		{shIn, "InConstraintComponent", buildIn},
+	}
+}
+
+// eachValue returns a check that fails for each value node for which ok returns false
+func eachValue(message string, ok func(v *validator, value rdf.Term) bool) (check checkFunc) {
+	check = func(v *validator, focus rdf.Term, values []rdf.Term) (failures []failure) {
+		for _, value := range values {
+			if !ok(v, value) {
+				failures = append(failures, failure{value: value, message: message})
+			}
+		}
+		return
+	}
+	return
+}
+
+// buildClass checks that the value nodes are instances of the class
+func buildClass(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+	check = eachValue("Value is not an instance of "+ttl(val),
+		func(v *validator, value rdf.Term) bool {
+			return v.hasClass(value, val)
+		})
+	return
+}
+
+// buildDatatype checks that the value nodes are valid literals of the datatype
+func buildDatatype(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+	if val.Type() != rdf.TermIRI {
+		err = p.invalid(s, shDatatype, val)
+		return
+	}
+	check = eachValue("Value is not a valid literal of type "+ttl(val),
+		func(v *validator, value rdf.Term) bool {
+			lit, ok := value.(rdf.Literal)
+			return ok && lit.Datatype() == val.String() && lit.Validate() == nil
+		})
+	return
+}
+
+// buildNodeKind checks that the value nodes are of the node kind
+func buildNodeKind(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+	kinds := map[string][]rdf.TermType{
+		shIRI.String():                {rdf.TermIRI},
+		shBlankNode.String():          {rdf.TermBlankNode},
+		shLiteral.String():            {rdf.TermLiteral},
+		shBlankNodeOrIRI.String():     {rdf.TermBlankNode, rdf.TermIRI},
+		shBlankNodeOrLiteral.String(): {rdf.TermBlankNode, rdf.TermLiteral},
+		shIRIOrLiteral.String():       {rdf.TermIRI, rdf.TermLiteral},
+	}
+	types, ok := kinds[val.String()]
+	if !ok || val.Type() != rdf.TermIRI {
+		err = p.invalid(s, shNodeKind, val)
+		return
+	}
+	check = eachValue("Value is not of node kind "+ttl(val),
+		func(v *validator, value rdf.Term) bool {
+			for _, typ := range types {
+				if value.Type() == typ {
+					return true
+				}
+			}
+			return false
+		})
+	return
+}
+
+// buildCount checks the minimum (max is false) or maximum number of value nodes
+func buildCount(max bool) builder {
+	return func(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+		n, ok := integer(val)
+		if !ok {
+			err = p.invalid(s, shMinCount, val)
+			if max {
+				err = p.invalid(s, shMaxCount, val)
+			}
+			return
+		}
+		check = func(v *validator, focus rdf.Term, values []rdf.Term) (failures []failure) {
+			switch {
+			case !max && len(values) < n:
+				failures = []failure{{message: "Less than " + strconv.Itoa(n) + " values"}}
+			case max && len(values) > n:
+				failures = []failure{{message: "More than " + strconv.Itoa(n) + " values"}}
+			}
+			return
+		}
+		return
+	}
+}
+
+// buildRange checks that the value nodes compare to the value of param with the operator op (">",
+// ">=", "<" or "<="); values that cannot be compared fail
+func buildRange(param rdf.IRI, op string) builder {
+	return func(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+		if val.Type() != rdf.TermLiteral {
+			err = p.invalid(s, param, val)
+			return
+		}
+		check = eachValue("Value is not "+op+" "+ttl(val),
+			func(v *validator, value rdf.Term) bool {
+				c, err := sparql.Compare(value, val)
+				if err != nil {
+					return false
+				}
+				switch op {
+				case ">":
+					return c > 0
+				case ">=":
+					return c >= 0
+				case "<":
+					return c < 0
+				default:
+					return c <= 0
+				}
+			})
+		return
+	}
+}
+
+// buildLength checks the minimum (max is false) or maximum string length of the value nodes;
+// blank nodes always fail
+func buildLength(max bool) builder {
+	return func(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+		n, ok := integer(val)
+		if !ok {
+			err = p.invalid(s, shMinLength, val)
+			if max {
+				err = p.invalid(s, shMaxLength, val)
+			}
+			return
+		}
+		message := "Value has less than " + strconv.Itoa(n) + " characters"
+		if max {
+			message = "Value has more than " + strconv.Itoa(n) + " characters"
+		}
+		check = eachValue(message, func(v *validator, value rdf.Term) bool {
+			if value.Type() == rdf.TermBlankNode {
+				return false
+			}
+			length := utf8.RuneCountInString(value.String())
+			return max && length <= n || !max && length >= n
+		})
+		return
+	}
+}
+
+// buildPattern checks that the value nodes match the regular expression of sh:pattern and
+// sh:flags; blank nodes always fail
+func buildPattern(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+	if val.Type() != rdf.TermLiteral {
+		err = p.invalid(s, shPattern, val)
+		return
+	}
+	pat, prefix := val.String(), ""
+	for _, flags := range p.objects(s.term, shFlags) {
+		for _, f := range flags.String() {
+			switch f {
+			case 'i', 'm', 's':
+				prefix += string(f)
+			case 'x':
+				pat = strings.Join(strings.Fields(pat), "")
+			default:
+				err = p.invalid(s, shFlags, flags)
+				return
+			}
+		}
+	}
+	if prefix != "" {
+		pat = "(?" + prefix + ")" + pat
+	}
+	re, e := regexp.Compile(pat)
+	if e != nil {
+		err = p.invalid(s, shPattern, val)
+		return
+	}
+	check = eachValue("Value does not match pattern "+ttl(val),
+		func(v *validator, value rdf.Term) bool {
+			return value.Type() != rdf.TermBlankNode && re.MatchString(value.String())
+		})
+	return
+}
+
+// buildLanguageIn checks that the value nodes are literals with a language tag that matches one of
+// the language ranges of the list
+func buildLanguageIn(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+	ranges, err := p.list(val)
+	if err != nil {
+		return
+	}
+	check = eachValue("Language of value is not allowed",
+		func(v *validator, value rdf.Term) bool {
+			lit, ok := value.(rdf.Literal)
+			if !ok || lit.Lang() == "" {
+				return false
+			}
+			for _, rng := range ranges {
+				if langMatches(lit.Lang(), rng.String()) {
+					return true
+				}
+			}
+			return false
+		})
+	return
+}
+
+// langMatches checks if a language tag matches a language range (RFC 4647 basic filtering)
+func langMatches(tag string, rng string) (ok bool) {
+	tag, rng = strings.ToLower(tag), strings.ToLower(rng)
+	ok = rng == "*" || tag == rng || strings.HasPrefix(tag, rng+"-")
+	return
+}
+
+// buildUniqueLang checks that no language tag is used by several value nodes
+func buildUniqueLang(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+	if !isTrue(val) {
+		return
+	}
+	check = func(v *validator, focus rdf.Term, values []rdf.Term) (failures []failure) {
+		count := make(map[string]int)
+		var langs []string
+		for _, value := range values {
+			if lit, ok := value.(rdf.Literal); ok && lit.Lang() != "" {
+				lang := strings.ToLower(lit.Lang())
+				if count[lang]++; count[lang] == 2 {
+					langs = append(langs, lang)
+				}
+			}
+		}
+		for _, lang := range langs {
+			failures = append(failures,
+				failure{message: "Language \"" + lang + "\" is used by several values"})
+		}
+		return
+	}
+	return
+}
+
+// buildPair compares the value nodes with the values of a property of the focus node
+// (sh:equals, sh:disjoint, sh:lessThan or sh:lessThanOrEquals)
+func buildPair(param rdf.IRI) builder {
+	return func(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+		if val.Type() != rdf.TermIRI {
+			err = p.invalid(s, param, val)
+			return
+		}
+		check = func(v *validator, focus rdf.Term, values []rdf.Term) (failures []failure) {
+			others := v.objects(focus, val)
+			fail := func(value rdf.Term, message string) {
+				failures = append(failures, failure{value: value, message: message})
+			}
+			switch {
+			case rdf.EqualTerms(param, shEquals):
+				for _, value := range values {
+					if !contains(others, value) {
+						fail(value, "Value is not a value of "+ttl(val))
+					}
+				}
+				for _, other := range others {
+					if !contains(values, other) {
+						fail(other, "Value of "+ttl(val)+" is missing")
+					}
+				}
+			case rdf.EqualTerms(param, shDisjoint):
+				for _, value := range values {
+					if contains(others, value) {
+						fail(value, "Value is also a value of "+ttl(val))
+					}
+				}
+			default:
+				orEqual := rdf.EqualTerms(param, shLessThanOrEquals)
+				for _, value := range values {
+					for _, other := range others {
+						c, err := sparql.Compare(value, other)
+						if err != nil || c > 0 || c == 0 && !orEqual {
+							fail(value, "Value is not less than "+ttl(other))
+						}
+					}
+				}
+			}
+			return
+		}
+		return
+	}
+}
+
+// buildNot checks that the value nodes do not conform to the shape
+func buildNot(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+	not, err := p.shape(val)
+	if err != nil {
+		return
+	}
+	check = eachValue("Value conforms to shape "+ttl(val),
+		func(v *validator, value rdf.Term) bool {
+			return !v.conforms(not, value)
+		})
+	return
+}
+
+// buildLogical checks that the value nodes conform to all (sh:and), at least one (sh:or) or
+// exactly one (sh:xone) of the shapes of the list
+func buildLogical(param rdf.IRI) builder {
+	return func(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+		members, err := p.list(val)
+		if err != nil {
+			return
+		}
+		var shapes []*shape
+		for _, member := range members {
+			var m *shape
+			if m, err = p.shape(member); err != nil {
+				return
+			}
+			shapes = append(shapes, m)
+		}
+		name := strings.TrimPrefix(param.String(), shNS)
+		check = eachValue("Value does not conform to sh:"+name+" of shape "+ttl(s.term),
+			func(v *validator, value rdf.Term) bool {
+				n := 0
+				for _, m := range shapes {
+					if v.conforms(m, value) {
+						n++
+					}
+				}
+				switch {
+				case rdf.EqualTerms(param, shAnd):
+					return n == len(shapes)
+				case rdf.EqualTerms(param, shOr):
+					return n > 0
+				default:
+					return n == 1
+				}
+			})
+		return
+	}
+}
+
+// buildNode checks that the value nodes conform to the shape
+func buildNode(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+	node, err := p.shape(val)
+	if err != nil {
+		return
+	}
+	check = eachValue("Value does not conform to shape "+ttl(val),
+		func(v *validator, value rdf.Term) bool {
+			return v.conforms(node, value)
+		})
+	return
+}
+
+// buildQualified checks the minimum (max is false) or maximum number of value nodes that conform
+// to the shape; if sh:qualifiedValueShapesDisjoint is true, value nodes that conform to the
+// qualified value shapes of sibling property shapes are not counted
+func buildQualified(max bool) builder {
+	return func(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+		param := shQualifiedMinCount
+		if max {
+			param = shQualifiedMaxCount
+		}
+		counts := p.objects(s.term, param)
+		if len(counts) == 0 {
+			return
+		}
+		n, ok := integer(counts[0])
+		if !ok {
+			err = p.invalid(s, param, counts[0])
+			return
+		}
+		qualified, err := p.shape(val)
+		if err != nil {
+			return
+		}
+		var siblings []*shape
+		for _, disjoint := range p.objects(s.term, shQualifiedValueShapesDisjoint) {
+			if !isTrue(disjoint) {
+				continue
+			}
+			for _, parent := range p.graph.Subjects(shProperty, s.term) {
+				for _, prop := range p.objects(parent.Term, shProperty) {
+					for _, sib := range p.objects(prop, shQualifiedValueShape) {
+						if rdf.EqualTerms(prop, s.term) || rdf.EqualTerms(sib, val) {
+							continue
+						}
+						var sibling *shape
+						if sibling, err = p.shape(sib); err != nil {
+							return
+						}
+						siblings = append(siblings, sibling)
+					}
+				}
+			}
+		}
+		check = func(v *validator, focus rdf.Term, values []rdf.Term) (failures []failure) {
+			count := 0
+			for _, value := range values {
+				if !v.conforms(qualified, value) {
+					continue
+				}
+				conforms := true
+				for _, sibling := range siblings {
+					if v.conforms(sibling, value) {
+						conforms = false
+						break
+					}
+				}
+				if conforms {
+					count++
+				}
+			}
+			switch {
+			case !max && count < n:
+				failures = []failure{{message: "Less than " + strconv.Itoa(n) +
+					" values conform to shape " + ttl(val)}}
+			case max && count > n:
+				failures = []failure{{message: "More than " + strconv.Itoa(n) +
+					" values conform to shape " + ttl(val)}}
+			}
+			return
+		}
+		return
+	}
+}
+
+// buildClosed checks that the value nodes have only properties which are paths of the property
+// shapes or listed in sh:ignoredProperties
+func buildClosed(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+	if !isTrue(val) {
+		return
+	}
+	allowed := make(map[string]bool)
+	for _, prop := range s.properties {
+		if pred, ok := prop.path.Predicate(); ok {
+			allowed[pred.String()] = true
+		}
+	}
+	for _, ignored := range p.objects(s.term, shIgnoredProperties) {
+		var preds []rdf.Term
+		if preds, err = p.list(ignored); err != nil {
+			return
+		}
+		for _, pred := range preds {
+			allowed[pred.String()] = true
+		}
+	}
+	check = func(v *validator, focus rdf.Term, values []rdf.Term) (failures []failure) {
+		for _, value := range values {
+			for it := v.data.Match(value, nil, nil); it.Next(); {
+				pred, ok := it.Edge().Pred.(rdf.IRI)
+				if !ok || allowed[pred.String()] {
+					continue
+				}
+				failures = append(failures, failure{value: it.Edge().Object.Term,
+					path: &Path{pred: pred}, message: "Property " + ttl(pred) + " is not allowed"})
+			}
+		}
+		return
+	}
+	return
+}
+
+// buildHasValue checks that the parameter value is one of the value nodes
+func buildHasValue(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+	check = func(v *validator, focus rdf.Term, values []rdf.Term) (failures []failure) {
+		if !contains(values, val) {
+			failures = []failure{{message: "Value " + ttl(val) + " is missing"}}
+		}
+		return
+	}
+	return
+}
+
+// buildIn checks that the value nodes are members of the list
+func buildIn(p *parser, s *shape, val rdf.Term) (check checkFunc, err error) {
+	members, err := p.list(val)
+	if err != nil {
+		return
+	}
+	check = eachValue("Value is not in the list of allowed values",
+		func(v *validator, value rdf.Term) bool {
+			return contains(members, value)
+		})
+	return
+}
+
+// integer returns the value of a non-negative integer literal
+func integer(term rdf.Term) (n int, ok bool) {
+	lit, isLit := term.(rdf.Literal)
+	if !isLit || lit.Lang() != "" {
+		return
+	}
+	n, err := strconv.Atoi(lit.String())
+	ok = err == nil && n >= 0
+	return
+}
+
+// contains checks if the terms contain term
+func contains(terms []rdf.Term, term rdf.Term) (ok bool) {
+	for i := range terms {
+		if rdf.EqualTerms(terms[i], term) {
+			ok = true
+			return
+		}
+	}
+	return
+}
+
+// ttl returns the term in turtle syntax
+func ttl(term rdf.Term) (str string) {
+	str = term.SerializeTTL(nil)
+	return
+}
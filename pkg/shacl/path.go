@@ -0,0 +1,278 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package shacl
+
+import (
+	"errors"
+	"strings"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// Path is a SHACL property path
+type Path struct {
+	op    string  // "" (predicate), "/" (sequence), "|" (alternative), "^", "*", "+" or "?"
+	pred  rdf.IRI // predicate of predicate paths
+	parts []*Path // parts of sequences and alternatives, the path of the other operators
+}
+
+// parsePath parses the property path starting at term in the shapes graph
+func (p *parser) parsePath(term rdf.Term) (path *Path, err error) {
+	if iri, ok := term.(rdf.IRI); ok {
+		path = &Path{pred: iri}
+		return
+	}
+	if term.Type() != rdf.TermBlankNode {
+		err = errors.New("Invalid property path " + ttl(term))
+		return
+	}
+	if len(p.objects(term, rdfFirst)) > 0 {
+		path = &Path{op: "/"}
+		err = p.parsePaths(path, term)
+		if err == nil && len(path.parts) < 2 {
+			err = errors.New("Sequence path " + ttl(term) + " has less than two members")
+		}
+		return
+	}
+	ops := []struct {
+		pred rdf.IRI
+		op   string
+	}{
+		{shAlternativePath, "|"}, {shInversePath, "^"}, {shZeroOrMorePath, "*"},
+		{shOneOrMorePath, "+"}, {shZeroOrOnePath, "?"},
+	}
+	for _, o := range ops {
+		obj := p.objects(term, o.pred)
+		if len(obj) == 0 {
+			continue
+		}
+		if len(obj) > 1 || path != nil {
+			err = errors.New("Ambiguous property path " + ttl(term))
+			return
+		}
+		path = &Path{op: o.op}
+		if o.op == "|" {
+			err = p.parsePaths(path, obj[0])
+		} else {
+			var part *Path
+			part, err = p.parsePath(obj[0])
+			path.parts = []*Path{part}
+		}
+		if err != nil {
+			return
+		}
+	}
+	if path == nil {
+		err = errors.New("Invalid property path " + ttl(term))
+	}
+	return
+}
+
+// parsePaths parses the members of the rdf list as parts of the path
+func (p *parser) parsePaths(path *Path, list rdf.Term) (err error) {
+	items, err := p.list(list)
+	if err != nil {
+		return
+	}
+	for _, item := range items {
+		var part *Path
+		if part, err = p.parsePath(item); err != nil {
+			return
+		}
+		path.parts = append(path.parts, part)
+	}
+	return
+}
+
+// Predicate returns the predicate of a predicate path (ok is false for other paths)
+func (path *Path) Predicate() (pred rdf.IRI, ok bool) {
+	pred, ok = path.pred, path.op == ""
+	return
+}
+
+// String returns the path in SPARQL syntax
+func (path *Path) String() (str string) {
+	switch path.op {
+	case "":
+		str = "<" + path.pred.String() + ">"
+	case "/", "|":
+		var parts []string
+		for _, part := range path.parts {
+			parts = append(parts, part.String())
+		}
+		str = "(" + strings.Join(parts, path.op) + ")"
+	case "^":
+		str = "^" + path.parts[0].String()
+	default:
+		str = path.parts[0].String() + path.op
+	}
+	return
+}
+
+// values returns the distinct terms that are reachable from focus via the path in the graph
+func (path *Path) values(graph *rdf.Graph, focus rdf.Term) (values []rdf.Term) {
+	values = distinct(path.targets(graph, focus, false))
+	return
+}
+
+// targets returns the terms reachable from start via the path (inverse: in opposite direction);
+// sequences and alternatives may return terms several times
+func (path *Path) targets(graph *rdf.Graph, start rdf.Term, inverse bool) (targets []rdf.Term) {
+	switch path.op {
+	case "":
+		if inverse {
+			for it := graph.Match(nil, path.pred, start); it.Next(); {
+				targets = append(targets, it.Edge().Subject.Term)
+			}
+		} else {
+			for it := graph.Match(start, path.pred, nil); it.Next(); {
+				targets = append(targets, it.Edge().Object.Term)
+			}
+		}
+	case "/":
+		targets = []rdf.Term{start}
+		for i := range path.parts {
+			part := path.parts[i]
+			if inverse {
+				part = path.parts[len(path.parts)-1-i]
+			}
+			var next []rdf.Term
+			for _, term := range targets {
+				next = append(next, part.targets(graph, term, inverse)...)
+			}
+			targets = distinct(next)
+		}
+	case "|":
+		for _, part := range path.parts {
+			targets = append(targets, part.targets(graph, start, inverse)...)
+		}
+	case "^":
+		targets = path.parts[0].targets(graph, start, !inverse)
+	default:
+		targets = path.closure(graph, start, inverse)
+	}
+	return
+}
+
+// closure returns the distinct terms reachable from start by zero or one ('?'), zero or more ('*')
+// or one or more ('+') repetitions of the path
+func (path *Path) closure(graph *rdf.Graph, start rdf.Term, inverse bool) (targets []rdf.Term) {
+	seen := make(map[string]bool)
+	add := func(term rdf.Term) (added bool) {
+		if key := rdf.TermKey(term); !seen[key] {
+			seen[key] = true
+			targets = append(targets, term)
+			added = true
+		}
+		return
+	}
+	if path.op != "+" {
+		add(start)
+	}
+	queue := []rdf.Term{start}
+	for len(queue) > 0 {
+		term := queue[0]
+		queue = queue[1:]
+		for _, next := range path.parts[0].targets(graph, term, inverse) {
+			if add(next) && path.op != "?" {
+				queue = append(queue, next)
+			}
+		}
+		if path.op == "?" {
+			break
+		}
+	}
+	return
+}
+
+// encode adds the triples of the path to the graph and returns the term of the path; blank nodes
+// are created by blank
+func (path *Path) encode(graph *rdf.Graph, blank func() rdf.BlankNode) (term rdf.Term) {
+	switch path.op {
+	case "":
+		term = path.pred
+		return
+	case "/":
+		term = encodeList(graph, path.parts, blank)
+		return
+	}
+	node := blank()
+	term = node
+	switch path.op {
+	case "|":
+		obj := encodeList(graph, path.parts, blank)
+		graph.AddTriple(rdf.Triple{Sub: node, Pred: shAlternativePath, Obj: obj})
+	default:
+		pred := map[string]rdf.IRI{"^": shInversePath, "*": shZeroOrMorePath,
+			"+": shOneOrMorePath, "?": shZeroOrOnePath}[path.op]
+		obj := path.parts[0].encode(graph, blank)
+		graph.AddTriple(rdf.Triple{Sub: node, Pred: pred, Obj: obj})
+	}
+	return
+}
+
+// encodeList adds a rdf list of the paths to the graph and returns its head
+func encodeList(graph *rdf.Graph, paths []*Path, blank func() rdf.BlankNode) (head rdf.Term) {
+	head = rdfNil
+	for i := len(paths) - 1; i >= 0; i-- {
+		node := blank()
+		graph.AddTriple(rdf.Triple{Sub: node, Pred: rdfFirst, Obj: paths[i].encode(graph, blank)})
+		graph.AddTriple(rdf.Triple{Sub: node, Pred: rdfRest, Obj: head})
+		head = node
+	}
+	return
+}
+
+// distinct removes duplicate terms
+func distinct(terms []rdf.Term) (ret []rdf.Term) {
+	seen := make(map[string]bool)
+	for _, term := range terms {
+		if key := rdf.TermKey(term); !seen[key] {
+			seen[key] = true
+			ret = append(ret, term)
+		}
+	}
+	return
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package shacl
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// Report is the result of a validation (sh:ValidationReport)
+type Report struct {
+	Conforms bool     // the data graph conforms to the shapes (there are no results)
+	Results  []Result // validation results
+}
+
+// Result is a violation of a constraint (sh:ValidationResult)
+type Result struct {
+	Focus     rdf.Term      // focus node (sh:focusNode)
+	Path      *Path         // path of the property shape (sh:resultPath; nil for node shapes)
+	Value     rdf.Term      // value node that caused the result (sh:value; may be nil)
+	Shape     rdf.Term      // shape that contains the constraint (sh:sourceShape)
+	Component rdf.IRI       // constraint component (sh:sourceConstraintComponent)
+	Severity  rdf.IRI       // sh:Violation, sh:Warning or sh:Info (sh:resultSeverity)
+	Messages  []rdf.Literal // sh:message of the shape or a generated message (sh:resultMessage)
+}
+
+// result returns the validation result of a failure of a constraint of the shape
+func (s *shape) result(focus rdf.Term, component rdf.IRI, f failure) (res Result) {
+	res = Result{
+		Focus:     focus,
+		Path:      s.path,
+		Value:     f.value,
+		Shape:     s.term,
+		Component: component,
+		Severity:  s.severity,
+		Messages:  s.messages,
+	}
+	if f.path != nil {
+		res.Path = f.path
+	}
+	if len(res.Messages) == 0 {
+		res.Messages = []rdf.Literal{rdf.NewTypedLiteral(f.message, "")}
+	}
+	return
+}
+
+// Err returns an error if the report contains results of severity sh:Violation; warnings and
+// infos are ignored
+func (report *Report) Err() (err error) {
+	var violations []string
+	for _, res := range report.Results {
+		if rdf.EqualTerms(res.Severity, shViolation) {
+			violations = append(violations, res.String())
+		}
+	}
+	if len(violations) == 0 {
+		return
+	}
+	err = errors.New("Validation failed: " + strings.Join(violations, "; "))
+	return
+}
+
+// String returns the focus node, path, value and message of the result
+func (res Result) String() (str string) {
+	str = ttl(res.Focus)
+	if res.Path != nil {
+		str += " " + res.Path.String()
+	}
+	if res.Value != nil {
+		str += " " + ttl(res.Value)
+	}
+	if len(res.Messages) > 0 {
+		str += ": " + res.Messages[0].String()
+	}
+	return
+}
+
+// Graph returns the report as sh:ValidationReport graph; complex result paths are copied to the
+// report with new blank nodes
+func (report *Report) Graph() (graph rdf.Graph) {
+	graph.Nodes = make(map[string]*rdf.Node)
+	next := 0
+	blank := func() (node rdf.BlankNode) {
+		node = rdf.NewBlankNode("b" + strconv.Itoa(next))
+		next++
+		return
+	}
+	add := func(s rdf.Term, p rdf.IRI, o rdf.Term) {
+		graph.AddTriple(rdf.Triple{Sub: s, Pred: p, Obj: o})
+	}
+	rep := blank()
+	add(rep, rdfType, shValidationReport)
+	add(rep, shConforms, rdf.NewTypedLiteral(strconv.FormatBool(report.Conforms), rdf.XsdBoolean))
+	for _, res := range report.Results {
+		node := blank()
+		add(rep, shResult, node)
+		add(node, rdfType, shValidationResult)
+		add(node, shFocusNode, res.Focus)
+		if res.Path != nil {
+			add(node, shResultPath, res.Path.encode(&graph, blank))
+		}
+		if res.Value != nil {
+			add(node, shValue, res.Value)
+		}
+		add(node, shSourceShape, res.Shape)
+		add(node, shSourceConstraintComponent, res.Component)
+		add(node, shResultSeverity, res.Severity)
+		for _, msg := range res.Messages {
+			add(node, shResultMessage, msg)
+		}
+	}
+	return
+}
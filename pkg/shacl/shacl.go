@@ -0,0 +1,140 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package shacl validates rdf graphs against SHACL shapes (SHACL Core). The results of a
+// validation are returned as Go structs and can be converted to a sh:ValidationReport graph.
+package shacl
+
+import (
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// shNS is the namespace of the SHACL vocabulary
+const shNS = "http://www.w3.org/ns/shacl#"
+
+// SHACL vocabulary
+var (
+	rdfType        = rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#type")
+	rdfFirst       = rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#first")
+	rdfRest        = rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#rest")
+	rdfNil         = rdf.NewIRI("http://www.w3.org/1999/02/22-rdf-syntax-ns#nil")
+	rdfsClass      = rdf.NewIRI("http://www.w3.org/2000/01/rdf-schema#Class")
+	rdfsSubClassOf = rdf.NewIRI("http://www.w3.org/2000/01/rdf-schema#subClassOf")
+
+	shNodeShape                    = rdf.NewIRI(shNS + "NodeShape")
+	shPropertyShape                = rdf.NewIRI(shNS + "PropertyShape")
+	shTargetClass                  = rdf.NewIRI(shNS + "targetClass")
+	shTargetNode                   = rdf.NewIRI(shNS + "targetNode")
+	shTargetSubjectsOf             = rdf.NewIRI(shNS + "targetSubjectsOf")
+	shTargetObjectsOf              = rdf.NewIRI(shNS + "targetObjectsOf")
+	shPath                         = rdf.NewIRI(shNS + "path")
+	shInversePath                  = rdf.NewIRI(shNS + "inversePath")
+	shAlternativePath              = rdf.NewIRI(shNS + "alternativePath")
+	shZeroOrMorePath               = rdf.NewIRI(shNS + "zeroOrMorePath")
+	shOneOrMorePath                = rdf.NewIRI(shNS + "oneOrMorePath")
+	shZeroOrOnePath                = rdf.NewIRI(shNS + "zeroOrOnePath")
+	shDeactivated                  = rdf.NewIRI(shNS + "deactivated")
+	shSeverity                     = rdf.NewIRI(shNS + "severity")
+	shMessage                      = rdf.NewIRI(shNS + "message")
+	shViolation                    = rdf.NewIRI(shNS + "Violation")
+	shIRI                          = rdf.NewIRI(shNS + "IRI")
+	shBlankNode                    = rdf.NewIRI(shNS + "BlankNode")
+	shLiteral                      = rdf.NewIRI(shNS + "Literal")
+	shBlankNodeOrIRI               = rdf.NewIRI(shNS + "BlankNodeOrIRI")
+	shBlankNodeOrLiteral           = rdf.NewIRI(shNS + "BlankNodeOrLiteral")
+	shIRIOrLiteral                 = rdf.NewIRI(shNS + "IRIOrLiteral")
+	shFlags                        = rdf.NewIRI(shNS + "flags")
+	shIgnoredProperties            = rdf.NewIRI(shNS + "ignoredProperties")
+	shQualifiedMinCount            = rdf.NewIRI(shNS + "qualifiedMinCount")
+	shQualifiedMaxCount            = rdf.NewIRI(shNS + "qualifiedMaxCount")
+	shQualifiedValueShapesDisjoint = rdf.NewIRI(shNS + "qualifiedValueShapesDisjoint")
+	shProperty                     = rdf.NewIRI(shNS + "property")
+	shQualifiedValueShape          = rdf.NewIRI(shNS + "qualifiedValueShape")
+
+	shValidationReport          = rdf.NewIRI(shNS + "ValidationReport")
+	shValidationResult          = rdf.NewIRI(shNS + "ValidationResult")
+	shConforms                  = rdf.NewIRI(shNS + "conforms")
+	shResult                    = rdf.NewIRI(shNS + "result")
+	shFocusNode                 = rdf.NewIRI(shNS + "focusNode")
+	shResultPath                = rdf.NewIRI(shNS + "resultPath")
+	shValue                     = rdf.NewIRI(shNS + "value")
+	shSourceShape               = rdf.NewIRI(shNS + "sourceShape")
+	shSourceConstraintComponent = rdf.NewIRI(shNS + "sourceConstraintComponent")
+	shResultSeverity            = rdf.NewIRI(shNS + "resultSeverity")
+	shResultMessage             = rdf.NewIRI(shNS + "resultMessage")
+)
+
+// Shapes are the shapes of a shapes graph
+type Shapes struct {
+	shapes []*shape          // shapes in the order of their first occurrence in the shapes graph
+	index  map[string]*shape // shapes by TermKey
+}
+
+// Validate parses the shapes graph and validates the data graph against it
+func Validate(shapesGraph *rdf.Graph, data *rdf.Graph) (report *Report, err error) {
+	shapes, err := Parse(shapesGraph)
+	if err != nil {
+		return
+	}
+	report = shapes.Validate(data)
+	return
+}
+
+// Validate validates the data graph against the shapes; the focus nodes of the shapes are
+// determined by their targets, instances of classes are found by rdf:type and rdfs:subClassOf
+// triples of the data graph
+func (shapes *Shapes) Validate(data *rdf.Graph) (report *Report) {
+	v := newValidator(data)
+	report = &Report{}
+	for _, s := range shapes.shapes {
+		if s.deactivated {
+			continue
+		}
+		for _, focus := range v.targets(s) {
+			report.Results = append(report.Results, v.validate(s, focus)...)
+		}
+	}
+	report.Conforms = len(report.Results) == 0
+	return
+}
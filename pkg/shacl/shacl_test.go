@@ -0,0 +1,151 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package shacl
+
+import (
+	"bytes"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/internal/testsuite"
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// base iri of the fixtures in testdata and vocabulary of the W3C SHACL tests
+const (
+	shaclTests = "http://example.org/owl2go/shacl/"
+	shtNS      = "http://www.w3.org/ns/shacl-test#"
+)
+
+// object returns the only object of the subject and predicate
+func object(t *testing.T, graph *rdf.Graph, subj rdf.Term, pred string) (obj rdf.Term) {
+	objs := graph.Objects(subj, rdf.NewIRI(pred))
+	if len(objs) != 1 {
+		t.Fatalf("%d objects of %v %s", len(objs), subj, pred)
+	}
+	obj = objs[0].Term
+	return
+}
+
+// reportTriples returns the triples of the validation report starting at node; the nodes the
+// results refer to are not followed and result messages are left out, since they depend on the
+// implementation
+func reportTriples(graph *rdf.Graph, node rdf.Term) (trips []rdf.Triple) {
+	visited := map[string]bool{rdf.TermKey(node): true}
+	for queue := []rdf.Term{node}; len(queue) > 0; queue = queue[1:] {
+		for it := graph.Match(queue[0], nil, nil); it.Next(); {
+			trip := it.Triple()
+			switch {
+			case rdf.EqualTerms(trip.Pred, shResultMessage):
+				continue
+			case rdf.EqualTerms(trip.Pred, shFocusNode), rdf.EqualTerms(trip.Pred, shValue),
+				rdf.EqualTerms(trip.Pred, shSourceShape):
+			case trip.Obj.Type() == rdf.TermBlankNode && !visited[rdf.TermKey(trip.Obj)]:
+				visited[rdf.TermKey(trip.Obj)] = true
+				queue = append(queue, trip.Obj)
+			}
+			trips = append(trips, trip)
+		}
+	}
+	return
+}
+
+// runValidateTest validates the data graph of a test against its shapes graph and compares the
+// validation report with the expected result
+func runValidateTest(t *testing.T, graph *rdf.Graph, base string, entry testsuite.Entry) {
+	action := entry.Action
+	for _, pred := range []string{shtNS + "dataGraph", shtNS + "shapesGraph"} {
+		if g := object(t, graph, action, pred); g.String() != base {
+			t.Fatalf("graph %v is not the test file", g)
+		}
+	}
+	report, err := Validate(graph, graph)
+	result := entry.Result
+	if result.String() == shtNS+"Failure" {
+		if err == nil {
+			t.Fatal("expected failure")
+		}
+		return
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reportGraph := report.Graph()
+	it := reportGraph.Match(nil, rdfType, shValidationReport)
+	if !it.Next() {
+		t.Fatal("report graph without sh:ValidationReport")
+	}
+	got, _ := rdf.NewGraph(reportTriples(&reportGraph, it.Edge().Subject.Term))
+	want, _ := rdf.NewGraph(reportTriples(graph, result))
+	if !rdf.Isomorphic(&got, &want) {
+		var buf bytes.Buffer
+		rdf.EncodeNTriples(got.ToTriples(), &buf)
+		t.Errorf("reports differ, got:\n%s", buf.String())
+	}
+}
+
+func TestValidateFixtures(t *testing.T) {
+	manifest := testsuite.DecodeTTL(t, filepath.Join("testdata", "manifest.ttl"),
+		shaclTests+"manifest.ttl")
+	var files []string
+	for _, include := range manifest.Objects(nil, rdf.NewIRI(testsuite.MF+"include")) {
+		files = append(files, testsuite.File(include.Term, shaclTests))
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		base := shaclTests + file
+		graph, entries := testsuite.ReadManifest(t, filepath.Join("testdata", file), base)
+		for _, entry := range entries {
+			entry := entry
+			t.Run(strings.TrimSuffix(file, ".ttl"), func(t *testing.T) {
+				if entry.Type != "Validate" {
+					t.Fatalf("unknown test type %s", entry.Type)
+				}
+				runValidateTest(t, graph, base, entry)
+			})
+		}
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package shacl
+
+import (
+	"errors"
+	"strings"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// shape is a node shape or a property shape
+type shape struct {
+	term        rdf.Term
+	path        *Path // path of property shapes (nil for node shapes)
+	targets     []target
+	deactivated bool
+	severity    rdf.IRI
+	messages    []rdf.Literal
+	properties  []*shape // property shapes of sh:property
+	constraints []*constraint
+}
+
+// target declares focus nodes of a shape
+type target struct {
+	kind rdf.IRI  // sh:targetClass, sh:targetNode, sh:targetSubjectsOf or sh:targetObjectsOf
+	term rdf.Term // class, node or predicate
+}
+
+// parser parses the shapes of a shapes graph
+type parser struct {
+	graph  *rdf.Graph
+	shapes *Shapes
+}
+
+// Parse parses the shapes of a shapes graph; nodes are shapes if they are instances of
+// sh:NodeShape or sh:PropertyShape, have targets or a path or are referenced by other shapes
+func Parse(graph *rdf.Graph) (shapes *Shapes, err error) {
+	p := &parser{graph: graph, shapes: &Shapes{index: make(map[string]*shape)}}
+	for _, e := range graph.Edges {
+		switch {
+		case rdf.EqualTerms(e.Pred, rdfType):
+			if !rdf.EqualTerms(e.Object.Term, shNodeShape) &&
+				!rdf.EqualTerms(e.Object.Term, shPropertyShape) {
+				continue
+			}
+		case rdf.EqualTerms(e.Pred, shPath), isTarget(e.Pred):
+		default:
+			continue
+		}
+		if _, err = p.shape(e.Subject.Term); err != nil {
+			return
+		}
+	}
+	shapes = p.shapes
+	return
+}
+
+// isTarget checks if pred declares a target
+func isTarget(pred rdf.Term) (ok bool) {
+	for _, kind := range []rdf.IRI{shTargetClass, shTargetNode, shTargetSubjectsOf,
+		shTargetObjectsOf} {
+		if rdf.EqualTerms(pred, kind) {
+			ok = true
+		}
+	}
+	return
+}
+
+// shape returns the shape of the term; the shape is parsed if it is used the first time
+func (p *parser) shape(term rdf.Term) (s *shape, err error) {
+	key := rdf.TermKey(term)
+	if s = p.shapes.index[key]; s != nil {
+		return
+	}
+	s = &shape{term: term, severity: shViolation}
+	p.shapes.index[key] = s
+	p.shapes.shapes = append(p.shapes.shapes, s)
+	err = p.parseShape(s)
+	return
+}
+
+// parseShape parses the path, targets and constraints of the shape
+func (p *parser) parseShape(s *shape) (err error) {
+	paths := p.objects(s.term, shPath)
+	if len(paths) > 1 {
+		err = errors.New("Shape " + ttl(s.term) + " has several paths")
+		return
+	}
+	if len(paths) == 1 {
+		if s.path, err = p.parsePath(paths[0]); err != nil {
+			return
+		}
+	}
+	for _, kind := range []rdf.IRI{shTargetClass, shTargetNode, shTargetSubjectsOf,
+		shTargetObjectsOf} {
+		for _, obj := range p.objects(s.term, kind) {
+			s.targets = append(s.targets, target{kind: kind, term: obj})
+		}
+	}
+	// implicit class target
+	if p.graph.Match(s.term, rdfType, rdfsClass).Next() {
+		s.targets = append(s.targets, target{kind: shTargetClass, term: s.term})
+	}
+	for _, obj := range p.objects(s.term, shDeactivated) {
+		s.deactivated = s.deactivated || isTrue(obj)
+	}
+	for _, obj := range p.objects(s.term, shSeverity) {
+		if iri, ok := obj.(rdf.IRI); ok {
+			s.severity = iri
+		}
+	}
+	for _, obj := range p.objects(s.term, shMessage) {
+		if lit, ok := obj.(rdf.Literal); ok {
+			s.messages = append(s.messages, lit)
+		}
+	}
+	for _, obj := range p.objects(s.term, shProperty) {
+		var prop *shape
+		if prop, err = p.shape(obj); err != nil {
+			return
+		}
+		if prop.path == nil {
+			err = errors.New("Property shape " + ttl(obj) + " of shape " + ttl(s.term) +
+				" has no path")
+			return
+		}
+		s.properties = append(s.properties, prop)
+	}
+	for _, comp := range components {
+		for _, val := range p.objects(s.term, comp.param) {
+			var check checkFunc
+			if check, err = comp.build(p, s, val); err != nil {
+				return
+			}
+			if check != nil {
+				s.constraints = append(s.constraints,
+					&constraint{component: rdf.NewIRI(shNS + comp.name), check: check})
+			}
+		}
+	}
+	return
+}
+
+// objects returns the objects of the triples with subject and predicate in the shapes graph
+func (p *parser) objects(subj rdf.Term, pred rdf.IRI) (objects []rdf.Term) {
+	for it := p.graph.Match(subj, pred, nil); it.Next(); {
+		objects = append(objects, it.Edge().Object.Term)
+	}
+	return
+}
+
+// list returns the members of the rdf list starting at head
+func (p *parser) list(head rdf.Term) (items []rdf.Term, err error) {
+	visited := make(map[string]bool)
+	for !rdf.EqualTerms(head, rdfNil) {
+		first, rest := p.objects(head, rdfFirst), p.objects(head, rdfRest)
+		if visited[rdf.TermKey(head)] || len(first) != 1 || len(rest) != 1 {
+			err = errors.New("Invalid rdf list " + ttl(head))
+			return
+		}
+		visited[rdf.TermKey(head)] = true
+		items = append(items, first[0])
+		head = rest[0]
+	}
+	return
+}
+
+// invalid returns an error for an invalid value of a parameter of the shape
+func (p *parser) invalid(s *shape, param rdf.IRI, val rdf.Term) (err error) {
+	err = errors.New("Invalid value " + ttl(val) + " of sh:" +
+		strings.TrimPrefix(param.String(), shNS) + " in shape " + ttl(s.term))
+	return
+}
+
+// isTrue checks if the term is the xsd:boolean true
+func isTrue(term rdf.Term) (ok bool) {
+	lit, isLit := term.(rdf.Literal)
+	ok = isLit && lit.Datatype() == rdf.XsdBoolean && lit.Value() == true
+	return
+}
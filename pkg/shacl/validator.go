@@ -0,0 +1,167 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package shacl
+
+import (
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// validator validates the nodes of a data graph
+type validator struct {
+	data    *rdf.Graph
+	classes map[string]map[string]bool // super classes (including itself) by TermKey of a class
+	active  map[string]bool            // shapes and focus nodes in validation
+}
+
+// newValidator returns a validator for the data graph
+func newValidator(data *rdf.Graph) (v *validator) {
+	v = &validator{
+		data:    data,
+		classes: make(map[string]map[string]bool),
+		active:  make(map[string]bool),
+	}
+	return
+}
+
+// targets returns the focus nodes of the targets of the shape
+func (v *validator) targets(s *shape) (focus []rdf.Term) {
+	for _, t := range s.targets {
+		switch {
+		case rdf.EqualTerms(t.kind, shTargetNode):
+			focus = append(focus, t.term)
+		case rdf.EqualTerms(t.kind, shTargetClass):
+			for it := v.data.Match(nil, rdfType, nil); it.Next(); {
+				if v.superClasses(it.Edge().Object.Term)[rdf.TermKey(t.term)] {
+					focus = append(focus, it.Edge().Subject.Term)
+				}
+			}
+		case rdf.EqualTerms(t.kind, shTargetSubjectsOf):
+			for it := v.data.Match(nil, t.term, nil); it.Next(); {
+				focus = append(focus, it.Edge().Subject.Term)
+			}
+		case rdf.EqualTerms(t.kind, shTargetObjectsOf):
+			for it := v.data.Match(nil, t.term, nil); it.Next(); {
+				focus = append(focus, it.Edge().Object.Term)
+			}
+		}
+	}
+	focus = distinct(focus)
+	return
+}
+
+// validate validates the focus node against the shape and returns the results; shapes that
+// recursively refer to themselves are assumed to conform
+func (v *validator) validate(s *shape, focus rdf.Term) (results []Result) {
+	key := rdf.TermKey(s.term) + " " + rdf.TermKey(focus)
+	if s.deactivated || v.active[key] {
+		return
+	}
+	v.active[key] = true
+	defer delete(v.active, key)
+	values := []rdf.Term{focus}
+	if s.path != nil {
+		values = s.path.values(v.data, focus)
+	}
+	for _, c := range s.constraints {
+		for _, f := range c.check(v, focus, values) {
+			results = append(results, s.result(focus, c.component, f))
+		}
+	}
+	for _, prop := range s.properties {
+		for _, value := range values {
+			results = append(results, v.validate(prop, value)...)
+		}
+	}
+	return
+}
+
+// conforms checks if the term conforms to the shape
+func (v *validator) conforms(s *shape, term rdf.Term) (ok bool) {
+	ok = len(v.validate(s, term)) == 0
+	return
+}
+
+// hasClass checks if the term is an instance of the class or one of its sub classes
+func (v *validator) hasClass(term rdf.Term, class rdf.Term) (ok bool) {
+	if term.Type() == rdf.TermLiteral {
+		return
+	}
+	for it := v.data.Match(term, rdfType, nil); it.Next(); {
+		if v.superClasses(it.Edge().Object.Term)[rdf.TermKey(class)] {
+			ok = true
+			return
+		}
+	}
+	return
+}
+
+// superClasses returns the TermKeys of the class and its transitive super classes
+func (v *validator) superClasses(class rdf.Term) (supers map[string]bool) {
+	key := rdf.TermKey(class)
+	if supers = v.classes[key]; supers != nil {
+		return
+	}
+	supers = map[string]bool{key: true}
+	queue := []rdf.Term{class}
+	for len(queue) > 0 {
+		for it := v.data.Match(queue[0], rdfsSubClassOf, nil); it.Next(); {
+			if super := it.Edge().Object.Term; !supers[rdf.TermKey(super)] {
+				supers[rdf.TermKey(super)] = true
+				queue = append(queue, super)
+			}
+		}
+		queue = queue[1:]
+	}
+	v.classes[key] = supers
+	return
+}
+
+// objects returns the objects of the triples with subject and predicate in the data graph
+func (v *validator) objects(subj rdf.Term, pred rdf.Term) (objects []rdf.Term) {
+	for it := v.data.Match(subj, pred, nil); it.Next(); {
+		objects = append(objects, it.Edge().Object.Term)
+	}
+	return
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sparql
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// aggregate is an aggregate function of a select, having or order by clause; its value is bound
+// to an internal variable for each group
+type aggregate struct {
+	name     string // COUNT, SUM, MIN, MAX, AVG, SAMPLE or GROUP_CONCAT
+	arg      expr   // argument (nil for COUNT(*))
+	distinct bool   // aggregate distinct values only
+	sep      string // separator of GROUP_CONCAT
+	result   string // variable bound to the value
+}
+
+// group partitions the solutions by the group conditions and evaluates the aggregates for each
+// group; without group conditions all solutions form a single group
+func (e *evaluator) group(q *Query, in []Binding) (sols []Binding) {
+	var keys []string
+	groups := make(map[string][]Binding)
+	first := make(map[string]Binding)
+	for _, sol := range in {
+		g := Binding{}
+		for i, cond := range q.groupBy {
+			val, err := cond.expr.eval(e, sol)
+			if err != nil {
+				continue
+			}
+			name := cond.name
+			if name == "" {
+				name = "." + strconv.Itoa(i)
+			}
+			g[name] = val
+		}
+		key := bindingKey(g, sortedVars(g))
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+			first[key] = g
+		}
+		groups[key] = append(groups[key], sol)
+	}
+	if len(q.groupBy) == 0 && len(in) == 0 {
+		keys = append(keys, "")
+		first[""] = Binding{}
+	}
+	for _, key := range keys {
+		sol := Binding{}
+		for name, val := range first[key] {
+			if !strings.HasPrefix(name, ".") {
+				sol[name] = val
+			}
+		}
+		for _, agg := range q.aggs {
+			if val, err := e.aggregate(agg, groups[key]); err == nil {
+				sol[agg.result] = val
+			}
+		}
+		sols = append(sols, sol)
+	}
+	return
+}
+
+// sortedVars returns the variables of a binding in alphabetical order
+func sortedVars(b Binding) (vars []string) {
+	for name := range b {
+		vars = append(vars, name)
+	}
+	sort.Strings(vars)
+	return
+}
+
+// aggregate evaluates an aggregate over the solutions of a group; solutions for which the argument
+// cannot be evaluated are ignored
+func (e *evaluator) aggregate(agg *aggregate, sols []Binding) (val rdf.Term, err error) {
+	if agg.arg == nil {
+		count := len(sols)
+		if agg.distinct {
+			seen := make(map[string]bool)
+			for _, sol := range sols {
+				seen[bindingKey(sol, sortedVars(sol))+"\x00"+strings.Join(sortedVars(sol), " ")] =
+					true
+			}
+			count = len(seen)
+		}
+		val = rdf.NewTypedLiteral(strconv.Itoa(count), rdf.XsdInteger)
+		return
+	}
+	var vals []rdf.Term
+	seen := make(map[string]bool)
+	for _, sol := range sols {
+		v, e1 := agg.arg.eval(e, sol)
+		if e1 != nil {
+			continue
+		}
+		if agg.distinct {
+			if seen[rdf.TermKey(v)] {
+				continue
+			}
+			seen[rdf.TermKey(v)] = true
+		}
+		vals = append(vals, v)
+	}
+	switch agg.name {
+	case "COUNT":
+		val = rdf.NewTypedLiteral(strconv.Itoa(len(vals)), rdf.XsdInteger)
+	case "SUM", "AVG":
+		sum := numeric{kind: numInteger, rat: new(big.Rat)}
+		for _, v := range vals {
+			n, ok := toNumeric(v)
+			if !ok {
+				err = errType
+				return
+			}
+			if sum, err = arith("+", sum, n); err != nil {
+				return
+			}
+		}
+		if agg.name == "AVG" && len(vals) > 0 {
+			count := numeric{kind: numInteger, rat: new(big.Rat).SetInt64(int64(len(vals)))}
+			if sum, err = arith("/", sum, count); err != nil {
+				return
+			}
+		}
+		val = sum.literal()
+	case "MIN", "MAX":
+		for _, v := range vals {
+			cmp := 0
+			if val != nil {
+				cmp = orderTerms(v, val)
+			}
+			if val == nil || (agg.name == "MIN" && cmp < 0) || (agg.name == "MAX" && cmp > 0) {
+				val = v
+			}
+		}
+		if val == nil {
+			err = errType
+		}
+	case "SAMPLE":
+		if len(vals) == 0 {
+			err = errType
+			return
+		}
+		val = vals[0]
+	case "GROUP_CONCAT":
+		strs := make([]string, len(vals))
+		for i, v := range vals {
+			if v.Type() == rdf.TermBlankNode {
+				err = errType
+				return
+			}
+			strs[i] = v.String()
+		}
+		val = simpleLiteral(strings.Join(strs, agg.sep))
+	}
+	return
+}
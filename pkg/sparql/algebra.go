@@ -0,0 +1,733 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sparql
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// node is a term or a variable of a triple pattern; blank nodes of patterns are variables that
+// start with "_:"
+type node struct {
+	term rdf.Term // term (nil for variables)
+	name string   // variable name
+}
+
+// triple is a triple pattern; the predicate is either a node or a property path
+type triple struct {
+	s    node
+	p    node
+	path path // property path (nil for simple predicates)
+	o    node
+}
+
+// pattern is a graph pattern of the sparql algebra
+type pattern interface {
+	// collectVars adds the in-scope variables of the pattern in order of appearance
+	collectVars(vars *[]string, seen map[string]bool)
+}
+
+// bgp is a basic graph pattern
+type bgp struct {
+	triples []triple
+}
+
+// join joins the solutions of two patterns
+type join struct {
+	left, right pattern
+}
+
+// leftJoin extends the solutions of left with compatible solutions of right (optional)
+type leftJoin struct {
+	left, right pattern
+	cond        []expr // filters of the optional pattern
+}
+
+// union combines the solutions of two patterns
+type union struct {
+	left, right pattern
+}
+
+// minus removes the solutions of left that are compatible with a solution of right
+type minus struct {
+	left, right pattern
+}
+
+// filter removes the solutions for which a condition is not true
+type filter struct {
+	cond  []expr
+	inner pattern
+}
+
+// extend binds a variable to the value of an expression (bind)
+type extend struct {
+	inner pattern
+	name  string
+	expr  expr
+}
+
+// valuesBlock is inline data (values); nil terms are undefined
+type valuesBlock struct {
+	vars []string
+	rows [][]rdf.Term
+}
+
+// subQuery is a nested select query
+type subQuery struct {
+	query *Query
+}
+
+// collectVars adds the in-scope variables of the pattern in order of appearance
+func (pat *bgp) collectVars(vars *[]string, seen map[string]bool) {
+	for _, t := range pat.triples {
+		for _, n := range []node{t.s, t.p, t.o} {
+			addVar(n.name, vars, seen)
+		}
+	}
+}
+
+// collectVars adds the in-scope variables of the pattern in order of appearance
+func (pat *join) collectVars(vars *[]string, seen map[string]bool) {
+	pat.left.collectVars(vars, seen)
+	pat.right.collectVars(vars, seen)
+}
+
+// collectVars adds the in-scope variables of the pattern in order of appearance
+func (pat *leftJoin) collectVars(vars *[]string, seen map[string]bool) {
+	pat.left.collectVars(vars, seen)
+	pat.right.collectVars(vars, seen)
+}
+
+// collectVars adds the in-scope variables of the pattern in order of appearance
+func (pat *union) collectVars(vars *[]string, seen map[string]bool) {
+	pat.left.collectVars(vars, seen)
+	pat.right.collectVars(vars, seen)
+}
+
+// collectVars adds the in-scope variables of the pattern in order of appearance
+func (pat *minus) collectVars(vars *[]string, seen map[string]bool) {
+	pat.left.collectVars(vars, seen)
+}
+
+// collectVars adds the in-scope variables of the pattern in order of appearance
+func (pat *filter) collectVars(vars *[]string, seen map[string]bool) {
+	pat.inner.collectVars(vars, seen)
+}
+
+// collectVars adds the in-scope variables of the pattern in order of appearance
+func (pat *extend) collectVars(vars *[]string, seen map[string]bool) {
+	pat.inner.collectVars(vars, seen)
+	addVar(pat.name, vars, seen)
+}
+
+// collectVars adds the in-scope variables of the pattern in order of appearance
+func (pat *valuesBlock) collectVars(vars *[]string, seen map[string]bool) {
+	for _, name := range pat.vars {
+		addVar(name, vars, seen)
+	}
+}
+
+// collectVars adds the in-scope variables of the pattern in order of appearance
+func (pat *subQuery) collectVars(vars *[]string, seen map[string]bool) {
+	for _, name := range pat.query.Vars {
+		addVar(name, vars, seen)
+	}
+}
+
+// addVar adds a variable that has not been seen before; blank nodes and internal variables are
+// ignored
+func addVar(name string, vars *[]string, seen map[string]bool) {
+	if name == "" || seen[name] || strings.HasPrefix(name, "_:") || strings.HasPrefix(name, ".") {
+		return
+	}
+	seen[name] = true
+	*vars = append(*vars, name)
+}
+
+// evaluator evaluates queries over a graph
+type evaluator struct {
+	graph    *rdf.Graph
+	base     string                    // base iri of the query
+	now      time.Time                 // value of NOW()
+	regexps  map[string]*regexp.Regexp // compiled regular expressions
+	bnodes   map[string]rdf.BlankNode  // blank nodes created by BNODE(str)
+	numBlank int                       // number of created blank nodes
+	terms    []rdf.Term                // all subjects and objects of the graph
+}
+
+// newEvaluator creates an evaluator for the graph
+func newEvaluator(graph *rdf.Graph) (e *evaluator) {
+	e = &evaluator{graph: graph, now: time.Now(), regexps: make(map[string]*regexp.Regexp),
+		bnodes: make(map[string]rdf.BlankNode)}
+	return
+}
+
+// newBlankNode creates a new blank node whose label is not used by a node of the graph
+func (e *evaluator) newBlankNode() (blank rdf.BlankNode) {
+	for {
+		e.numBlank++
+		blank = rdf.NewBlankNode("q" + strconv.Itoa(e.numBlank))
+		if e.graph.Node(blank) == nil {
+			return
+		}
+	}
+}
+
+// evalQuery evaluates the where clause and the solution modifiers of a query; the solutions of
+// select queries are projected
+func (e *evaluator) evalQuery(q *Query, init Binding) (sols []Binding, err error) {
+	sols, err = e.eval(q.where, init)
+	if err != nil {
+		return
+	}
+	if q.values != nil {
+		var vals []Binding
+		vals, err = e.eval(q.values, Binding{})
+		if err != nil {
+			return
+		}
+		sols = joinSolutions(sols, vals)
+	}
+	if len(q.groupBy) > 0 || len(q.aggs) > 0 {
+		sols = e.group(q, sols)
+		sols = e.filter(q.having, sols)
+	}
+	for _, proj := range q.project {
+		if proj.expr != nil {
+			sols = e.extend(sols, proj.name, proj.expr)
+		}
+	}
+	if len(q.orderBy) > 0 {
+		e.order(q.orderBy, sols)
+	}
+	if q.Form == FormSelect {
+		sols = project(sols, q.Vars)
+		if q.distinct || q.reduced {
+			sols = distinct(sols, q.Vars)
+		}
+	}
+	if q.offset > 0 {
+		if q.offset >= len(sols) {
+			sols = nil
+		} else {
+			sols = sols[q.offset:]
+		}
+	}
+	if q.limit >= 0 && q.limit < len(sols) {
+		sols = sols[:q.limit]
+	}
+	return
+}
+
+// eval evaluates a graph pattern; init contains variables that are already bound (e.g. by EXISTS)
+func (e *evaluator) eval(pat pattern, init Binding) (sols []Binding, err error) {
+	switch t := pat.(type) {
+	case *bgp:
+		e.matchTriples(orderTriples(t.triples, init), init, &sols)
+	case *join:
+		var left, right []Binding
+		if left, right, err = e.evalBoth(t.left, t.right, init); err == nil {
+			sols = joinSolutions(left, right)
+		}
+	case *leftJoin:
+		var left, right []Binding
+		if left, right, err = e.evalBoth(t.left, t.right, init); err == nil {
+			sols = e.leftJoin(left, right, t.cond)
+		}
+	case *union:
+		var left, right []Binding
+		if left, right, err = e.evalBoth(t.left, t.right, init); err == nil {
+			sols = append(left, right...)
+		}
+	case *minus:
+		var left, right []Binding
+		if left, right, err = e.evalBoth(t.left, t.right, init); err == nil {
+			sols = minusSolutions(left, right)
+		}
+	case *filter:
+		if sols, err = e.eval(t.inner, init); err == nil {
+			sols = e.filter(t.cond, sols)
+		}
+	case *extend:
+		if sols, err = e.eval(t.inner, init); err == nil {
+			sols = e.extend(sols, t.name, t.expr)
+		}
+	case *valuesBlock:
+		for _, row := range t.rows {
+			sol := Binding{}
+			for i, term := range row {
+				if term != nil {
+					sol[t.vars[i]] = term
+				}
+			}
+			if merged, ok := merge(init, sol); ok {
+				sols = append(sols, merged)
+			}
+		}
+	case *subQuery:
+		var sub []Binding
+		if sub, err = e.evalQuery(t.query, Binding{}); err == nil {
+			sols = joinSolutions([]Binding{init}, sub)
+		}
+	}
+	return
+}
+
+// evalBoth evaluates two graph patterns
+func (e *evaluator) evalBoth(left, right pattern, init Binding) (l, r []Binding, err error) {
+	if l, err = e.eval(left, init); err != nil {
+		return
+	}
+	r, err = e.eval(right, init)
+	return
+}
+
+// orderTriples orders triple patterns so that patterns with more bound terms are matched first
+func orderTriples(triples []triple, init Binding) (ordered []triple) {
+	bound := make(map[string]bool)
+	for name := range init {
+		bound[name] = true
+	}
+	rest := append([]triple{}, triples...)
+	for len(rest) > 0 {
+		best, bestScore := 0, -1
+		for i, t := range rest {
+			score := 0
+			for j, n := range []node{t.s, t.o, t.p} {
+				if n.term != nil || bound[n.name] {
+					score += 3 - j
+				}
+			}
+			if t.path != nil {
+				score--
+			}
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		t := rest[best]
+		for _, n := range []node{t.s, t.p, t.o} {
+			if n.name != "" {
+				bound[n.name] = true
+			}
+		}
+		ordered = append(ordered, t)
+		rest = append(rest[:best], rest[best+1:]...)
+	}
+	return
+}
+
+// matchTriples matches the triple patterns one after another and adds all solutions
+func (e *evaluator) matchTriples(triples []triple, b Binding, sols *[]Binding) {
+	if len(triples) == 0 {
+		*sols = append(*sols, b)
+		return
+	}
+	t := triples[0]
+	s, o := resolve(t.s, b), resolve(t.o, b)
+	if t.path != nil {
+		for _, pair := range e.evalPath(t.path, s, o) {
+			if nb, ok := bindTriple(b, t, pair[0], nil, pair[1]); ok {
+				e.matchTriples(triples[1:], nb, sols)
+			}
+		}
+		return
+	}
+	p := resolve(t.p, b)
+	if p != nil && p.Type() != rdf.TermIRI || s != nil && s.Type() == rdf.TermLiteral {
+		return
+	}
+	for it := e.graph.Match(s, p, o); it.Next(); {
+		edge := it.Edge()
+		nb, ok := bindTriple(b, t, edge.Subject.Term, edge.Pred, edge.Object.Term)
+		if ok {
+			e.matchTriples(triples[1:], nb, sols)
+		}
+	}
+}
+
+// resolve returns the term of a node or the bound value of a variable (nil if unbound)
+func resolve(n node, b Binding) (term rdf.Term) {
+	term = n.term
+	if term == nil {
+		term = b[n.name]
+	}
+	return
+}
+
+// bindTriple binds the variables of a triple pattern to the terms of a matching triple (p is nil
+// for property paths); ok is false if the terms do not match
+func bindTriple(b Binding, t triple, s, p, o rdf.Term) (nb Binding, ok bool) {
+	nb = b
+	copied := false
+	for _, pair := range []struct {
+		n    node
+		term rdf.Term
+	}{{t.s, s}, {t.p, p}, {t.o, o}} {
+		if pair.term == nil {
+			continue
+		}
+		if old := resolve(pair.n, nb); old != nil {
+			if !rdf.EqualTerms(old, pair.term) {
+				return
+			}
+			continue
+		}
+		if !copied {
+			nb, copied = copyBinding(b), true
+		}
+		nb[pair.n.name] = pair.term
+	}
+	ok = true
+	return
+}
+
+// copyBinding copies a binding
+func copyBinding(b Binding) (c Binding) {
+	c = make(Binding, len(b)+1)
+	for name, term := range b {
+		c[name] = term
+	}
+	return
+}
+
+// merge merges two solutions; ok is false if they are not compatible
+func merge(a, b Binding) (m Binding, ok bool) {
+	for name, term := range b {
+		if old, found := a[name]; found && !rdf.EqualTerms(old, term) {
+			return
+		}
+	}
+	m = copyBinding(a)
+	for name, term := range b {
+		m[name] = term
+	}
+	ok = true
+	return
+}
+
+// commonVars returns the variables that are bound in all solutions of both sides
+func commonVars(left, right []Binding) (vars []string) {
+	if len(left) == 0 || len(right) == 0 {
+		return
+	}
+	for name := range left[0] {
+		found := true
+		for _, sols := range [][]Binding{left, right} {
+			for _, sol := range sols {
+				if _, ok := sol[name]; !ok {
+					found = false
+					break
+				}
+			}
+		}
+		if found {
+			vars = append(vars, name)
+		}
+	}
+	sort.Strings(vars)
+	return
+}
+
+// joinSolutions joins two sequences of solutions; solutions are hashed by variables that are bound
+// on both sides
+func joinSolutions(left, right []Binding) (sols []Binding) {
+	vars := commonVars(left, right)
+	buckets := make(map[string][]Binding)
+	for _, r := range right {
+		key := bindingKey(r, vars)
+		buckets[key] = append(buckets[key], r)
+	}
+	for _, l := range left {
+		for _, r := range buckets[bindingKey(l, vars)] {
+			if m, ok := merge(l, r); ok {
+				sols = append(sols, m)
+			}
+		}
+	}
+	return
+}
+
+// leftJoin extends the left solutions with all compatible right solutions that satisfy the
+// conditions; left solutions without such solutions are kept
+func (e *evaluator) leftJoin(left, right []Binding, cond []expr) (sols []Binding) {
+	for _, l := range left {
+		found := false
+		for _, r := range right {
+			m, ok := merge(l, r)
+			if !ok || !e.satisfies(cond, m) {
+				continue
+			}
+			sols = append(sols, m)
+			found = true
+		}
+		if !found {
+			sols = append(sols, l)
+		}
+	}
+	return
+}
+
+// minusSolutions removes left solutions that are compatible with a right solution sharing at least
+// one variable
+func minusSolutions(left, right []Binding) (sols []Binding) {
+	for _, l := range left {
+		removed := false
+		for _, r := range right {
+			shared := false
+			for name := range r {
+				if _, ok := l[name]; ok {
+					shared = true
+					break
+				}
+			}
+			if _, ok := merge(l, r); ok && shared {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			sols = append(sols, l)
+		}
+	}
+	return
+}
+
+// satisfies checks if the effective boolean values of all conditions are true
+func (e *evaluator) satisfies(cond []expr, b Binding) (ok bool) {
+	for _, c := range cond {
+		val, err := c.eval(e, b)
+		if err != nil {
+			return
+		}
+		if ok, err = ebv(val); err != nil || !ok {
+			ok = false
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// filter returns the solutions that satisfy all conditions
+func (e *evaluator) filter(cond []expr, in []Binding) (sols []Binding) {
+	if len(cond) == 0 {
+		sols = in
+		return
+	}
+	for _, sol := range in {
+		if e.satisfies(cond, sol) {
+			sols = append(sols, sol)
+		}
+	}
+	return
+}
+
+// extend binds a variable to the value of an expression in all solutions; the variable stays
+// unbound if the evaluation fails
+func (e *evaluator) extend(in []Binding, name string, ex expr) (sols []Binding) {
+	for _, sol := range in {
+		if _, ok := sol[name]; ok {
+			sols = append(sols, sol)
+			continue
+		}
+		val, err := ex.eval(e, sol)
+		if err == nil {
+			sol = copyBinding(sol)
+			sol[name] = val
+		}
+		sols = append(sols, sol)
+	}
+	return
+}
+
+// order sorts the solutions by the order conditions
+func (e *evaluator) order(conds []orderCond, sols []Binding) {
+	keys := make([][]rdf.Term, len(sols))
+	for i, sol := range sols {
+		keys[i] = make([]rdf.Term, len(conds))
+		for j, c := range conds {
+			keys[i][j], _ = c.expr.eval(e, sol)
+		}
+	}
+	idx := make([]int, len(sols))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		for j, c := range conds {
+			cmp := orderTerms(keys[idx[a]][j], keys[idx[b]][j])
+			if c.desc {
+				cmp = -cmp
+			}
+			if cmp != 0 {
+				return cmp < 0
+			}
+		}
+		return false
+	})
+	sorted := make([]Binding, len(sols))
+	for i, j := range idx {
+		sorted[i] = sols[j]
+	}
+	copy(sols, sorted)
+}
+
+// project restricts the solutions to the variables
+func project(in []Binding, vars []string) (sols []Binding) {
+	for _, sol := range in {
+		p := make(Binding, len(vars))
+		for _, name := range vars {
+			if term, ok := sol[name]; ok {
+				p[name] = term
+			}
+		}
+		sols = append(sols, p)
+	}
+	return
+}
+
+// distinct removes duplicate solutions
+func distinct(in []Binding, vars []string) (sols []Binding) {
+	seen := make(map[string]bool)
+	for _, sol := range in {
+		key := bindingKey(sol, vars)
+		if !seen[key] {
+			seen[key] = true
+			sols = append(sols, sol)
+		}
+	}
+	return
+}
+
+// bindingKey returns a string that identifies the values of the variables in a solution
+func bindingKey(b Binding, vars []string) (key string) {
+	var sb strings.Builder
+	for _, name := range vars {
+		if term, ok := b[name]; ok {
+			sb.WriteString(rdf.TermKey(term))
+		}
+		sb.WriteByte(0)
+	}
+	key = sb.String()
+	return
+}
+
+// construct instantiates the template with all solutions; blank nodes of the template are
+// replaced by new blank nodes for each solution
+func (e *evaluator) construct(template []triple, sols []Binding) (trip []rdf.Triple) {
+	seen := make(map[string]bool)
+	for _, sol := range sols {
+		blanks := make(map[string]rdf.BlankNode)
+		inst := func(n node) (term rdf.Term) {
+			term = resolve(n, sol)
+			if blank, ok := term.(rdf.BlankNode); ok && n.term != nil {
+				if _, found := blanks[blank.String()]; !found {
+					blanks[blank.String()] = e.newBlankNode()
+				}
+				term = blanks[blank.String()]
+			}
+			return
+		}
+		for _, t := range template {
+			s, p, o := inst(t.s), inst(t.p), inst(t.o)
+			if s == nil || p == nil || o == nil || s.Type() == rdf.TermLiteral ||
+				p.Type() != rdf.TermIRI {
+				continue
+			}
+			e.addTriple(&trip, seen, rdf.Triple{Sub: s, Pred: p, Obj: o})
+		}
+	}
+	return
+}
+
+// describe returns the triples describing the resources; blank node objects are described as well
+func (e *evaluator) describe(nodes []node, sols []Binding) (trip []rdf.Triple) {
+	seen := make(map[string]bool)
+	described := make(map[string]bool)
+	var queue []rdf.Term
+	for _, n := range nodes {
+		if n.term != nil {
+			queue = append(queue, n.term)
+			continue
+		}
+		for _, sol := range sols {
+			if term, ok := sol[n.name]; ok {
+				queue = append(queue, term)
+			}
+		}
+	}
+	for len(queue) > 0 {
+		res := queue[0]
+		queue = queue[1:]
+		if res.Type() == rdf.TermLiteral || described[rdf.TermKey(res)] {
+			continue
+		}
+		described[rdf.TermKey(res)] = true
+		for it := e.graph.Match(res, nil, nil); it.Next(); {
+			t := it.Triple()
+			e.addTriple(&trip, seen, t)
+			if t.Obj.Type() == rdf.TermBlankNode {
+				queue = append(queue, t.Obj)
+			}
+		}
+	}
+	return
+}
+
+// addTriple adds a triple that has not been added before
+func (e *evaluator) addTriple(trip *[]rdf.Triple, seen map[string]bool, t rdf.Triple) {
+	key := rdf.TermKey(t.Sub) + " " + rdf.TermKey(t.Pred) + " " + rdf.TermKey(t.Obj)
+	if !seen[key] {
+		seen[key] = true
+		*trip = append(*trip, t)
+	}
+}
@@ -0,0 +1,668 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sparql
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// errType is returned by expressions with invalid arguments (e.g. unbound variables)
+var errType = errors.New("type error")
+
+// expr is an expression of a filter, bind, select or order by clause
+type expr interface {
+	eval(e *evaluator, b Binding) (val rdf.Term, err error)
+}
+
+// varExpr is a variable
+type varExpr struct {
+	name string
+}
+
+// constExpr is a constant term
+type constExpr struct {
+	term rdf.Term
+}
+
+// unaryExpr is an unary operation ('!', '-' or '+')
+type unaryExpr struct {
+	op  string
+	arg expr
+}
+
+// binaryExpr is a binary operation (logical, comparison or arithmetic operator)
+type binaryExpr struct {
+	op          string
+	left, right expr
+}
+
+// inExpr is an IN or NOT IN expression
+type inExpr struct {
+	arg  expr
+	list []expr
+	not  bool
+}
+
+// existsExpr is an EXISTS or NOT EXISTS expression
+type existsExpr struct {
+	pat pattern
+	not bool
+}
+
+// callExpr is a call of a builtin function (upper case name) or of a function iri (e.g. casts)
+type callExpr struct {
+	name string
+	args []expr
+}
+
+// eval returns the bound value of the variable
+func (ex *varExpr) eval(e *evaluator, b Binding) (val rdf.Term, err error) {
+	val, ok := b[ex.name]
+	if !ok {
+		err = errType
+	}
+	return
+}
+
+// eval returns the constant term
+func (ex *constExpr) eval(e *evaluator, b Binding) (val rdf.Term, err error) {
+	val = ex.term
+	return
+}
+
+// eval evaluates the unary operation
+func (ex *unaryExpr) eval(e *evaluator, b Binding) (val rdf.Term, err error) {
+	arg, err := ex.arg.eval(e, b)
+	if err != nil {
+		return
+	}
+	if ex.op == "!" {
+		var ok bool
+		if ok, err = ebv(arg); err == nil {
+			val = boolLiteral(!ok)
+		}
+		return
+	}
+	n, ok := toNumeric(arg)
+	if !ok {
+		err = errType
+		return
+	}
+	if ex.op == "-" {
+		n, err = arith("-", numeric{kind: numInteger, rat: new(big.Rat)}, n)
+	}
+	val = n.literal()
+	return
+}
+
+// eval evaluates the binary operation
+func (ex *binaryExpr) eval(e *evaluator, b Binding) (val rdf.Term, err error) {
+	if ex.op == "||" || ex.op == "&&" {
+		val, err = ex.evalLogical(e, b)
+		return
+	}
+	left, err := ex.left.eval(e, b)
+	if err != nil {
+		return
+	}
+	right, err := ex.right.eval(e, b)
+	if err != nil {
+		return
+	}
+	var cmp int
+	var eq bool
+	switch ex.op {
+	case "=", "!=":
+		if eq, err = equalTerms(left, right); err == nil {
+			val = boolLiteral(eq == (ex.op == "="))
+		}
+	case "<", ">", "<=", ">=":
+		if cmp, err = compareValues(left, right); err == nil {
+			val = boolLiteral(ex.op == "<" && cmp < 0 || ex.op == ">" && cmp > 0 ||
+				ex.op == "<=" && cmp <= 0 || ex.op == ">=" && cmp >= 0)
+		}
+	default:
+		l, okL := toNumeric(left)
+		r, okR := toNumeric(right)
+		if !okL || !okR {
+			err = errType
+			return
+		}
+		var n numeric
+		if n, err = arith(ex.op, l, r); err == nil {
+			val = n.literal()
+		}
+	}
+	return
+}
+
+// evalLogical evaluates '||' and '&&'; an error on one side is ignored if the other side decides
+// the result
+func (ex *binaryExpr) evalLogical(e *evaluator, b Binding) (val rdf.Term, err error) {
+	var res [2]bool
+	var errs [2]error
+	for i, arg := range []expr{ex.left, ex.right} {
+		var term rdf.Term
+		if term, errs[i] = arg.eval(e, b); errs[i] == nil {
+			res[i], errs[i] = ebv(term)
+		}
+	}
+	decisive := ex.op == "||"
+	for i := range res {
+		if errs[i] == nil && res[i] == decisive {
+			val = boolLiteral(decisive)
+			return
+		}
+	}
+	if errs[0] != nil || errs[1] != nil {
+		err = errType
+		return
+	}
+	val = boolLiteral(!decisive)
+	return
+}
+
+// eval checks if the value is (not) equal to one of the list elements
+func (ex *inExpr) eval(e *evaluator, b Binding) (val rdf.Term, err error) {
+	arg, err := ex.arg.eval(e, b)
+	if err != nil {
+		return
+	}
+	failed := false
+	for _, item := range ex.list {
+		term, e1 := item.eval(e, b)
+		var eq bool
+		if e1 == nil {
+			eq, e1 = equalTerms(arg, term)
+		}
+		if e1 != nil {
+			failed = true
+		} else if eq {
+			val = boolLiteral(!ex.not)
+			return
+		}
+	}
+	if failed {
+		err = errType
+		return
+	}
+	val = boolLiteral(ex.not)
+	return
+}
+
+// eval checks if the pattern has a solution with the variables of the binding
+func (ex *existsExpr) eval(e *evaluator, b Binding) (val rdf.Term, err error) {
+	sols, err := e.eval(ex.pat, b)
+	if err == nil {
+		val = boolLiteral((len(sols) > 0) != ex.not)
+	}
+	return
+}
+
+// eval calls the function
+func (ex *callExpr) eval(e *evaluator, b Binding) (val rdf.Term, err error) {
+	switch ex.name {
+	case "BOUND":
+		_, ok := b[ex.args[0].(*varExpr).name]
+		val = boolLiteral(ok)
+		return
+	case "IF":
+		var cond rdf.Term
+		var ok bool
+		if cond, err = ex.args[0].eval(e, b); err != nil {
+			return
+		}
+		if ok, err = ebv(cond); err != nil {
+			return
+		}
+		if ok {
+			val, err = ex.args[1].eval(e, b)
+		} else {
+			val, err = ex.args[2].eval(e, b)
+		}
+		return
+	case "COALESCE":
+		for _, arg := range ex.args {
+			if val, err = arg.eval(e, b); err == nil {
+				return
+			}
+		}
+		err = errType
+		return
+	}
+	args := make([]rdf.Term, len(ex.args))
+	for i, arg := range ex.args {
+		if args[i], err = arg.eval(e, b); err != nil {
+			return
+		}
+	}
+	val, err = e.call(ex.name, args, b)
+	return
+}
+
+// boolLiteral returns a xsd:boolean literal
+func boolLiteral(val bool) (lit rdf.Literal) {
+	lit = rdf.NewTypedLiteral(strconv.FormatBool(val), rdf.XsdBoolean)
+	return
+}
+
+// simpleLiteral returns a literal without datatype and language
+func simpleLiteral(str string) (lit rdf.Literal) {
+	lit = rdf.NewTypedLiteral(str, "")
+	return
+}
+
+// ebv returns the effective boolean value of a term
+func ebv(term rdf.Term) (val bool, err error) {
+	lit, ok := term.(rdf.Literal)
+	if !ok {
+		err = errType
+		return
+	}
+	switch dt := lit.Datatype(); {
+	case dt == rdf.XsdBoolean:
+		v, e := lit.Parse()
+		val = e == nil && v.(bool)
+	case dt == rdf.XsdString || dt == rdf.RdfLangString:
+		val = lit.String() != ""
+	case numericTypes[dt] >= numInteger:
+		n, ok := toNumeric(lit)
+		if ok && n.kind >= numFloat {
+			val = n.f != 0 && !math.IsNaN(n.f)
+		} else if ok {
+			val = n.rat.Sign() != 0
+		}
+	default:
+		err = errType
+	}
+	return
+}
+
+// numeric kinds in order of type promotion
+const (
+	numNone = iota
+	numInteger
+	numDecimal
+	numFloat
+	numDouble
+)
+
+// numericTypes maps the numeric datatypes to their kind
+var numericTypes = map[string]int{
+	rdf.XsdInteger: numInteger, rdf.XsdNonPositiveInteger: numInteger,
+	rdf.XsdNegativeInteger: numInteger, rdf.XsdNonNegativeInteger: numInteger,
+	rdf.XsdPositiveInteger: numInteger, rdf.XsdLong: numInteger, rdf.XsdInt: numInteger,
+	rdf.XsdShort: numInteger, rdf.XsdByte: numInteger, rdf.XsdUnsignedLong: numInteger,
+	rdf.XsdUnsignedInt: numInteger, rdf.XsdUnsignedShort: numInteger,
+	rdf.XsdUnsignedByte: numInteger, rdf.XsdDecimal: numDecimal, rdf.XsdFloat: numFloat,
+	rdf.XsdDouble: numDouble,
+}
+
+// numeric is the value of a numeric literal
+type numeric struct {
+	kind int      // numInteger, numDecimal, numFloat or numDouble
+	rat  *big.Rat // value of integers and decimals
+	f    float64  // value of floats and doubles
+}
+
+// toNumeric returns the value of a valid numeric literal
+func toNumeric(term rdf.Term) (n numeric, ok bool) {
+	lit, isLit := term.(rdf.Literal)
+	if !isLit {
+		return
+	}
+	n.kind = numericTypes[lit.Datatype()]
+	if n.kind == numNone {
+		return
+	}
+	val, err := lit.Parse()
+	if err != nil {
+		return
+	}
+	ok = true
+	switch v := val.(type) {
+	case rdf.Decimal:
+		n.rat = v.Rat()
+	case float64:
+		n.f = v
+	case float32:
+		n.f = float64(v)
+	case *big.Int:
+		n.rat = new(big.Rat).SetInt(v)
+	case int64:
+		n.rat = new(big.Rat).SetInt64(v)
+	case int32:
+		n.rat = new(big.Rat).SetInt64(int64(v))
+	case int16:
+		n.rat = new(big.Rat).SetInt64(int64(v))
+	case int8:
+		n.rat = new(big.Rat).SetInt64(int64(v))
+	case uint64:
+		n.rat = new(big.Rat).SetInt(new(big.Int).SetUint64(v))
+	case uint32:
+		n.rat = new(big.Rat).SetInt64(int64(v))
+	case uint16:
+		n.rat = new(big.Rat).SetInt64(int64(v))
+	case uint8:
+		n.rat = new(big.Rat).SetInt64(int64(v))
+	default:
+		ok = false
+	}
+	return
+}
+
+// float returns the value as float64
+func (n numeric) float() (f float64) {
+	if n.kind >= numFloat {
+		f = n.f
+	} else {
+		f, _ = n.rat.Float64()
+	}
+	return
+}
+
+// promote converts the value to a numeric kind that is not lower than the current one
+func (n numeric) promote(kind int) (p numeric) {
+	p = n
+	if kind >= numFloat && n.kind < numFloat {
+		p.f = n.float()
+	}
+	if kind > p.kind {
+		p.kind = kind
+	}
+	return
+}
+
+// literal returns the value as literal of xsd:integer, xsd:decimal, xsd:float or xsd:double
+func (n numeric) literal() (lit rdf.Literal) {
+	switch n.kind {
+	case numInteger:
+		lit = rdf.NewTypedLiteral(new(big.Int).Quo(n.rat.Num(), n.rat.Denom()).String(),
+			rdf.XsdInteger)
+	case numDecimal:
+		d, _ := rdf.ParseDecimal(n.rat.FloatString(20))
+		lit, _ = rdf.NewLiteral(d, rdf.XsdDecimal)
+	case numFloat:
+		lit, _ = rdf.NewLiteral(float32(n.f), rdf.XsdFloat)
+	default:
+		lit, _ = rdf.NewLiteral(n.f, rdf.XsdDouble)
+	}
+	return
+}
+
+// arith applies an arithmetic operator ('+', '-', '*' or '/') with numeric type promotion; the
+// division of integers returns a decimal
+func arith(op string, a, b numeric) (n numeric, err error) {
+	kind := a.kind
+	if b.kind > kind {
+		kind = b.kind
+	}
+	if op == "/" && kind == numInteger {
+		kind = numDecimal
+	}
+	a, b = a.promote(kind), b.promote(kind)
+	n.kind = kind
+	if kind >= numFloat {
+		switch op {
+		case "+":
+			n.f = a.f + b.f
+		case "-":
+			n.f = a.f - b.f
+		case "*":
+			n.f = a.f * b.f
+		case "/":
+			n.f = a.f / b.f
+		}
+		if kind == numFloat {
+			n.f = float64(float32(n.f))
+		}
+		return
+	}
+	n.rat = new(big.Rat)
+	switch op {
+	case "+":
+		n.rat.Add(a.rat, b.rat)
+	case "-":
+		n.rat.Sub(a.rat, b.rat)
+	case "*":
+		n.rat.Mul(a.rat, b.rat)
+	case "/":
+		if b.rat.Sign() == 0 {
+			err = errors.New("division by zero")
+			return
+		}
+		n.rat.Quo(a.rat, b.rat)
+	}
+	return
+}
+
+// compareValues compares the values of two literals of compatible datatypes (numbers, strings,
+// booleans, date and time values or durations)
+func compareValues(a, b rdf.Term) (cmp int, err error) {
+	la, okA := a.(rdf.Literal)
+	lb, okB := b.(rdf.Literal)
+	if !okA || !okB {
+		err = errType
+		return
+	}
+	if na, ok := toNumeric(la); ok {
+		nb, ok := toNumeric(lb)
+		if !ok {
+			err = errType
+			return
+		}
+		kind := na.kind
+		if nb.kind > kind {
+			kind = nb.kind
+		}
+		na, nb = na.promote(kind), nb.promote(kind)
+		switch {
+		case kind < numFloat:
+			cmp = na.rat.Cmp(nb.rat)
+		case math.IsNaN(na.f) || math.IsNaN(nb.f):
+			err = errType
+		case na.f < nb.f:
+			cmp = -1
+		case na.f > nb.f:
+			cmp = 1
+		}
+		return
+	}
+	dtA, dtB := la.Datatype(), lb.Datatype()
+	switch {
+	case dtA == rdf.XsdString && dtB == rdf.XsdString,
+		dtA == rdf.RdfLangString && dtB == rdf.RdfLangString &&
+			strings.EqualFold(la.Lang(), lb.Lang()):
+		cmp = strings.Compare(la.String(), lb.String())
+	case dtA == rdf.XsdBoolean && dtB == rdf.XsdBoolean:
+		va, errA := la.Parse()
+		vb, errB := lb.Parse()
+		if errA != nil || errB != nil {
+			err = errType
+			return
+		}
+		if va != vb {
+			cmp = 1
+			if va == false {
+				cmp = -1
+			}
+		}
+	case isDuration(dtA) && isDuration(dtB):
+		cmp, err = compareDurations(la, lb)
+	case temporalCategory(dtA) != "" && temporalCategory(dtA) == temporalCategory(dtB):
+		ta, errA := la.ToTime()
+		tb, errB := lb.ToTime()
+		if errA != nil || errB != nil {
+			err = errType
+			return
+		}
+		if ta.Before(tb) {
+			cmp = -1
+		} else if ta.After(tb) {
+			cmp = 1
+		}
+	default:
+		err = errType
+	}
+	return
+}
+
+// isDuration checks if the datatype is xsd:duration or derived from it
+func isDuration(dt string) (ok bool) {
+	ok = dt == rdf.XsdDuration || dt == rdf.XsdDayTimeDuration || dt == rdf.XsdYearMonthDuration
+	return
+}
+
+// temporalCategory returns a name for datatypes whose values can be compared with each other
+// (empty for other datatypes)
+func temporalCategory(dt string) (cat string) {
+	switch dt {
+	case rdf.XsdDateTime, rdf.XsdDateTimeStamp:
+		cat = "dateTime"
+	case rdf.XsdDate, rdf.XsdTime, rdf.XsdYear, rdf.XsdYearMonth, rdf.XsdMonth, rdf.XsdMonthDay,
+		rdf.XsdDay:
+		cat = dt
+	}
+	return
+}
+
+// compareDurations compares two durations by months and then by seconds
+func compareDurations(a, b rdf.Literal) (cmp int, err error) {
+	va, errA := a.Parse()
+	vb, errB := b.Parse()
+	da, okA := va.(rdf.Duration)
+	db, okB := vb.(rdf.Duration)
+	if errA != nil || errB != nil || !okA || !okB {
+		err = errType
+		return
+	}
+	value := func(d rdf.Duration) (months int64, nanos *big.Int) {
+		months = d.Months
+		nanos = new(big.Int).Mul(big.NewInt(d.Seconds), big.NewInt(1e9))
+		nanos.Add(nanos, big.NewInt(d.Nanoseconds))
+		if d.Negative {
+			months = -months
+			nanos.Neg(nanos)
+		}
+		return
+	}
+	ma, na := value(da)
+	mb, nb := value(db)
+	switch {
+	case ma < mb:
+		cmp = -1
+	case ma > mb:
+		cmp = 1
+	default:
+		cmp = na.Cmp(nb)
+	}
+	return
+}
+
+// equalTerms checks if two terms are equal; literals are compared by value if their datatypes
+// are compatible and an error is returned for literals of unknown datatypes
+func equalTerms(a, b rdf.Term) (eq bool, err error) {
+	if rdf.EqualTerms(a, b) {
+		eq = true
+		return
+	}
+	la, okA := a.(rdf.Literal)
+	lb, okB := b.(rdf.Literal)
+	if !okA || !okB {
+		return
+	}
+	cmp, e := compareValues(la, lb)
+	if e == nil {
+		eq = cmp == 0
+		return
+	}
+	_, knownA := rdf.LookupDatatype(la.Datatype())
+	_, knownB := rdf.LookupDatatype(lb.Datatype())
+	if (!knownA && la.Lang() == "") || (!knownB && lb.Lang() == "") {
+		err = errType
+	}
+	return
+}
+
+// orderTerms compares two terms for ORDER BY, MIN and MAX: unbound values (nil) come first, then
+// blank nodes, iris and literals; literals that cannot be compared by value are ordered by
+// lexical form, datatype and language
+func orderTerms(a, b rdf.Term) (cmp int) {
+	rank := func(t rdf.Term) (r int) {
+		if t != nil {
+			r = 1 + int(t.Type())
+			if t.Type() == rdf.TermLiteral {
+				r = 4
+			}
+		}
+		return
+	}
+	ra, rb := rank(a), rank(b)
+	switch {
+	case ra != rb:
+		cmp = ra - rb
+	case a == nil:
+	case ra == 4:
+		var err error
+		if cmp, err = compareValues(a, b); err == nil {
+			return
+		}
+		la, lb := a.(rdf.Literal), b.(rdf.Literal)
+		if cmp = strings.Compare(la.String(), lb.String()); cmp == 0 {
+			if cmp = strings.Compare(la.Datatype(), lb.Datatype()); cmp == 0 {
+				cmp = strings.Compare(la.Lang(), lb.Lang())
+			}
+		}
+	default:
+		cmp = strings.Compare(a.String(), b.String())
+	}
+	return
+}
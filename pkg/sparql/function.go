@@ -0,0 +1,612 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sparql
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"math"
+	"math/big"
+	mrand "math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// builtins maps the names of all builtin functions to the minimum and maximum number of arguments
+// (-1: any number)
+var builtins = map[string][2]int{
+	"STR": {1, 1}, "LANG": {1, 1}, "LANGMATCHES": {2, 2}, "DATATYPE": {1, 1}, "BOUND": {1, 1},
+	"IRI": {1, 1}, "URI": {1, 1}, "BNODE": {0, 1}, "RAND": {0, 0}, "ABS": {1, 1},
+	"CEIL": {1, 1}, "FLOOR": {1, 1}, "ROUND": {1, 1}, "CONCAT": {0, -1}, "STRLEN": {1, 1},
+	"UCASE": {1, 1}, "LCASE": {1, 1}, "ENCODE_FOR_URI": {1, 1}, "CONTAINS": {2, 2},
+	"STRSTARTS": {2, 2}, "STRENDS": {2, 2}, "STRBEFORE": {2, 2}, "STRAFTER": {2, 2},
+	"YEAR": {1, 1}, "MONTH": {1, 1}, "DAY": {1, 1}, "HOURS": {1, 1}, "MINUTES": {1, 1},
+	"SECONDS": {1, 1}, "TIMEZONE": {1, 1}, "TZ": {1, 1}, "NOW": {0, 0}, "UUID": {0, 0},
+	"STRUUID": {0, 0}, "MD5": {1, 1}, "SHA1": {1, 1}, "SHA256": {1, 1}, "SHA384": {1, 1},
+	"SHA512": {1, 1}, "COALESCE": {0, -1}, "IF": {3, 3}, "STRLANG": {2, 2}, "STRDT": {2, 2},
+	"SAMETERM": {2, 2}, "ISIRI": {1, 1}, "ISURI": {1, 1}, "ISBLANK": {1, 1},
+	"ISLITERAL": {1, 1}, "ISNUMERIC": {1, 1}, "REGEX": {2, 3}, "SUBSTR": {2, 3},
+	"REPLACE": {3, 4},
+}
+
+// call evaluates a function with evaluated arguments
+func (e *evaluator) call(name string, args []rdf.Term, b Binding) (val rdf.Term, err error) {
+	switch name {
+	case "STR":
+		if args[0].Type() == rdf.TermBlankNode {
+			err = errType
+			return
+		}
+		val = simpleLiteral(args[0].String())
+	case "LANG":
+		lit, ok := args[0].(rdf.Literal)
+		if !ok {
+			err = errType
+			return
+		}
+		val = simpleLiteral(lit.Lang())
+	case "DATATYPE":
+		lit, ok := args[0].(rdf.Literal)
+		if !ok {
+			err = errType
+			return
+		}
+		val = rdf.NewIRI(lit.Datatype())
+	case "LANGMATCHES":
+		tag, okTag := stringArg(args[0])
+		rng, okRng := stringArg(args[1])
+		if !okTag || !okRng {
+			err = errType
+			return
+		}
+		val = boolLiteral(langMatches(tag.String(), rng.String()))
+	case "IRI", "URI":
+		switch t := args[0].(type) {
+		case rdf.IRI:
+			val = t
+		case rdf.Literal:
+			if t.Datatype() != rdf.XsdString {
+				err = errType
+				return
+			}
+			val = rdf.NewIRI(rdf.ResolveIRI(e.base, t.String()))
+		default:
+			err = errType
+		}
+	case "BNODE":
+		if len(args) == 0 {
+			val = e.newBlankNode()
+			return
+		}
+		lit, ok := args[0].(rdf.Literal)
+		if !ok || lit.Datatype() != rdf.XsdString {
+			err = errType
+			return
+		}
+		vars := make([]string, 0, len(b))
+		for v := range b {
+			vars = append(vars, v)
+		}
+		sort.Strings(vars)
+		key := lit.String() + "\x00" + strings.Join(vars, " ") + bindingKey(b, vars)
+		blank, found := e.bnodes[key]
+		if !found {
+			blank = e.newBlankNode()
+			e.bnodes[key] = blank
+		}
+		val = blank
+	case "RAND":
+		val, _ = rdf.NewLiteral(mrand.Float64(), rdf.XsdDouble)
+	case "ABS", "CEIL", "FLOOR", "ROUND":
+		val, err = roundNumeric(name, args[0])
+	case "CONCAT":
+		val, err = concat(args)
+	case "STRLEN":
+		lit, ok := stringArg(args[0])
+		if !ok {
+			err = errType
+			return
+		}
+		val = rdf.NewTypedLiteral(strconv.Itoa(utf8.RuneCountInString(lit.String())),
+			rdf.XsdInteger)
+	case "UCASE", "LCASE":
+		lit, ok := stringArg(args[0])
+		if !ok {
+			err = errType
+			return
+		}
+		if name == "UCASE" {
+			val = withLexical(lit, strings.ToUpper(lit.String()))
+		} else {
+			val = withLexical(lit, strings.ToLower(lit.String()))
+		}
+	case "ENCODE_FOR_URI":
+		lit, ok := stringArg(args[0])
+		if !ok {
+			err = errType
+			return
+		}
+		val = simpleLiteral(encodeForURI(lit.String()))
+	case "CONTAINS", "STRSTARTS", "STRENDS", "STRBEFORE", "STRAFTER":
+		val, err = compareStrings(name, args[0], args[1])
+	case "SUBSTR":
+		val, err = substr(args)
+	case "REGEX", "REPLACE":
+		val, err = e.regex(name, args)
+	case "YEAR", "MONTH", "DAY", "HOURS", "MINUTES", "SECONDS", "TIMEZONE", "TZ":
+		val, err = dateTimePart(name, args[0])
+	case "NOW":
+		val = rdf.NewTypedLiteral(rdf.FormatDateTime(e.now), rdf.XsdDateTime)
+	case "UUID", "STRUUID":
+		uuid := make([]byte, 16)
+		if _, err = rand.Read(uuid); err != nil {
+			return
+		}
+		uuid[6] = uuid[6]&0x0f | 0x40
+		uuid[8] = uuid[8]&0x3f | 0x80
+		str := fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10],
+			uuid[10:])
+		if name == "UUID" {
+			val = rdf.NewIRI("urn:uuid:" + str)
+		} else {
+			val = simpleLiteral(str)
+		}
+	case "MD5", "SHA1", "SHA256", "SHA384", "SHA512":
+		lit, ok := args[0].(rdf.Literal)
+		if !ok || lit.Datatype() != rdf.XsdString {
+			err = errType
+			return
+		}
+		h := map[string]func() hash.Hash{"MD5": md5.New, "SHA1": sha1.New, "SHA256": sha256.New,
+			"SHA384": sha512.New384, "SHA512": sha512.New}[name]()
+		h.Write([]byte(lit.String()))
+		val = simpleLiteral(hex.EncodeToString(h.Sum(nil)))
+	case "STRLANG":
+		lit, ok := args[0].(rdf.Literal)
+		lang, okLang := args[1].(rdf.Literal)
+		if !ok || !okLang || lit.Datatype() != rdf.XsdString || lang.Datatype() != rdf.XsdString ||
+			lang.String() == "" {
+			err = errType
+			return
+		}
+		val = rdf.NewLangLiteral(lit.String(), lang.String())
+	case "STRDT":
+		lit, ok := args[0].(rdf.Literal)
+		dt, okDt := args[1].(rdf.IRI)
+		if !ok || !okDt || lit.Datatype() != rdf.XsdString {
+			err = errType
+			return
+		}
+		val = rdf.NewTypedLiteral(lit.String(), dt.String())
+	case "SAMETERM":
+		val = boolLiteral(rdf.EqualTerms(args[0], args[1]))
+	case "ISIRI", "ISURI":
+		val = boolLiteral(args[0].Type() == rdf.TermIRI)
+	case "ISBLANK":
+		val = boolLiteral(args[0].Type() == rdf.TermBlankNode)
+	case "ISLITERAL":
+		val = boolLiteral(args[0].Type() == rdf.TermLiteral)
+	case "ISNUMERIC":
+		_, ok := toNumeric(args[0])
+		val = boolLiteral(ok)
+	default:
+		if _, ok := rdf.LookupDatatype(name); ok && len(args) == 1 {
+			val, err = cast(args[0], name)
+			return
+		}
+		err = errors.New("Unknown function " + name)
+	}
+	return
+}
+
+// stringArg returns the argument if it is a simple literal, a xsd:string or a language-tagged
+// string
+func stringArg(term rdf.Term) (lit rdf.Literal, ok bool) {
+	lit, ok = term.(rdf.Literal)
+	ok = ok && (lit.Datatype() == rdf.XsdString || lit.Datatype() == rdf.RdfLangString)
+	return
+}
+
+// compatibleArgs checks if two string arguments are compatible (the second one has no language
+// tag or the same language tag as the first one)
+func compatibleArgs(a, b rdf.Term) (la, lb rdf.Literal, ok bool) {
+	la, okA := stringArg(a)
+	lb, okB := stringArg(b)
+	ok = okA && okB && (lb.Lang() == "" || strings.EqualFold(la.Lang(), lb.Lang()))
+	return
+}
+
+// withLexical returns a literal with the datatype and language tag of lit and a new lexical form
+func withLexical(lit rdf.Literal, str string) (res rdf.Literal) {
+	if lit.Lang() != "" {
+		res = rdf.NewLangLiteral(str, lit.Lang())
+	} else {
+		res = rdf.NewTypedLiteral(str, lit.Datatype())
+	}
+	return
+}
+
+// langMatches checks if a language tag matches a language range (RFC 4647 basic filtering)
+func langMatches(tag string, rng string) (ok bool) {
+	tag, rng = strings.ToLower(tag), strings.ToLower(rng)
+	if rng == "*" {
+		ok = tag != ""
+		return
+	}
+	ok = tag == rng || strings.HasPrefix(tag, rng+"-")
+	return
+}
+
+// roundNumeric evaluates ABS, CEIL, FLOOR and ROUND
+func roundNumeric(name string, arg rdf.Term) (val rdf.Term, err error) {
+	n, ok := toNumeric(arg)
+	if !ok {
+		err = errType
+		return
+	}
+	if n.kind >= numFloat {
+		switch name {
+		case "ABS":
+			n.f = math.Abs(n.f)
+		case "CEIL":
+			n.f = math.Ceil(n.f)
+		case "FLOOR":
+			n.f = math.Floor(n.f)
+		case "ROUND":
+			n.f = math.Floor(n.f + 0.5)
+		}
+		val = n.literal()
+		return
+	}
+	r := new(big.Rat).Set(n.rat)
+	switch name {
+	case "ABS":
+		r.Abs(r)
+	case "ROUND":
+		r.Add(r, big.NewRat(1, 2))
+		fallthrough
+	case "FLOOR":
+		r.SetInt(floorRat(r))
+	case "CEIL":
+		r.Neg(r)
+		r.SetInt(floorRat(r))
+		r.Neg(r)
+	}
+	n.rat = r
+	val = n.literal()
+	return
+}
+
+// floorRat returns the largest integer that is not greater than r
+func floorRat(r *big.Rat) (i *big.Int) {
+	i = new(big.Int).Div(r.Num(), r.Denom())
+	return
+}
+
+// concat concatenates string literals; the result keeps the language tag if all literals have the
+// same one
+func concat(args []rdf.Term) (val rdf.Term, err error) {
+	var sb strings.Builder
+	lang := ""
+	for i, arg := range args {
+		lit, ok := stringArg(arg)
+		if !ok {
+			err = errType
+			return
+		}
+		sb.WriteString(lit.String())
+		if i == 0 {
+			lang = lit.Lang()
+		} else if !strings.EqualFold(lang, lit.Lang()) {
+			lang = ""
+		}
+	}
+	if lang != "" {
+		val = rdf.NewLangLiteral(sb.String(), lang)
+	} else {
+		val = simpleLiteral(sb.String())
+	}
+	return
+}
+
+// encodeForURI percent-encodes all characters except the unreserved characters of RFC 3986
+func encodeForURI(str string) (enc string) {
+	var sb strings.Builder
+	for _, c := range []byte(str) {
+		if isAlpha(rune(c)) || isDigit(rune(c)) || strings.IndexByte("-_.~", c) >= 0 {
+			sb.WriteByte(c)
+		} else {
+			sb.WriteString(fmt.Sprintf("%%%02X", c))
+		}
+	}
+	enc = sb.String()
+	return
+}
+
+// compareStrings evaluates CONTAINS, STRSTARTS, STRENDS, STRBEFORE and STRAFTER
+func compareStrings(name string, a, b rdf.Term) (val rdf.Term, err error) {
+	la, lb, ok := compatibleArgs(a, b)
+	if !ok {
+		err = errType
+		return
+	}
+	str, sub := la.String(), lb.String()
+	switch name {
+	case "CONTAINS":
+		val = boolLiteral(strings.Contains(str, sub))
+	case "STRSTARTS":
+		val = boolLiteral(strings.HasPrefix(str, sub))
+	case "STRENDS":
+		val = boolLiteral(strings.HasSuffix(str, sub))
+	default:
+		i := strings.Index(str, sub)
+		switch {
+		case i < 0:
+			val = simpleLiteral("")
+		case name == "STRBEFORE":
+			val = withLexical(la, str[:i])
+		default:
+			val = withLexical(la, str[i+len(sub):])
+		}
+	}
+	return
+}
+
+// substr evaluates SUBSTR (positions in characters starting at 1)
+func substr(args []rdf.Term) (val rdf.Term, err error) {
+	lit, ok := stringArg(args[0])
+	start, okStart := toNumeric(args[1])
+	if !ok || !okStart {
+		err = errType
+		return
+	}
+	runes := []rune(lit.String())
+	from := math.Floor(start.float() + 0.5)
+	to := math.Inf(1)
+	if len(args) > 2 {
+		length, okLength := toNumeric(args[2])
+		if !okLength {
+			err = errType
+			return
+		}
+		to = from + math.Floor(length.float()+0.5)
+	}
+	var sb strings.Builder
+	for i, r := range runes {
+		if pos := float64(i + 1); pos >= from && pos < to {
+			sb.WriteRune(r)
+		}
+	}
+	val = withLexical(lit, sb.String())
+	return
+}
+
+// regex evaluates REGEX and REPLACE; the flags i, m, s and x are supported
+func (e *evaluator) regex(name string, args []rdf.Term) (val rdf.Term, err error) {
+	lit, ok := stringArg(args[0])
+	pat, okPat := args[1].(rdf.Literal)
+	if !ok || !okPat || pat.Datatype() != rdf.XsdString {
+		err = errType
+		return
+	}
+	var repl rdf.Literal
+	flagArg := 2
+	if name == "REPLACE" {
+		if repl, ok = args[2].(rdf.Literal); !ok || repl.Datatype() != rdf.XsdString {
+			err = errType
+			return
+		}
+		flagArg = 3
+	}
+	flags := ""
+	if len(args) > flagArg {
+		f, okFlags := args[flagArg].(rdf.Literal)
+		if !okFlags || f.Datatype() != rdf.XsdString {
+			err = errType
+			return
+		}
+		flags = f.String()
+	}
+	re, err := e.compileRegexp(pat.String(), flags)
+	if err != nil {
+		return
+	}
+	if name == "REGEX" {
+		val = boolLiteral(re.MatchString(lit.String()))
+		return
+	}
+	// $1 in XPath replacements is ${1} in Go
+	r := regexp.MustCompile(`\$(\d+)`).ReplaceAllString(repl.String(), "$${$1}")
+	val = withLexical(lit, re.ReplaceAllString(lit.String(), r))
+	return
+}
+
+// compileRegexp compiles a regular expression with XPath flags; compiled expressions are cached
+func (e *evaluator) compileRegexp(pat string, flags string) (re *regexp.Regexp, err error) {
+	key := flags + "/" + pat
+	if re = e.regexps[key]; re != nil {
+		return
+	}
+	prefix := ""
+	for _, f := range flags {
+		switch f {
+		case 'i', 'm', 's':
+			prefix += string(f)
+		case 'x':
+			pat = strings.Join(strings.Fields(pat), "")
+		default:
+			err = errors.New("Invalid regular expression flag " + string(f))
+			return
+		}
+	}
+	if prefix != "" {
+		pat = "(?" + prefix + ")" + pat
+	}
+	if re, err = regexp.Compile(pat); err != nil {
+		return
+	}
+	e.regexps[key] = re
+	return
+}
+
+// tzRegexp matches the timezone of a xsd:dateTime
+var tzRegexp = regexp.MustCompile(`(Z|[+-]\d\d:\d\d)$`)
+
+// dateTimePart evaluates YEAR, MONTH, DAY, HOURS, MINUTES, SECONDS, TIMEZONE and TZ
+func dateTimePart(name string, arg rdf.Term) (val rdf.Term, err error) {
+	lit, ok := arg.(rdf.Literal)
+	if !ok || temporalCategory(lit.Datatype()) == "" {
+		err = errType
+		return
+	}
+	t, err := lit.ToTime()
+	if err != nil {
+		err = errType
+		return
+	}
+	integer := func(i int) (lit rdf.Literal) {
+		lit = rdf.NewTypedLiteral(strconv.Itoa(i), rdf.XsdInteger)
+		return
+	}
+	tz := tzRegexp.FindString(strings.TrimSpace(lit.String()))
+	switch name {
+	case "YEAR":
+		val = integer(t.Year())
+	case "MONTH":
+		val = integer(int(t.Month()))
+	case "DAY":
+		val = integer(t.Day())
+	case "HOURS":
+		val = integer(t.Hour())
+	case "MINUTES":
+		val = integer(t.Minute())
+	case "SECONDS":
+		sec := new(big.Rat).SetFrac64(int64(t.Second())*1e9+int64(t.Nanosecond()), 1e9)
+		val = numeric{kind: numDecimal, rat: sec}.literal()
+	case "TIMEZONE":
+		if tz == "" {
+			err = errType
+			return
+		}
+		_, offset := t.Zone()
+		d := rdf.Duration{Seconds: int64(offset)}
+		if offset < 0 {
+			d = rdf.Duration{Negative: true, Seconds: int64(-offset)}
+		}
+		val, err = rdf.NewLiteral(d, rdf.XsdDayTimeDuration)
+	case "TZ":
+		val = simpleLiteral(tz)
+	}
+	return
+}
+
+// cast converts a term to a xsd datatype (e.g. xsd:integer("5"))
+func cast(arg rdf.Term, dt string) (val rdf.Term, err error) {
+	if dt == rdf.XsdString {
+		if arg.Type() == rdf.TermBlankNode {
+			err = errType
+			return
+		}
+		val = rdf.NewTypedLiteral(arg.String(), rdf.XsdString)
+		return
+	}
+	lit, ok := arg.(rdf.Literal)
+	if !ok || lit.Lang() != "" {
+		err = errType
+		return
+	}
+	lexical := strings.TrimSpace(lit.String())
+	if n, isNum := toNumeric(lit); isNum {
+		f := n.float()
+		switch {
+		case dt == rdf.XsdBoolean:
+			lexical = strconv.FormatBool(f != 0 && !math.IsNaN(f))
+		case numericTypes[dt] == numInteger || numericTypes[dt] == numDecimal:
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				err = errType
+				return
+			}
+			r := n.rat
+			if n.kind >= numFloat {
+				r = new(big.Rat).SetFloat64(f)
+			}
+			if numericTypes[dt] == numInteger {
+				lexical = new(big.Int).Quo(r.Num(), r.Denom()).String()
+			} else {
+				lexical = r.FloatString(20)
+			}
+		case numericTypes[dt] >= numFloat:
+			lexical = strconv.FormatFloat(f, 'E', -1, 64)
+		}
+	} else if lit.Datatype() == rdf.XsdBoolean && numericTypes[dt] != numNone {
+		lexical = "0"
+		if v, e := lit.Parse(); e == nil && v.(bool) {
+			lexical = "1"
+		}
+	} else if lit.Datatype() != rdf.XsdString && lit.Datatype() != dt &&
+		!(dt == rdf.XsdBoolean || numericTypes[dt] != numNone) {
+		err = errType
+		return
+	}
+	res := rdf.NewTypedLiteral(lexical, dt)
+	if res.Validate() != nil {
+		err = errType
+		return
+	}
+	val, _ = res.Canonical()
+	return
+}
@@ -0,0 +1,489 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sparql
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// tokenKind is the kind of a token of a query
+type tokenKind int
+
+// possible token kinds
+const (
+	tokEOF     tokenKind = iota
+	tokIRI               // iri reference (value without brackets and escapes)
+	tokPName             // prefixed name (value as written without escapes in the local name)
+	tokBlank             // blank node label (value without "_:")
+	tokVar               // variable (value without '?' or '$')
+	tokString            // string (value without quotes and escapes)
+	tokLangTag           // language tag (value without '@')
+	tokInteger           // integer
+	tokDecimal           // decimal
+	tokDouble            // double
+	tokWord              // keyword or function name
+	tokPunct             // punctuation or operator
+)
+
+// token is a token of a query
+type token struct {
+	kind tokenKind
+	val  string
+	pos  int // position of the first rune of the token
+}
+
+// lexer splits a query into tokens
+type lexer struct {
+	runes []rune
+	pos   int
+	lines []int // positions of the first runes of all lines
+}
+
+// punctuations are the operators and punctuation characters ordered by length
+var punctuations = []string{"^^", "&&", "||", "!=", "<=", ">=", "{", "}", "(", ")", "[", "]", ".",
+	",", ";", "*", "/", "|", "^", "+", "-", "!", "=", "<", ">", "?"}
+
+// tokenize splits a query into tokens; the last token is tokEOF
+func tokenize(query string) (tok []token, lines []int, err error) {
+	l := &lexer{runes: []rune(query), lines: []int{0}}
+	for i, r := range l.runes {
+		if r == '\n' {
+			l.lines = append(l.lines, i+1)
+		}
+	}
+	lines = l.lines
+	for {
+		l.skipWS()
+		if l.pos >= len(l.runes) {
+			tok = append(tok, token{kind: tokEOF, pos: l.pos})
+			return
+		}
+		var t token
+		t, err = l.next()
+		if err != nil {
+			return
+		}
+		tok = append(tok, t)
+	}
+}
+
+// skipWS skips whitespaces and comments
+func (l *lexer) skipWS() {
+	for l.pos < len(l.runes) {
+		r := l.runes[l.pos]
+		if r == '#' {
+			for l.pos < len(l.runes) && l.runes[l.pos] != '\n' {
+				l.pos++
+			}
+		} else if r == ' ' || r == '\t' || r == '\r' || r == '\n' {
+			l.pos++
+		} else {
+			return
+		}
+	}
+}
+
+// peek returns the rune at the offset from the current position (0 at the end of the query)
+func (l *lexer) peek(offset int) (r rune) {
+	if l.pos+offset < len(l.runes) {
+		r = l.runes[l.pos+offset]
+	}
+	return
+}
+
+// next reads the token at the current position
+func (l *lexer) next() (t token, err error) {
+	t.pos = l.pos
+	r := l.runes[l.pos]
+	switch {
+	case r == '<':
+		if val, ok := l.scanIRI(); ok {
+			t.kind, t.val = tokIRI, val
+			return
+		}
+	case (r == '?' || r == '$') && isVarChar(l.peek(1), true):
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.runes) && isVarChar(l.runes[l.pos], false) {
+			l.pos++
+		}
+		t.kind, t.val = tokVar, string(l.runes[start:l.pos])
+		return
+	case r == '"' || r == '\'':
+		t.kind = tokString
+		t.val, err = l.scanString()
+		return
+	case r == '@' && isAlpha(l.peek(1)):
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.runes) && (isAlpha(l.runes[l.pos]) || l.runes[l.pos] == '-' ||
+			(l.pos > start && isDigit(l.runes[l.pos]))) {
+			l.pos++
+		}
+		t.kind, t.val = tokLangTag, string(l.runes[start:l.pos])
+		return
+	case isDigit(r) || (r == '.' && isDigit(l.peek(1))):
+		t.kind, t.val = l.scanNumber()
+		return
+	case r == '_' && l.peek(1) == ':':
+		l.pos += 2
+		start := l.pos
+		if !isPNCharsU(l.peek(0)) && !isDigit(l.peek(0)) {
+			err = l.syntaxError(t.pos, "", "invalid blank node label")
+			return
+		}
+		l.scanName()
+		t.kind, t.val = tokBlank, string(l.runes[start:l.pos])
+		return
+	case r == ':' || isPNCharsBase(r):
+		start := l.pos
+		if r != ':' {
+			l.scanName()
+		}
+		if l.peek(0) != ':' {
+			if strings.ContainsRune(string(l.runes[start:l.pos]), '.') ||
+				strings.ContainsRune(string(l.runes[start:l.pos]), '-') {
+				err = l.syntaxError(start, "", "invalid keyword "+string(l.runes[start:l.pos]))
+				return
+			}
+			t.kind, t.val = tokWord, string(l.runes[start:l.pos])
+			return
+		}
+		prefix := string(l.runes[start:l.pos])
+		l.pos++
+		var local string
+		local, err = l.scanLocalName()
+		t.kind, t.val = tokPName, prefix+":"+local
+		return
+	}
+	for _, punct := range punctuations {
+		if l.hasPrefix(punct) {
+			l.pos += len(punct)
+			t.kind, t.val = tokPunct, punct
+			return
+		}
+	}
+	err = l.syntaxError(l.pos, "", "unexpected character "+strconv.QuoteRune(r))
+	return
+}
+
+// hasPrefix checks if the runes at the current position start with str
+func (l *lexer) hasPrefix(str string) (ok bool) {
+	for i, r := range []rune(str) {
+		if l.peek(i) != r {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// scanIRI reads an iri reference; ok is false if the runes at the current position are no iri
+// reference (e.g. the operator '<')
+func (l *lexer) scanIRI() (val string, ok bool) {
+	var b strings.Builder
+	i := l.pos + 1
+	for i < len(l.runes) {
+		r := l.runes[i]
+		switch {
+		case r == '>':
+			l.pos = i + 1
+			val, ok = b.String(), true
+			return
+		case r == '\\':
+			var n int
+			r, n = parseUChar(l.runes[i:])
+			if n == 0 {
+				return
+			}
+			b.WriteRune(r)
+			i += n
+			continue
+		case r <= 0x20 || strings.ContainsRune("<\"{}|^`", r):
+			return
+		}
+		b.WriteRune(r)
+		i++
+	}
+	return
+}
+
+// parseUChar parses an escape sequence \uXXXX or \UXXXXXXXX at the beginning of the runes; n is
+// the number of consumed runes (0 if there is no valid escape sequence)
+func parseUChar(runes []rune) (r rune, n int) {
+	if len(runes) < 2 || runes[0] != '\\' {
+		return
+	}
+	length := 0
+	switch runes[1] {
+	case 'u':
+		length = 4
+	case 'U':
+		length = 8
+	default:
+		return
+	}
+	if len(runes) < 2+length {
+		return
+	}
+	v, err := strconv.ParseUint(string(runes[2:2+length]), 16, 32)
+	if err != nil {
+		return
+	}
+	r, n = rune(v), 2+length
+	return
+}
+
+// scanString reads a string in single, double or triple quotes
+func (l *lexer) scanString() (val string, err error) {
+	start := l.pos
+	quote := l.runes[l.pos]
+	long := l.peek(1) == quote && l.peek(2) == quote
+	if long {
+		l.pos += 3
+	} else {
+		l.pos++
+	}
+	var b strings.Builder
+	for {
+		if l.pos >= len(l.runes) {
+			err = l.syntaxError(start, string(quote), "unterminated string")
+			return
+		}
+		r := l.runes[l.pos]
+		switch {
+		case r == quote && (!long || (l.peek(1) == quote && l.peek(2) == quote)):
+			if long {
+				// additional quotes belong to the string
+				for l.peek(3) == quote {
+					b.WriteRune(quote)
+					l.pos++
+				}
+				l.pos += 3
+			} else {
+				l.pos++
+			}
+			val = b.String()
+			return
+		case r == '\\':
+			if u, n := parseUChar(l.runes[l.pos:]); n > 0 {
+				b.WriteRune(u)
+				l.pos += n
+				continue
+			}
+			esc := map[rune]string{'t': "\t", 'b': "\b", 'n': "\n", 'r': "\r", 'f': "\f",
+				'"': "\"", '\'': "'", '\\': "\\"}
+			str, ok := esc[l.peek(1)]
+			if !ok {
+				err = l.syntaxError(l.pos, "", "invalid escape sequence")
+				return
+			}
+			b.WriteString(str)
+			l.pos += 2
+			continue
+		case !long && (r == '\n' || r == '\r'):
+			err = l.syntaxError(l.pos, string(quote), "line break in string")
+			return
+		}
+		b.WriteRune(r)
+		l.pos++
+	}
+}
+
+// scanNumber reads an integer, decimal or double without sign
+func (l *lexer) scanNumber() (kind tokenKind, val string) {
+	start := l.pos
+	kind = tokInteger
+	for isDigit(l.peek(0)) {
+		l.pos++
+	}
+	if l.peek(0) == '.' && isDigit(l.peek(1)) {
+		kind = tokDecimal
+		l.pos++
+		for isDigit(l.peek(0)) {
+			l.pos++
+		}
+	}
+	if r := l.peek(0); r == 'e' || r == 'E' {
+		exp := 1
+		if s := l.peek(1); s == '+' || s == '-' {
+			exp = 2
+		}
+		if isDigit(l.peek(exp)) {
+			kind = tokDouble
+			l.pos += exp
+			for isDigit(l.peek(0)) {
+				l.pos++
+			}
+		}
+	}
+	val = string(l.runes[start:l.pos])
+	return
+}
+
+// scanName reads the characters of a prefix or blank node label (a trailing '.' is no part of
+// the name)
+func (l *lexer) scanName() {
+	l.pos++
+	for l.pos < len(l.runes) && (isPNChars(l.runes[l.pos]) || l.runes[l.pos] == '.') {
+		l.pos++
+	}
+	for l.runes[l.pos-1] == '.' {
+		l.pos--
+	}
+}
+
+// scanLocalName reads the local part of a prefixed name and removes escapes
+func (l *lexer) scanLocalName() (local string, err error) {
+	var b strings.Builder
+	for first := true; l.pos < len(l.runes); first = false {
+		r := l.runes[l.pos]
+		switch {
+		case r == '%':
+			if !isHex(l.peek(1)) || !isHex(l.peek(2)) {
+				err = l.syntaxError(l.pos, "", "invalid percent encoding")
+				return
+			}
+			b.WriteString(string(l.runes[l.pos : l.pos+3]))
+			l.pos += 3
+			continue
+		case r == '\\':
+			next := l.peek(1)
+			if !strings.ContainsRune("_~.-!$&'()*+,;=/?#@%", next) || next == 0 {
+				err = l.syntaxError(l.pos, "", "invalid escape sequence in local name")
+				return
+			}
+			b.WriteRune(next)
+			l.pos += 2
+			continue
+		case r == '.':
+			// a dot must be followed by further characters of the name
+			i := l.pos
+			for i < len(l.runes) && l.runes[i] == '.' {
+				i++
+			}
+			if first || i >= len(l.runes) || !(isPNChars(l.runes[i]) || l.runes[i] == ':' ||
+				l.runes[i] == '%' || l.runes[i] == '\\') {
+				local = b.String()
+				return
+			}
+		case r == ':' || isPNCharsU(r) || isDigit(r) || (!first && isPNChars(r)):
+		default:
+			local = b.String()
+			return
+		}
+		b.WriteRune(r)
+		l.pos++
+	}
+	local = b.String()
+	return
+}
+
+// syntaxError creates a syntax error at the specified position
+func (l *lexer) syntaxError(pos int, expected string, msg string) (err error) {
+	err = newSyntaxError(l.runes, l.lines, pos, expected, msg)
+	return
+}
+
+// newSyntaxError creates a syntax error at the specified position of the runes
+func newSyntaxError(runes []rune, lines []int, pos int, expected string, msg string) (err error) {
+	i := sort.Search(len(lines), func(i int) bool { return lines[i] > pos }) - 1
+	end := len(runes)
+	if i+1 < len(lines) {
+		end = lines[i+1]
+	}
+	err = &rdf.SyntaxError{Line: i + 1, Column: pos - lines[i] + 1, Expected: expected, Msg: msg,
+		Snippet: strings.TrimSpace(string(runes[lines[i]:end]))}
+	return
+}
+
+// isVarChar checks if r is allowed in variable names
+func isVarChar(r rune, first bool) (ok bool) {
+	ok = isPNCharsU(r) || isDigit(r) || (!first && (r == 0xB7 || (r >= 0x300 && r <= 0x36F) ||
+		(r >= 0x203F && r <= 0x2040)))
+	return
+}
+
+// isPNCharsBase checks if r is a character that may start a prefix
+func isPNCharsBase(r rune) (ok bool) {
+	ok = isAlpha(r) || (r >= 0xC0 && r != 0xD7 && r != 0xF7 && unicode.IsLetter(r))
+	return
+}
+
+// isPNCharsU checks if r is a character that may start a local name or blank node label
+func isPNCharsU(r rune) (ok bool) {
+	ok = r == '_' || isPNCharsBase(r)
+	return
+}
+
+// isPNChars checks if r is allowed inside of prefixes, local names and blank node labels
+func isPNChars(r rune) (ok bool) {
+	ok = isPNCharsU(r) || isDigit(r) || r == '-' || r == 0xB7 || (r >= 0x300 && r <= 0x36F) ||
+		(r >= 0x203F && r <= 0x2040)
+	return
+}
+
+// isAlpha checks if r is an ascii letter
+func isAlpha(r rune) (ok bool) {
+	ok = (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+	return
+}
+
+// isDigit checks if r is an ascii digit
+func isDigit(r rune) (ok bool) {
+	ok = r >= '0' && r <= '9'
+	return
+}
+
+// isHex checks if r is a hexadecimal digit
+func isHex(r rune) (ok bool) {
+	ok = isDigit(r) || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+	return
+}
@@ -0,0 +1,1420 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sparql
+
+import (
+	"strconv"
+	"strings"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// rdfNS is the namespace of the rdf vocabulary
+const rdfNS = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+
+// aggregates are the names of the aggregate functions
+var aggregates = map[string]bool{"COUNT": true, "SUM": true, "MIN": true, "MAX": true, "AVG": true,
+	"SAMPLE": true, "GROUP_CONCAT": true}
+
+// parser parses queries
+type parser struct {
+	tok       []token
+	i         int               // index of the current token
+	runes     []rune            // runes of the query (for error messages)
+	lines     []int             // positions of the first runes of all lines
+	query     *Query            // (sub)query that is parsed
+	prefixes  map[string]string // declared prefixes
+	base      string            // base iri
+	numBlank  int               // number of anonymous blank nodes
+	template  bool              // blank nodes are terms instead of variables (construct template)
+	aggsValid bool              // aggregates are allowed in the current expression
+}
+
+// Parse parses a SPARQL query (SELECT, CONSTRUCT, ASK or DESCRIBE)
+func Parse(query string) (q *Query, err error) {
+	tok, lines, err := tokenize(query)
+	if err != nil {
+		return
+	}
+	p := &parser{tok: tok, runes: []rune(query), lines: lines, prefixes: make(map[string]string)}
+	q, err = p.parseQuery()
+	if err != nil {
+		q = nil
+	}
+	return
+}
+
+// peek returns the current token
+func (p *parser) peek() (t token) {
+	t = p.tok[p.i]
+	return
+}
+
+// next returns the current token and moves to the next one
+func (p *parser) next() (t token) {
+	t = p.tok[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return
+}
+
+// isWord checks if the current token is the keyword (case-insensitive)
+func (p *parser) isWord(word string) (ok bool) {
+	t := p.tok[p.i]
+	ok = t.kind == tokWord && strings.EqualFold(t.val, word)
+	return
+}
+
+// isPunct checks if the current token is the punctuation
+func (p *parser) isPunct(punct string) (ok bool) {
+	t := p.tok[p.i]
+	ok = t.kind == tokPunct && t.val == punct
+	return
+}
+
+// expectWord consumes the keyword
+func (p *parser) expectWord(word string) (err error) {
+	if !p.isWord(word) {
+		err = p.unexpected(word)
+		return
+	}
+	p.next()
+	return
+}
+
+// expectPunct consumes the punctuation
+func (p *parser) expectPunct(punct string) (err error) {
+	if !p.isPunct(punct) {
+		err = p.unexpected(punct)
+		return
+	}
+	p.next()
+	return
+}
+
+// syntaxError creates a syntax error at the current token
+func (p *parser) syntaxError(expected string, msg string) (err error) {
+	err = newSyntaxError(p.runes, p.lines, p.tok[p.i].pos, expected, msg)
+	return
+}
+
+// unexpected creates a syntax error for an unexpected token
+func (p *parser) unexpected(expected string) (err error) {
+	t := p.peek()
+	if t.kind == tokEOF {
+		err = p.syntaxError(expected, "unexpected end of query")
+		return
+	}
+	end := len(p.runes)
+	if p.i+1 < len(p.tok) {
+		end = p.tok[p.i+1].pos
+	}
+	err = p.syntaxError(expected, "unexpected "+strconv.Quote(strings.TrimSpace(
+		string(p.runes[t.pos:end]))))
+	return
+}
+
+// parseQuery parses the prologue, the query and trailing values
+func (p *parser) parseQuery() (q *Query, err error) {
+	if err = p.parsePrologue(); err != nil {
+		return
+	}
+	q = &Query{limit: -1}
+	p.query = q
+	switch {
+	case p.isWord("SELECT"):
+		err = p.parseSelect(q)
+	case p.isWord("CONSTRUCT"):
+		err = p.parseConstruct(q)
+	case p.isWord("ASK"):
+		q.Form = FormAsk
+		p.next()
+		if err = p.parseDatasetClause(); err != nil {
+			return
+		}
+		if q.where, err = p.parseWhereClause(); err != nil {
+			return
+		}
+		err = p.parseSolutionModifier(q)
+	case p.isWord("DESCRIBE"):
+		err = p.parseDescribe(q)
+	default:
+		err = p.unexpected("SELECT")
+	}
+	if err != nil {
+		return
+	}
+	if p.isWord("VALUES") {
+		if q.values, err = p.parseDataBlock(); err != nil {
+			return
+		}
+	}
+	if p.peek().kind != tokEOF {
+		err = p.unexpected("")
+		return
+	}
+	q.Prefixes, q.Base = p.prefixes, p.base
+	return
+}
+
+// parsePrologue parses BASE and PREFIX declarations
+func (p *parser) parsePrologue() (err error) {
+	for {
+		switch {
+		case p.isWord("BASE"):
+			p.next()
+			if p.peek().kind != tokIRI {
+				err = p.unexpected("<")
+				return
+			}
+			p.base = rdf.ResolveIRI(p.base, p.next().val)
+		case p.isWord("PREFIX"):
+			p.next()
+			t := p.peek()
+			if t.kind != tokPName || !strings.HasSuffix(t.val, ":") ||
+				strings.Count(t.val, ":") > 1 {
+				err = p.unexpected("prefix:")
+				return
+			}
+			p.next()
+			if p.peek().kind != tokIRI {
+				err = p.unexpected("<")
+				return
+			}
+			p.prefixes[strings.TrimSuffix(t.val, ":")] = rdf.ResolveIRI(p.base, p.next().val)
+		default:
+			return
+		}
+	}
+}
+
+// parseSelect parses a select query or subquery
+func (p *parser) parseSelect(q *Query) (err error) {
+	q.Form = FormSelect
+	p.next()
+	if p.isWord("DISTINCT") {
+		q.distinct = true
+		p.next()
+	} else if p.isWord("REDUCED") {
+		q.reduced = true
+		p.next()
+	}
+	if p.isPunct("*") {
+		q.star = true
+		p.next()
+	} else {
+		for {
+			if t := p.peek(); t.kind == tokVar {
+				q.project = append(q.project, projection{name: t.val})
+				p.next()
+			} else if p.isPunct("(") {
+				p.next()
+				var proj projection
+				if proj.expr, err = p.parseAggExpression(); err != nil {
+					return
+				}
+				if proj.name, err = p.parseAs(); err != nil {
+					return
+				}
+				if err = p.expectPunct(")"); err != nil {
+					return
+				}
+				q.project = append(q.project, proj)
+			} else {
+				break
+			}
+		}
+		if len(q.project) == 0 {
+			err = p.unexpected("*")
+			return
+		}
+	}
+	if err = p.parseDatasetClause(); err != nil {
+		return
+	}
+	if q.where, err = p.parseWhereClause(); err != nil {
+		return
+	}
+	if err = p.parseSolutionModifier(q); err != nil {
+		return
+	}
+	err = p.setVars(q)
+	return
+}
+
+// parseAs parses "AS ?var"
+func (p *parser) parseAs() (name string, err error) {
+	if err = p.expectWord("AS"); err != nil {
+		return
+	}
+	if p.peek().kind != tokVar {
+		err = p.unexpected("?")
+		return
+	}
+	name = p.next().val
+	return
+}
+
+// setVars determines the variables of the solutions of a select query and checks the projection
+func (p *parser) setVars(q *Query) (err error) {
+	grouped := len(q.groupBy) > 0 || len(q.aggs) > 0
+	if q.star {
+		if grouped {
+			err = p.syntaxError("", "SELECT * is not allowed in grouped queries")
+			return
+		}
+		q.where.collectVars(&q.Vars, make(map[string]bool))
+		return
+	}
+	inScope := make(map[string]bool)
+	var vars []string
+	q.where.collectVars(&vars, inScope)
+	groupVars := make(map[string]bool)
+	for _, cond := range q.groupBy {
+		groupVars[cond.name] = true
+	}
+	seen := make(map[string]bool)
+	for _, proj := range q.project {
+		if seen[proj.name] || (proj.expr != nil && inScope[proj.name]) {
+			err = p.syntaxError("", "variable ?"+proj.name+" is already in scope")
+			return
+		}
+		if grouped && proj.expr == nil && !groupVars[proj.name] {
+			err = p.syntaxError("", "variable ?"+proj.name+" is not grouped")
+			return
+		}
+		seen[proj.name] = true
+		q.Vars = append(q.Vars, proj.name)
+	}
+	return
+}
+
+// parseDatasetClause rejects FROM clauses since queries are evaluated over a single graph
+func (p *parser) parseDatasetClause() (err error) {
+	if p.isWord("FROM") {
+		err = p.syntaxError("", "FROM is not supported (queries are evaluated over a single graph)")
+	}
+	return
+}
+
+// parseWhereClause parses a where clause (the keyword WHERE is optional)
+func (p *parser) parseWhereClause() (pat pattern, err error) {
+	if p.isWord("WHERE") {
+		p.next()
+	}
+	pat, err = p.parseGroupGraphPattern()
+	return
+}
+
+// parseSolutionModifier parses GROUP BY, HAVING, ORDER BY, LIMIT and OFFSET
+func (p *parser) parseSolutionModifier(q *Query) (err error) {
+	if p.isWord("GROUP") {
+		p.next()
+		if err = p.expectWord("BY"); err != nil {
+			return
+		}
+		for len(q.groupBy) == 0 || p.startsCondition() {
+			var cond projection
+			if t := p.peek(); t.kind == tokVar {
+				p.next()
+				cond = projection{name: t.val, expr: &varExpr{name: t.val}}
+			} else if p.isPunct("(") {
+				p.next()
+				if cond.expr, err = p.parseExpression(); err != nil {
+					return
+				}
+				if p.isWord("AS") {
+					if cond.name, err = p.parseAs(); err != nil {
+						return
+					}
+				}
+				if err = p.expectPunct(")"); err != nil {
+					return
+				}
+			} else if cond.expr, err = p.parseConstraint(); err != nil {
+				return
+			}
+			q.groupBy = append(q.groupBy, cond)
+		}
+	}
+	if p.isWord("HAVING") {
+		p.next()
+		for len(q.having) == 0 || p.startsCondition() {
+			p.aggsValid = true
+			ex, e := p.parseConstraint()
+			p.aggsValid = false
+			if err = e; err != nil {
+				return
+			}
+			q.having = append(q.having, ex)
+		}
+	}
+	if p.isWord("ORDER") {
+		p.next()
+		if err = p.expectWord("BY"); err != nil {
+			return
+		}
+		for len(q.orderBy) == 0 || p.startsCondition() || p.isWord("ASC") || p.isWord("DESC") {
+			var cond orderCond
+			p.aggsValid = true
+			if p.isWord("ASC") || p.isWord("DESC") {
+				cond.desc = p.isWord("DESC")
+				p.next()
+				if err = p.expectPunct("("); err == nil {
+					if cond.expr, err = p.parseExpression(); err == nil {
+						err = p.expectPunct(")")
+					}
+				}
+			} else if t := p.peek(); t.kind == tokVar {
+				p.next()
+				cond.expr = &varExpr{name: t.val}
+			} else {
+				cond.expr, err = p.parseConstraint()
+			}
+			p.aggsValid = false
+			if err != nil {
+				return
+			}
+			q.orderBy = append(q.orderBy, cond)
+		}
+	}
+	for p.isWord("LIMIT") || p.isWord("OFFSET") {
+		limit := p.isWord("LIMIT")
+		p.next()
+		t := p.peek()
+		if t.kind != tokInteger {
+			err = p.unexpected("integer")
+			return
+		}
+		p.next()
+		n, e := strconv.Atoi(t.val)
+		if e != nil {
+			err = p.syntaxError("", "invalid number "+t.val)
+			return
+		}
+		if limit {
+			q.limit = n
+		} else {
+			q.offset = n
+		}
+	}
+	return
+}
+
+// startsCondition checks if the current token starts a group, having or order condition
+func (p *parser) startsCondition() (ok bool) {
+	t := p.peek()
+	switch t.kind {
+	case tokVar, tokIRI, tokPName:
+		ok = true
+	case tokPunct:
+		ok = t.val == "("
+	case tokWord:
+		name := strings.ToUpper(t.val)
+		_, ok = builtins[name]
+		ok = ok || aggregates[name] || name == "EXISTS" || name == "NOT"
+	}
+	return
+}
+
+// parseConstraint parses a bracketted expression or a function call
+func (p *parser) parseConstraint() (ex expr, err error) {
+	t := p.peek()
+	switch {
+	case p.isPunct("("):
+		p.next()
+		if ex, err = p.parseExpression(); err == nil {
+			err = p.expectPunct(")")
+		}
+	case t.kind == tokWord || t.kind == tokIRI || t.kind == tokPName:
+		ex, err = p.parsePrimary()
+		if _, ok := ex.(*constExpr); ok && err == nil {
+			err = p.unexpected("(")
+		}
+	default:
+		err = p.unexpected("(")
+	}
+	return
+}
+
+// parseConstruct parses a construct query
+func (p *parser) parseConstruct(q *Query) (err error) {
+	q.Form = FormConstruct
+	p.next()
+	if p.isPunct("{") {
+		p.next()
+		p.template = true
+		q.template, err = p.parseTriplesTemplate()
+		p.template = false
+		if err != nil {
+			return
+		}
+		if err = p.expectPunct("}"); err != nil {
+			return
+		}
+		if err = p.parseDatasetClause(); err != nil {
+			return
+		}
+		if q.where, err = p.parseWhereClause(); err != nil {
+			return
+		}
+	} else {
+		// short form: the template is the basic graph pattern of the where clause
+		if err = p.parseDatasetClause(); err != nil {
+			return
+		}
+		if err = p.expectWord("WHERE"); err != nil {
+			return
+		}
+		if err = p.expectPunct("{"); err != nil {
+			return
+		}
+		if q.template, err = p.parseTriplesTemplate(); err != nil {
+			return
+		}
+		for _, t := range q.template {
+			if t.path != nil || strings.HasPrefix(t.s.name, "_:") ||
+				strings.HasPrefix(t.o.name, "_:") {
+				err = p.syntaxError("", "blank nodes and property paths are not allowed in the "+
+					"short form of construct queries")
+				return
+			}
+		}
+		if err = p.expectPunct("}"); err != nil {
+			return
+		}
+		q.where = &bgp{triples: q.template}
+	}
+	err = p.parseSolutionModifier(q)
+	return
+}
+
+// parseTriplesTemplate parses triples separated by '.' up to a '}'
+func (p *parser) parseTriplesTemplate() (trip []triple, err error) {
+	for p.startsTriples() {
+		if err = p.parseTriplesSameSubject(&trip); err != nil {
+			return
+		}
+		if !p.isPunct(".") {
+			return
+		}
+		p.next()
+	}
+	return
+}
+
+// parseDescribe parses a describe query
+func (p *parser) parseDescribe(q *Query) (err error) {
+	q.Form = FormDescribe
+	p.next()
+	if p.isPunct("*") {
+		q.star = true
+		p.next()
+	} else {
+		for {
+			t := p.peek()
+			if t.kind == tokVar {
+				p.next()
+				q.describe = append(q.describe, node{name: t.val})
+			} else if t.kind == tokIRI || t.kind == tokPName {
+				var iri rdf.IRI
+				if iri, err = p.parseIRI(); err != nil {
+					return
+				}
+				q.describe = append(q.describe, node{term: iri})
+			} else {
+				break
+			}
+		}
+		if len(q.describe) == 0 {
+			err = p.unexpected("*")
+			return
+		}
+	}
+	if err = p.parseDatasetClause(); err != nil {
+		return
+	}
+	if p.isWord("WHERE") || p.isPunct("{") {
+		if q.where, err = p.parseWhereClause(); err != nil {
+			return
+		}
+	} else {
+		q.where = &bgp{}
+	}
+	if err = p.parseSolutionModifier(q); err != nil {
+		return
+	}
+	if q.star {
+		var vars []string
+		q.where.collectVars(&vars, make(map[string]bool))
+		for _, name := range vars {
+			q.describe = append(q.describe, node{name: name})
+		}
+	}
+	return
+}
+
+// parseGroupGraphPattern parses a group graph pattern or a subquery in curly brackets
+func (p *parser) parseGroupGraphPattern() (pat pattern, err error) {
+	if err = p.expectPunct("{"); err != nil {
+		return
+	}
+	if p.isWord("SELECT") {
+		sub := &Query{limit: -1}
+		outer := p.query
+		p.query = sub
+		err = p.parseSelect(sub)
+		p.query = outer
+		if err != nil {
+			return
+		}
+		if p.isWord("VALUES") {
+			if sub.values, err = p.parseDataBlock(); err != nil {
+				return
+			}
+		}
+		pat = &subQuery{query: sub}
+		err = p.expectPunct("}")
+		return
+	}
+	var g pattern
+	var filters []expr
+	for !p.isPunct("}") {
+		switch {
+		case p.isWord("OPTIONAL"):
+			p.next()
+			var opt pattern
+			if opt, err = p.parseGroupGraphPattern(); err != nil {
+				return
+			}
+			if f, ok := opt.(*filter); ok {
+				g = &leftJoin{left: emptyGroup(g), right: f.inner, cond: f.cond}
+			} else {
+				g = &leftJoin{left: emptyGroup(g), right: opt}
+			}
+		case p.isWord("MINUS"):
+			p.next()
+			var right pattern
+			if right, err = p.parseGroupGraphPattern(); err != nil {
+				return
+			}
+			g = &minus{left: emptyGroup(g), right: right}
+		case p.isWord("FILTER"):
+			p.next()
+			var cond expr
+			if cond, err = p.parseConstraint(); err != nil {
+				return
+			}
+			filters = append(filters, cond)
+		case p.isWord("BIND"):
+			p.next()
+			ext := &extend{inner: emptyGroup(g)}
+			if err = p.expectPunct("("); err != nil {
+				return
+			}
+			if ext.expr, err = p.parseExpression(); err != nil {
+				return
+			}
+			if ext.name, err = p.parseAs(); err != nil {
+				return
+			}
+			if err = p.expectPunct(")"); err != nil {
+				return
+			}
+			var vars []string
+			seen := make(map[string]bool)
+			ext.inner.collectVars(&vars, seen)
+			if seen[ext.name] {
+				err = p.syntaxError("", "variable ?"+ext.name+" is already in scope")
+				return
+			}
+			g = ext
+		case p.isWord("VALUES"):
+			var vals *valuesBlock
+			if vals, err = p.parseDataBlock(); err != nil {
+				return
+			}
+			g = joinPatterns(g, vals)
+		case p.isWord("GRAPH") || p.isWord("SERVICE"):
+			err = p.syntaxError("", strings.ToUpper(p.peek().val)+" is not supported")
+			return
+		case p.isPunct("{"):
+			var sub pattern
+			if sub, err = p.parseGroupGraphPattern(); err != nil {
+				return
+			}
+			for p.isWord("UNION") {
+				p.next()
+				var right pattern
+				if right, err = p.parseGroupGraphPattern(); err != nil {
+					return
+				}
+				sub = &union{left: sub, right: right}
+			}
+			g = joinPatterns(g, sub)
+		case p.startsTriples():
+			var trip []triple
+			if trip, err = p.parseTriplesTemplate(); err != nil {
+				return
+			}
+			g = joinPatterns(g, &bgp{triples: trip})
+			continue
+		default:
+			err = p.unexpected("}")
+			return
+		}
+		if p.isPunct(".") {
+			p.next()
+		}
+	}
+	p.next()
+	pat = emptyGroup(g)
+	if len(filters) > 0 {
+		pat = &filter{cond: filters, inner: pat}
+	}
+	return
+}
+
+// emptyGroup returns the pattern or an empty basic graph pattern if it is nil
+func emptyGroup(pat pattern) (ret pattern) {
+	ret = pat
+	if ret == nil {
+		ret = &bgp{}
+	}
+	return
+}
+
+// joinPatterns joins two patterns; adjacent basic graph patterns are merged
+func joinPatterns(left, right pattern) (pat pattern) {
+	if left == nil {
+		pat = right
+		return
+	}
+	l, okL := left.(*bgp)
+	r, okR := right.(*bgp)
+	if okL && okR {
+		pat = &bgp{triples: append(append([]triple{}, l.triples...), r.triples...)}
+		return
+	}
+	pat = &join{left: left, right: right}
+	return
+}
+
+// parseDataBlock parses inline data (VALUES)
+func (p *parser) parseDataBlock() (vals *valuesBlock, err error) {
+	p.next()
+	vals = &valuesBlock{}
+	single := false
+	if t := p.peek(); t.kind == tokVar {
+		single = true
+		vals.vars = []string{t.val}
+		p.next()
+	} else {
+		if err = p.expectPunct("("); err != nil {
+			return
+		}
+		for p.peek().kind == tokVar {
+			vals.vars = append(vals.vars, p.next().val)
+		}
+		if err = p.expectPunct(")"); err != nil {
+			return
+		}
+	}
+	if err = p.expectPunct("{"); err != nil {
+		return
+	}
+	for !p.isPunct("}") {
+		if !single {
+			if err = p.expectPunct("("); err != nil {
+				return
+			}
+		}
+		var row []rdf.Term
+		for (single && len(row) == 0) || (!single && !p.isPunct(")")) {
+			if p.isWord("UNDEF") {
+				p.next()
+				row = append(row, nil)
+				continue
+			}
+			var n node
+			if n, err = p.parseVarOrTerm(); err != nil {
+				return
+			}
+			if n.term == nil || n.term.Type() == rdf.TermBlankNode {
+				err = p.syntaxError("", "variables and blank nodes are not allowed in values")
+				return
+			}
+			row = append(row, n.term)
+		}
+		if !single {
+			p.next()
+		}
+		if len(row) != len(vals.vars) {
+			err = p.syntaxError("", "number of values does not match the number of variables")
+			return
+		}
+		vals.rows = append(vals.rows, row)
+	}
+	p.next()
+	return
+}
+
+// startsTriples checks if the current token starts a triple pattern
+func (p *parser) startsTriples() (ok bool) {
+	t := p.peek()
+	switch t.kind {
+	case tokVar, tokIRI, tokPName, tokBlank, tokString, tokInteger, tokDecimal, tokDouble:
+		ok = true
+	case tokWord:
+		ok = strings.EqualFold(t.val, "true") || strings.EqualFold(t.val, "false")
+	case tokPunct:
+		ok = t.val == "[" || t.val == "(" || t.val == "+" || t.val == "-"
+	}
+	return
+}
+
+// startsVerb checks if the current token starts a predicate or property path
+func (p *parser) startsVerb() (ok bool) {
+	t := p.peek()
+	switch t.kind {
+	case tokVar, tokIRI, tokPName:
+		ok = true
+	case tokWord:
+		ok = t.val == "a"
+	case tokPunct:
+		ok = t.val == "^" || t.val == "!" || t.val == "("
+	}
+	return
+}
+
+// parseTriplesSameSubject parses a subject and its property list
+func (p *parser) parseTriplesSameSubject(trip *[]triple) (err error) {
+	var subj node
+	if p.isPunct("[") || p.isPunct("(") {
+		if subj, err = p.parseTriplesNode(trip); err != nil {
+			return
+		}
+		if !p.startsVerb() {
+			return
+		}
+	} else if subj, err = p.parseVarOrTerm(); err != nil {
+		return
+	}
+	err = p.parsePropertyList(subj, trip)
+	return
+}
+
+// parsePropertyList parses predicates (or property paths) and their objects
+func (p *parser) parsePropertyList(subj node, trip *[]triple) (err error) {
+	for {
+		var pred node
+		var pa path
+		inverse := false
+		if t := p.peek(); t.kind == tokVar {
+			p.next()
+			pred = node{name: t.val}
+		} else {
+			if pa, err = p.parsePath(); err != nil {
+				return
+			}
+			switch t := pa.(type) {
+			case *pathLink:
+				pred, pa = node{term: t.iri}, nil
+			case *pathInv:
+				if link, ok := t.p.(*pathLink); ok {
+					pred, pa, inverse = node{term: link.iri}, nil, true
+				}
+			}
+			if pa != nil && p.template {
+				err = p.syntaxError("", "property paths are not allowed in templates")
+				return
+			}
+		}
+		for {
+			var obj node
+			if obj, err = p.parseObject(trip); err != nil {
+				return
+			}
+			if inverse {
+				*trip = append(*trip, triple{s: obj, p: pred, o: subj})
+			} else {
+				*trip = append(*trip, triple{s: subj, p: pred, path: pa, o: obj})
+			}
+			if !p.isPunct(",") {
+				break
+			}
+			p.next()
+		}
+		if !p.isPunct(";") {
+			return
+		}
+		for p.isPunct(";") {
+			p.next()
+		}
+		if !p.startsVerb() {
+			return
+		}
+	}
+}
+
+// parseObject parses an object (term, variable, blank node property list or collection)
+func (p *parser) parseObject(trip *[]triple) (n node, err error) {
+	if p.isPunct("[") || p.isPunct("(") {
+		n, err = p.parseTriplesNode(trip)
+		return
+	}
+	n, err = p.parseVarOrTerm()
+	return
+}
+
+// parseTriplesNode parses a blank node property list or a collection and adds its triples
+func (p *parser) parseTriplesNode(trip *[]triple) (n node, err error) {
+	if p.next().val == "[" {
+		n = p.newBlank()
+		if p.isPunct("]") {
+			p.next()
+			return
+		}
+		if err = p.parsePropertyList(n, trip); err != nil {
+			return
+		}
+		err = p.expectPunct("]")
+		return
+	}
+	var items []node
+	for !p.isPunct(")") {
+		var item node
+		if item, err = p.parseObject(trip); err != nil {
+			return
+		}
+		items = append(items, item)
+	}
+	p.next()
+	n = node{term: rdf.NewIRI(rdfNS + "nil")}
+	for i := len(items) - 1; i >= 0; i-- {
+		elem := p.newBlank()
+		*trip = append(*trip, triple{s: elem, p: node{term: rdf.NewIRI(rdfNS + "first")},
+			o: items[i]})
+		*trip = append(*trip, triple{s: elem, p: node{term: rdf.NewIRI(rdfNS + "rest")}, o: n})
+		n = elem
+	}
+	return
+}
+
+// newBlank creates an anonymous blank node
+func (p *parser) newBlank() (n node) {
+	p.numBlank++
+	label := "-anon" + strconv.Itoa(p.numBlank)
+	if p.template {
+		n.term = rdf.NewBlankNode(label)
+	} else {
+		n.name = "_:" + label
+	}
+	return
+}
+
+// parseVarOrTerm parses a variable, iri, blank node label or literal
+func (p *parser) parseVarOrTerm() (n node, err error) {
+	t := p.peek()
+	switch t.kind {
+	case tokVar:
+		p.next()
+		n.name = t.val
+	case tokIRI, tokPName:
+		n.term, err = p.parseIRI()
+	case tokBlank:
+		p.next()
+		if p.template {
+			n.term = rdf.NewBlankNode(t.val)
+		} else {
+			n.name = "_:" + t.val
+		}
+	default:
+		n.term, err = p.parseLiteral()
+	}
+	return
+}
+
+// parseIRI parses an iri reference or prefixed name
+func (p *parser) parseIRI() (iri rdf.IRI, err error) {
+	t := p.peek()
+	switch t.kind {
+	case tokIRI:
+		iri = rdf.NewIRI(rdf.ResolveIRI(p.base, t.val))
+	case tokPName:
+		i := strings.Index(t.val, ":")
+		ns, ok := p.prefixes[t.val[:i]]
+		if !ok {
+			err = p.syntaxError("", "undefined prefix "+t.val[:i])
+			return
+		}
+		iri = rdf.NewIRI(ns + t.val[i+1:])
+	default:
+		err = p.unexpected("iri")
+		return
+	}
+	p.next()
+	return
+}
+
+// parseLiteral parses a rdf literal, a numeric literal (optionally signed) or a boolean
+func (p *parser) parseLiteral() (lit rdf.Literal, err error) {
+	t := p.peek()
+	sign := ""
+	if p.isPunct("+") || p.isPunct("-") {
+		next := p.tok[p.i+1]
+		if next.pos != t.pos+1 || (next.kind != tokInteger && next.kind != tokDecimal &&
+			next.kind != tokDouble) {
+			err = p.unexpected("literal")
+			return
+		}
+		sign = t.val
+		p.next()
+		t = p.peek()
+	}
+	switch t.kind {
+	case tokString:
+		p.next()
+		if lang := p.peek(); lang.kind == tokLangTag {
+			p.next()
+			lit = rdf.NewLangLiteral(t.val, lang.val)
+		} else if p.isPunct("^^") {
+			p.next()
+			var dt rdf.IRI
+			if dt, err = p.parseIRI(); err != nil {
+				return
+			}
+			lit = rdf.NewTypedLiteral(t.val, dt.String())
+		} else {
+			lit = simpleLiteral(t.val)
+		}
+	case tokInteger:
+		lit = rdf.NewTypedLiteral(sign+t.val, rdf.XsdInteger)
+		p.next()
+	case tokDecimal:
+		lit = rdf.NewTypedLiteral(sign+t.val, rdf.XsdDecimal)
+		p.next()
+	case tokDouble:
+		lit = rdf.NewTypedLiteral(sign+t.val, rdf.XsdDouble)
+		p.next()
+	case tokWord:
+		if !p.isWord("true") && !p.isWord("false") {
+			err = p.unexpected("literal")
+			return
+		}
+		lit = boolLiteral(p.isWord("true"))
+		p.next()
+	default:
+		err = p.unexpected("term")
+	}
+	return
+}
+
+// parsePath parses a property path
+func (p *parser) parsePath() (pa path, err error) {
+	alt := &pathAlt{}
+	for {
+		seq := &pathSeq{}
+		for {
+			var elt path
+			if elt, err = p.parsePathElt(); err != nil {
+				return
+			}
+			seq.parts = append(seq.parts, elt)
+			if !p.isPunct("/") {
+				break
+			}
+			p.next()
+		}
+		if len(seq.parts) == 1 {
+			alt.parts = append(alt.parts, seq.parts[0])
+		} else {
+			alt.parts = append(alt.parts, seq)
+		}
+		if !p.isPunct("|") {
+			break
+		}
+		p.next()
+	}
+	pa = alt
+	if len(alt.parts) == 1 {
+		pa = alt.parts[0]
+	}
+	return
+}
+
+// parsePathElt parses an (inverse) path primary with an optional modifier
+func (p *parser) parsePathElt() (pa path, err error) {
+	inverse := p.isPunct("^")
+	if inverse {
+		p.next()
+	}
+	switch {
+	case p.isWord("a") && p.peek().val == "a":
+		p.next()
+		pa = &pathLink{iri: rdf.NewIRI(rdfNS + "type")}
+	case p.isPunct("!"):
+		p.next()
+		neg := &pathNeg{}
+		group := p.isPunct("(")
+		if group {
+			p.next()
+		}
+		for {
+			inv := p.isPunct("^")
+			if inv {
+				p.next()
+			}
+			var iri rdf.IRI
+			if p.isWord("a") && p.peek().val == "a" {
+				p.next()
+				iri = rdf.NewIRI(rdfNS + "type")
+			} else if iri, err = p.parseIRI(); err != nil {
+				return
+			}
+			if inv {
+				neg.inv = append(neg.inv, iri)
+			} else {
+				neg.fwd = append(neg.fwd, iri)
+			}
+			if !group || !p.isPunct("|") {
+				break
+			}
+			p.next()
+		}
+		if group {
+			if err = p.expectPunct(")"); err != nil {
+				return
+			}
+		}
+		pa = neg
+	case p.isPunct("("):
+		p.next()
+		if pa, err = p.parsePath(); err != nil {
+			return
+		}
+		if err = p.expectPunct(")"); err != nil {
+			return
+		}
+	default:
+		var iri rdf.IRI
+		if iri, err = p.parseIRI(); err != nil {
+			return
+		}
+		pa = &pathLink{iri: iri}
+	}
+	if p.isPunct("?") || p.isPunct("*") || p.isPunct("+") {
+		pa = &pathMod{p: pa, mod: p.next().val}
+	}
+	if inverse {
+		pa = &pathInv{p: pa}
+	}
+	return
+}
+
+// parseAggExpression parses an expression that may contain aggregates
+func (p *parser) parseAggExpression() (ex expr, err error) {
+	p.aggsValid = true
+	ex, err = p.parseExpression()
+	p.aggsValid = false
+	return
+}
+
+// parseExpression parses an expression ('||' has the lowest precedence)
+func (p *parser) parseExpression() (ex expr, err error) {
+	if ex, err = p.parseAnd(); err != nil {
+		return
+	}
+	for p.isPunct("||") {
+		p.next()
+		var right expr
+		if right, err = p.parseAnd(); err != nil {
+			return
+		}
+		ex = &binaryExpr{op: "||", left: ex, right: right}
+	}
+	return
+}
+
+// parseAnd parses a conjunction
+func (p *parser) parseAnd() (ex expr, err error) {
+	if ex, err = p.parseRelational(); err != nil {
+		return
+	}
+	for p.isPunct("&&") {
+		p.next()
+		var right expr
+		if right, err = p.parseRelational(); err != nil {
+			return
+		}
+		ex = &binaryExpr{op: "&&", left: ex, right: right}
+	}
+	return
+}
+
+// parseRelational parses a comparison or an IN expression
+func (p *parser) parseRelational() (ex expr, err error) {
+	if ex, err = p.parseAdditive(); err != nil {
+		return
+	}
+	for _, op := range []string{"=", "!=", "<", ">", "<=", ">="} {
+		if p.isPunct(op) {
+			p.next()
+			var right expr
+			if right, err = p.parseAdditive(); err == nil {
+				ex = &binaryExpr{op: op, left: ex, right: right}
+			}
+			return
+		}
+	}
+	not := p.isWord("NOT")
+	if not {
+		p.next()
+	}
+	if not || p.isWord("IN") {
+		if err = p.expectWord("IN"); err != nil {
+			return
+		}
+		in := &inExpr{arg: ex, not: not}
+		if in.list, err = p.parseArgs(); err == nil {
+			ex = in
+		}
+	}
+	return
+}
+
+// parseAdditive parses '+' and '-'
+func (p *parser) parseAdditive() (ex expr, err error) {
+	if ex, err = p.parseMultiplicative(); err != nil {
+		return
+	}
+	for p.isPunct("+") || p.isPunct("-") {
+		op := p.next().val
+		var right expr
+		if right, err = p.parseMultiplicative(); err != nil {
+			return
+		}
+		ex = &binaryExpr{op: op, left: ex, right: right}
+	}
+	return
+}
+
+// parseMultiplicative parses '*' and '/'
+func (p *parser) parseMultiplicative() (ex expr, err error) {
+	if ex, err = p.parseUnary(); err != nil {
+		return
+	}
+	for p.isPunct("*") || p.isPunct("/") {
+		op := p.next().val
+		var right expr
+		if right, err = p.parseUnary(); err != nil {
+			return
+		}
+		ex = &binaryExpr{op: op, left: ex, right: right}
+	}
+	return
+}
+
+// parseUnary parses '!', '+' and '-' in front of a primary expression
+func (p *parser) parseUnary() (ex expr, err error) {
+	if p.isPunct("!") || p.isPunct("+") || p.isPunct("-") {
+		op := p.next().val
+		var arg expr
+		if arg, err = p.parseUnary(); err == nil {
+			ex = &unaryExpr{op: op, arg: arg}
+		}
+		return
+	}
+	ex, err = p.parsePrimary()
+	return
+}
+
+// parsePrimary parses a bracketted expression, function call, variable or constant
+func (p *parser) parsePrimary() (ex expr, err error) {
+	t := p.peek()
+	switch {
+	case p.isPunct("("):
+		p.next()
+		if ex, err = p.parseExpression(); err == nil {
+			err = p.expectPunct(")")
+		}
+	case t.kind == tokVar:
+		p.next()
+		ex = &varExpr{name: t.val}
+	case t.kind == tokIRI || t.kind == tokPName:
+		var iri rdf.IRI
+		if iri, err = p.parseIRI(); err != nil {
+			return
+		}
+		if !p.isPunct("(") {
+			ex = &constExpr{term: iri}
+			return
+		}
+		call := &callExpr{name: iri.String()}
+		if call.args, err = p.parseArgs(); err == nil {
+			ex = call
+		}
+	case t.kind == tokWord && !p.isWord("true") && !p.isWord("false"):
+		ex, err = p.parseBuiltinCall()
+	default:
+		var lit rdf.Literal
+		if lit, err = p.parseLiteral(); err == nil {
+			ex = &constExpr{term: lit}
+		}
+	}
+	return
+}
+
+// parseBuiltinCall parses a call of a builtin function, an aggregate or (NOT) EXISTS
+func (p *parser) parseBuiltinCall() (ex expr, err error) {
+	name := strings.ToUpper(p.peek().val)
+	switch {
+	case name == "EXISTS" || name == "NOT":
+		p.next()
+		if name == "NOT" {
+			if err = p.expectWord("EXISTS"); err != nil {
+				return
+			}
+		}
+		var pat pattern
+		if pat, err = p.parseGroupGraphPattern(); err == nil {
+			ex = &existsExpr{pat: pat, not: name == "NOT"}
+		}
+		return
+	case aggregates[name]:
+		ex, err = p.parseAggregate(name)
+		return
+	}
+	arity, ok := builtins[name]
+	if !ok {
+		err = p.syntaxError("", "unknown function "+p.peek().val)
+		return
+	}
+	p.next()
+	call := &callExpr{name: name}
+	if call.args, err = p.parseArgs(); err != nil {
+		return
+	}
+	if len(call.args) < arity[0] || (arity[1] >= 0 && len(call.args) > arity[1]) {
+		err = p.syntaxError("", "wrong number of arguments for "+name)
+		return
+	}
+	if _, isVar := call.args[0].(*varExpr); name == "BOUND" && !isVar {
+		err = p.syntaxError("", "argument of BOUND must be a variable")
+		return
+	}
+	ex = call
+	return
+}
+
+// parseArgs parses an argument list in brackets
+func (p *parser) parseArgs() (args []expr, err error) {
+	if err = p.expectPunct("("); err != nil {
+		return
+	}
+	for !p.isPunct(")") {
+		if len(args) > 0 {
+			if err = p.expectPunct(","); err != nil {
+				return
+			}
+		}
+		var arg expr
+		if arg, err = p.parseExpression(); err != nil {
+			return
+		}
+		args = append(args, arg)
+	}
+	p.next()
+	return
+}
+
+// parseAggregate parses an aggregate and returns the variable that is bound to its value
+func (p *parser) parseAggregate(name string) (ex expr, err error) {
+	if !p.aggsValid {
+		err = p.syntaxError("", "aggregate "+name+" is not allowed here")
+		return
+	}
+	p.next()
+	if err = p.expectPunct("("); err != nil {
+		return
+	}
+	agg := &aggregate{name: name, sep: " "}
+	if p.isWord("DISTINCT") {
+		agg.distinct = true
+		p.next()
+	}
+	if name == "COUNT" && p.isPunct("*") {
+		p.next()
+	} else {
+		p.aggsValid = false
+		agg.arg, err = p.parseExpression()
+		p.aggsValid = true
+		if err != nil {
+			return
+		}
+	}
+	if name == "GROUP_CONCAT" && p.isPunct(";") {
+		p.next()
+		if err = p.expectWord("SEPARATOR"); err != nil {
+			return
+		}
+		if err = p.expectPunct("="); err != nil {
+			return
+		}
+		if p.peek().kind != tokString {
+			err = p.unexpected("string")
+			return
+		}
+		agg.sep = p.next().val
+	}
+	if err = p.expectPunct(")"); err != nil {
+		return
+	}
+	agg.result = ".agg" + strconv.Itoa(len(p.query.aggs))
+	p.query.aggs = append(p.query.aggs, agg)
+	ex = &varExpr{name: agg.result}
+	return
+}
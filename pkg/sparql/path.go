@@ -0,0 +1,271 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sparql
+
+import (
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// path is a property path
+type path interface {
+	// inverse returns the path that connects the same terms in opposite direction
+	inverse() (inv path)
+}
+
+// pathLink is a single predicate
+type pathLink struct {
+	iri rdf.IRI
+}
+
+// pathInv is an inverse path (^path)
+type pathInv struct {
+	p path
+}
+
+// pathSeq is a sequence of paths (path1/path2)
+type pathSeq struct {
+	parts []path
+}
+
+// pathAlt is an alternative of paths (path1|path2)
+type pathAlt struct {
+	parts []path
+}
+
+// pathMod is a path with a modifier ('?', '*' or '+')
+type pathMod struct {
+	p   path
+	mod string
+}
+
+// pathNeg is a negated property set (!(iri1|^iri2))
+type pathNeg struct {
+	fwd []rdf.IRI // excluded predicates
+	inv []rdf.IRI // excluded inverse predicates
+}
+
+// inverse returns the path that connects the same terms in opposite direction
+func (pa *pathLink) inverse() (inv path) {
+	inv = &pathInv{p: pa}
+	return
+}
+
+// inverse returns the path that connects the same terms in opposite direction
+func (pa *pathInv) inverse() (inv path) {
+	inv = pa.p
+	return
+}
+
+// inverse returns the path that connects the same terms in opposite direction
+func (pa *pathSeq) inverse() (inv path) {
+	seq := &pathSeq{}
+	for i := len(pa.parts) - 1; i >= 0; i-- {
+		seq.parts = append(seq.parts, pa.parts[i].inverse())
+	}
+	inv = seq
+	return
+}
+
+// inverse returns the path that connects the same terms in opposite direction
+func (pa *pathAlt) inverse() (inv path) {
+	alt := &pathAlt{}
+	for _, part := range pa.parts {
+		alt.parts = append(alt.parts, part.inverse())
+	}
+	inv = alt
+	return
+}
+
+// inverse returns the path that connects the same terms in opposite direction
+func (pa *pathMod) inverse() (inv path) {
+	inv = &pathMod{p: pa.p.inverse(), mod: pa.mod}
+	return
+}
+
+// inverse returns the path that connects the same terms in opposite direction
+func (pa *pathNeg) inverse() (inv path) {
+	inv = &pathNeg{fwd: pa.inv, inv: pa.fwd}
+	return
+}
+
+// evalPath returns all pairs of terms connected by the path; s and o restrict the start and end
+// of the path (nil: any term)
+func (e *evaluator) evalPath(pa path, s, o rdf.Term) (pairs [][2]rdf.Term) {
+	switch {
+	case s != nil:
+		for _, end := range e.pathTargets(pa, s) {
+			if o == nil || rdf.EqualTerms(end, o) {
+				pairs = append(pairs, [2]rdf.Term{s, end})
+			}
+		}
+	case o != nil:
+		for _, start := range e.pathTargets(pa.inverse(), o) {
+			pairs = append(pairs, [2]rdf.Term{start, o})
+		}
+	default:
+		for _, start := range e.allTerms() {
+			for _, end := range e.pathTargets(pa, start) {
+				pairs = append(pairs, [2]rdf.Term{start, end})
+			}
+		}
+	}
+	return
+}
+
+// pathTargets returns the terms that are reachable from start via the path; sequences and
+// alternatives may return terms several times
+func (e *evaluator) pathTargets(pa path, start rdf.Term) (targets []rdf.Term) {
+	switch t := pa.(type) {
+	case *pathLink:
+		for it := e.graph.Match(start, t.iri, nil); it.Next(); {
+			targets = append(targets, it.Edge().Object.Term)
+		}
+	case *pathInv:
+		if link, ok := t.p.(*pathLink); ok {
+			for it := e.graph.Match(nil, link.iri, start); it.Next(); {
+				targets = append(targets, it.Edge().Subject.Term)
+			}
+		} else {
+			targets = e.pathTargets(t.p.inverse(), start)
+		}
+	case *pathSeq:
+		targets = []rdf.Term{start}
+		for _, part := range t.parts {
+			var next []rdf.Term
+			for _, term := range targets {
+				next = append(next, e.pathTargets(part, term)...)
+			}
+			targets = next
+		}
+	case *pathAlt:
+		for _, part := range t.parts {
+			targets = append(targets, e.pathTargets(part, start)...)
+		}
+	case *pathMod:
+		targets = e.closure(t.p, start, t.mod)
+	case *pathNeg:
+		targets = e.negatedTargets(t, start)
+	}
+	return
+}
+
+// closure returns the distinct terms reachable from start by zero or one ('?'), zero or more ('*')
+// or one or more ('+') repetitions of the path
+func (e *evaluator) closure(pa path, start rdf.Term, mod string) (targets []rdf.Term) {
+	seen := make(map[string]bool)
+	add := func(term rdf.Term) (added bool) {
+		if key := rdf.TermKey(term); !seen[key] {
+			seen[key] = true
+			targets = append(targets, term)
+			added = true
+		}
+		return
+	}
+	if mod != "+" {
+		add(start)
+	}
+	queue := []rdf.Term{start}
+	for len(queue) > 0 {
+		term := queue[0]
+		queue = queue[1:]
+		for _, next := range e.pathTargets(pa, term) {
+			if add(next) && mod != "?" {
+				queue = append(queue, next)
+			}
+		}
+		if mod == "?" {
+			break
+		}
+	}
+	return
+}
+
+// negatedTargets returns the terms connected to start by a predicate that is not excluded
+func (e *evaluator) negatedTargets(pa *pathNeg, start rdf.Term) (targets []rdf.Term) {
+	excluded := func(pred rdf.Term, list []rdf.IRI) (found bool) {
+		for _, iri := range list {
+			if iri.String() == pred.String() {
+				found = true
+				return
+			}
+		}
+		return
+	}
+	if len(pa.fwd) > 0 || len(pa.inv) == 0 {
+		for it := e.graph.Match(start, nil, nil); it.Next(); {
+			edge := it.Edge()
+			if !excluded(edge.Pred, pa.fwd) {
+				targets = append(targets, edge.Object.Term)
+			}
+		}
+	}
+	if len(pa.inv) > 0 {
+		for it := e.graph.Match(nil, nil, start); it.Next(); {
+			edge := it.Edge()
+			if !excluded(edge.Pred, pa.inv) {
+				targets = append(targets, edge.Subject.Term)
+			}
+		}
+	}
+	return
+}
+
+// allTerms returns all distinct subjects and objects of the graph in order of appearance
+func (e *evaluator) allTerms() (terms []rdf.Term) {
+	if e.terms == nil {
+		seen := make(map[string]bool)
+		e.terms = []rdf.Term{}
+		for _, edge := range e.graph.Edges {
+			for _, term := range []rdf.Term{edge.Subject.Term, edge.Object.Term} {
+				if key := rdf.TermKey(term); !seen[key] {
+					seen[key] = true
+					e.terms = append(e.terms, term)
+				}
+			}
+		}
+	}
+	terms = e.terms
+	return
+}
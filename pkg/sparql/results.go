@@ -0,0 +1,282 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sparql
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// jsonTerm is a term in the SPARQL JSON result format
+type jsonTerm struct {
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	Lang     string `json:"xml:lang,omitempty"`
+	Datatype string `json:"datatype,omitempty"`
+}
+
+// checkForm returns an error for results of construct and describe queries (they contain triples
+// that can be written with the rdf encoders)
+func (res *Result) checkForm(ask bool) (err error) {
+	if res.Form == FormConstruct || res.Form == FormDescribe {
+		err = errors.New("Results of " + res.Form.String() +
+			" queries are triples (see rdf.EncodeTTL)")
+	} else if res.Form == FormAsk && !ask {
+		err = errors.New("Results of ASK queries cannot be written in this format")
+	}
+	return
+}
+
+// WriteJSON writes the result in the SPARQL 1.1 query results JSON format
+func (res *Result) WriteJSON(output io.Writer) (err error) {
+	if err = res.checkForm(true); err != nil {
+		return
+	}
+	var doc interface{}
+	if res.Form == FormAsk {
+		doc = struct {
+			Head    struct{} `json:"head"`
+			Boolean bool     `json:"boolean"`
+		}{Boolean: res.Boolean}
+	} else {
+		bindings := make([]map[string]jsonTerm, len(res.Bindings))
+		for i, sol := range res.Bindings {
+			bindings[i] = make(map[string]jsonTerm)
+			for _, name := range res.Vars {
+				if term, ok := sol[name]; ok {
+					bindings[i][name] = toJSONTerm(term)
+				}
+			}
+		}
+		vars := res.Vars
+		if vars == nil {
+			vars = []string{}
+		}
+		doc = struct {
+			Head struct {
+				Vars []string `json:"vars"`
+			} `json:"head"`
+			Results struct {
+				Bindings []map[string]jsonTerm `json:"bindings"`
+			} `json:"results"`
+		}{Head: struct {
+			Vars []string `json:"vars"`
+		}{Vars: vars}, Results: struct {
+			Bindings []map[string]jsonTerm `json:"bindings"`
+		}{Bindings: bindings}}
+	}
+	enc := json.NewEncoder(output)
+	enc.SetEscapeHTML(false)
+	err = enc.Encode(doc)
+	return
+}
+
+// toJSONTerm converts a term to the SPARQL JSON result format
+func toJSONTerm(term rdf.Term) (jt jsonTerm) {
+	jt.Value = term.String()
+	switch t := term.(type) {
+	case rdf.IRI:
+		jt.Type = "uri"
+	case rdf.BlankNode:
+		jt.Type = "bnode"
+	case rdf.Literal:
+		jt.Type = "literal"
+		jt.Lang = t.Lang()
+		if dt := t.Datatype(); dt != rdf.XsdString && dt != rdf.RdfLangString {
+			jt.Datatype = dt
+		}
+	}
+	return
+}
+
+// WriteXML writes the result in the SPARQL query results XML format
+func (res *Result) WriteXML(output io.Writer) (err error) {
+	if err = res.checkForm(true); err != nil {
+		return
+	}
+	var b strings.Builder
+	b.WriteString("<?xml version=\"1.0\"?>\n")
+	b.WriteString("<sparql xmlns=\"http://www.w3.org/2005/sparql-results#\">\n")
+	b.WriteString("  <head>\n")
+	for _, name := range res.Vars {
+		b.WriteString("    <variable name=\"" + escapeXML(name) + "\"/>\n")
+	}
+	b.WriteString("  </head>\n")
+	if res.Form == FormAsk {
+		if res.Boolean {
+			b.WriteString("  <boolean>true</boolean>\n")
+		} else {
+			b.WriteString("  <boolean>false</boolean>\n")
+		}
+	} else {
+		b.WriteString("  <results>\n")
+		for _, sol := range res.Bindings {
+			b.WriteString("    <result>\n")
+			for _, name := range res.Vars {
+				if term, ok := sol[name]; ok {
+					b.WriteString("      <binding name=\"" + escapeXML(name) + "\">" +
+						xmlTerm(term) + "</binding>\n")
+				}
+			}
+			b.WriteString("    </result>\n")
+		}
+		b.WriteString("  </results>\n")
+	}
+	b.WriteString("</sparql>\n")
+	_, err = io.WriteString(output, b.String())
+	return
+}
+
+// xmlTerm converts a term to the SPARQL XML result format
+func xmlTerm(term rdf.Term) (str string) {
+	switch t := term.(type) {
+	case rdf.IRI:
+		str = "<uri>" + escapeXML(t.String()) + "</uri>"
+	case rdf.BlankNode:
+		str = "<bnode>" + escapeXML(t.String()) + "</bnode>"
+	case rdf.Literal:
+		str = "<literal"
+		if t.Lang() != "" {
+			str += " xml:lang=\"" + escapeXML(t.Lang()) + "\""
+		} else if dt := t.Datatype(); dt != rdf.XsdString {
+			str += " datatype=\"" + escapeXML(dt) + "\""
+		}
+		str += ">" + escapeXML(t.String()) + "</literal>"
+	}
+	return
+}
+
+// escapeXML escapes a string for xml text and attribute values
+func escapeXML(in string) (out string) {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(in))
+	out = b.String()
+	return
+}
+
+// WriteCSV writes the result of a select query in the SPARQL 1.1 CSV format (values without
+// datatype and language)
+func (res *Result) WriteCSV(output io.Writer) (err error) {
+	if err = res.checkForm(false); err != nil {
+		return
+	}
+	w := csv.NewWriter(output)
+	w.UseCRLF = true
+	if err = w.Write(res.Vars); err != nil {
+		return
+	}
+	for _, sol := range res.Bindings {
+		row := make([]string, len(res.Vars))
+		for i, name := range res.Vars {
+			if term, ok := sol[name]; ok {
+				row[i] = term.String()
+				if term.Type() == rdf.TermBlankNode {
+					row[i] = "_:" + row[i]
+				}
+			}
+		}
+		if err = w.Write(row); err != nil {
+			return
+		}
+	}
+	w.Flush()
+	err = w.Error()
+	return
+}
+
+// WriteTSV writes the result of a select query in the SPARQL 1.1 TSV format (terms in turtle
+// syntax)
+func (res *Result) WriteTSV(output io.Writer) (err error) {
+	if err = res.checkForm(false); err != nil {
+		return
+	}
+	var b strings.Builder
+	for i, name := range res.Vars {
+		if i > 0 {
+			b.WriteString("\t")
+		}
+		b.WriteString("?" + name)
+	}
+	b.WriteString("\n")
+	for _, sol := range res.Bindings {
+		for i, name := range res.Vars {
+			if i > 0 {
+				b.WriteString("\t")
+			}
+			if term, ok := sol[name]; ok {
+				b.WriteString(tsvTerm(term))
+			}
+		}
+		b.WriteString("\n")
+	}
+	_, err = io.WriteString(output, b.String())
+	return
+}
+
+// tsvTerm converts a term to turtle syntax without prefixes
+func tsvTerm(term rdf.Term) (str string) {
+	switch t := term.(type) {
+	case rdf.IRI:
+		str = "<" + t.String() + ">"
+	case rdf.BlankNode:
+		str = "_:" + t.String()
+	case rdf.Literal:
+		str = "\"" + strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n", "\r", "\\r",
+			"\t", "\\t").Replace(t.String()) + "\""
+		if t.Lang() != "" {
+			str += "@" + t.Lang()
+		} else if dt := t.Datatype(); dt != rdf.XsdString {
+			str += "^^<" + dt + ">"
+		}
+	}
+	return
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package sparql implements a SPARQL 1.1 query engine for SELECT, CONSTRUCT, ASK and DESCRIBE
+// queries over an rdf graph. Results can be written in the SPARQL JSON, XML, CSV and TSV result
+// formats.
+package sparql
+
+import (
+	"strconv"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// QueryForm is the form of a query (SELECT, CONSTRUCT, ASK or DESCRIBE)
+type QueryForm int
+
+// possible query forms
+const (
+	FormSelect QueryForm = iota
+	FormConstruct
+	FormAsk
+	FormDescribe
+)
+
+// Binding maps variable names (without leading '?') to the terms bound in a solution; unbound
+// variables are missing
+type Binding map[string]rdf.Term
+
+// Query is a parsed SPARQL query
+type Query struct {
+	Form     QueryForm         // form of the query
+	Vars     []string          // variables of the solutions (select)
+	Prefixes map[string]string // prefixes declared in the query
+	Base     string            // base iri of the query
+
+	star     bool         // select *
+	project  []projection // select expressions
+	distinct bool         // select distinct
+	reduced  bool         // select reduced
+	where    pattern      // where clause
+	template []triple     // construct template
+	describe []node       // described resources
+	groupBy  []projection // group conditions
+	aggs     []*aggregate // aggregates used in select, having and order by
+	having   []expr       // having conditions
+	orderBy  []orderCond  // order conditions
+	limit    int          // maximum number of solutions (-1: no limit)
+	offset   int          // number of skipped solutions
+	values   *valuesBlock // trailing values clause
+}
+
+// projection is a variable of the select clause or group by clause that is bound to an expression
+type projection struct {
+	name string // variable name (empty for group conditions without variable)
+	expr expr   // expression (nil for plain variables)
+}
+
+// orderCond is an order condition
+type orderCond struct {
+	expr expr
+	desc bool
+}
+
+// Result is the result of a query
+type Result struct {
+	Form     QueryForm    // form of the query
+	Vars     []string     // variables of the solutions (select)
+	Bindings []Binding    // solutions (select)
+	Boolean  bool         // result of ask queries
+	Triples  []rdf.Triple // created triples (construct and describe)
+}
+
+// Exec parses a query and evaluates it over the graph
+func Exec(graph *rdf.Graph, query string) (res *Result, err error) {
+	q, err := Parse(query)
+	if err != nil {
+		return
+	}
+	res, err = q.Exec(graph)
+	return
+}
+
+// Exec evaluates the query over the graph
+func (q *Query) Exec(graph *rdf.Graph) (res *Result, err error) {
+	e := newEvaluator(graph)
+	e.base = q.Base
+	sols, err := e.evalQuery(q, Binding{})
+	if err != nil {
+		return
+	}
+	res = &Result{Form: q.Form, Vars: q.Vars}
+	switch q.Form {
+	case FormSelect:
+		res.Bindings = sols
+	case FormAsk:
+		res.Boolean = len(sols) > 0
+	case FormConstruct:
+		res.Triples = e.construct(q.template, sols)
+	case FormDescribe:
+		res.Triples = e.describe(q.describe, sols)
+	}
+	return
+}
+
+// Compare compares the values of two literals like the operators < and > of SPARQL (-1: a < b,
+// 0: a = b, 1: a > b); an error is returned if the values cannot be compared
+func Compare(a, b rdf.Term) (cmp int, err error) {
+	cmp, err = compareValues(a, b)
+	return
+}
+
+// String returns the name of the query form
+func (form QueryForm) String() (str string) {
+	switch form {
+	case FormSelect:
+		str = "SELECT"
+	case FormConstruct:
+		str = "CONSTRUCT"
+	case FormAsk:
+		str = "ASK"
+	case FormDescribe:
+		str = "DESCRIBE"
+	default:
+		str = "QueryForm(" + strconv.Itoa(int(form)) + ")"
+	}
+	return
+}
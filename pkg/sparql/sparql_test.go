@@ -0,0 +1,256 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sparql
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/internal/testsuite"
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// base iri of the fixtures in testdata and vocabularies of the W3C test manifests and results
+const (
+	sparqlTests = "http://example.org/owl2go/sparql/"
+	qtNS        = "http://www.w3.org/2001/sw/DataAccess/tests/test-query#"
+	rsNS        = "http://www.w3.org/2001/sw/DataAccess/tests/result-set#"
+)
+
+// jsonResult is a result in the SPARQL JSON result format
+type jsonResult struct {
+	Head struct {
+		Vars []string `json:"vars"`
+	} `json:"head"`
+	Results *struct {
+		Bindings []map[string]jsonTerm `json:"bindings"`
+	} `json:"results"`
+	Boolean *bool `json:"boolean"`
+}
+
+// queryTest is a query test of the manifest
+type queryTest struct {
+	testsuite.Entry
+	query string // file name of the query
+	data  string // file name of the default graph (empty: syntax test)
+}
+
+// readManifest reads the query tests of the manifest in the directory
+func readManifest(t *testing.T, dir string) (tests []queryTest) {
+	graph, entries := testsuite.ReadManifest(t, filepath.Join(dir, "manifest.ttl"),
+		sparqlTests+"manifest.ttl")
+	for _, entry := range entries {
+		test := queryTest{Entry: entry, query: testsuite.File(entry.Action, sparqlTests)}
+		if entry.Action.Type() != rdf.TermIRI {
+			test.query = testsuite.File(testsuite.Object(graph, entry.Action, qtNS+"query"),
+				sparqlTests)
+			test.data = testsuite.File(testsuite.Object(graph, entry.Action, qtNS+"data"),
+				sparqlTests)
+		}
+		tests = append(tests, test)
+	}
+	return
+}
+
+// fromJSONTerm converts a term of the SPARQL JSON result format
+func fromJSONTerm(jt jsonTerm) (term rdf.Term) {
+	switch {
+	case jt.Type == "uri":
+		term = rdf.NewIRI(jt.Value)
+	case jt.Type == "bnode":
+		term = rdf.NewBlankNode(jt.Value)
+	case jt.Lang != "":
+		term = rdf.NewLangLiteral(jt.Value, jt.Lang)
+	case jt.Datatype != "":
+		term = rdf.NewTypedLiteral(jt.Value, jt.Datatype)
+	default:
+		term = rdf.NewTypedLiteral(jt.Value, rdf.XsdString)
+	}
+	return
+}
+
+// resultGraph converts solutions to a graph of the W3C result set vocabulary, so that solutions
+// can be compared by graph isomorphism with a consistent mapping of their blank nodes; literals are
+// written in their canonical form and the solutions are numbered if ordered is set
+func resultGraph(sols []Binding, ordered bool) (graph *rdf.Graph) {
+	graph = &rdf.Graph{Nodes: make(map[string]*rdf.Node)}
+	set := rdf.NewBlankNode("set")
+	graph.AddTriple(rdf.Triple{Sub: set, Pred: rdf.NewIRI(rdfNS + "type"),
+		Obj: rdf.NewIRI(rsNS + "ResultSet")})
+	for i, sol := range sols {
+		node := rdf.NewBlankNode("sol" + strconv.Itoa(i))
+		graph.AddTriple(rdf.Triple{Sub: set, Pred: rdf.NewIRI(rsNS + "solution"), Obj: node})
+		if ordered {
+			graph.AddTriple(rdf.Triple{Sub: node, Pred: rdf.NewIRI(rsNS + "index"),
+				Obj: rdf.NewTypedLiteral(strconv.Itoa(i+1), rdf.XsdInteger)})
+		}
+		for name, term := range sol {
+			binding := rdf.NewBlankNode("sol" + strconv.Itoa(i) + "_" + name)
+			graph.AddTriple(rdf.Triple{Sub: node, Pred: rdf.NewIRI(rsNS + "binding"),
+				Obj: binding})
+			graph.AddTriple(rdf.Triple{Sub: binding, Pred: rdf.NewIRI(rsNS + "variable"),
+				Obj: rdf.NewTypedLiteral(name, rdf.XsdString)})
+			graph.AddTriple(rdf.Triple{Sub: binding, Pred: rdf.NewIRI(rsNS + "value"),
+				Obj: resultValue(term)})
+		}
+	}
+	return
+}
+
+// resultValue returns the term of a solution with blank node labels that do not clash with the
+// blank nodes of the result set and literals in their canonical form
+func resultValue(term rdf.Term) (value rdf.Term) {
+	value = term
+	switch t := term.(type) {
+	case rdf.BlankNode:
+		value = rdf.NewBlankNode("value_" + t.String())
+	case rdf.Literal:
+		if t.Lang() != "" {
+			return
+		}
+		dt, ok := rdf.LookupDatatype(t.Datatype())
+		if !ok {
+			return
+		}
+		if canonical, err := dt.Canonical(t.String()); err == nil {
+			value = rdf.NewTypedLiteral(canonical, t.Datatype())
+		}
+	}
+	return
+}
+
+// checkSelect compares the result of a select or ask query with the expected result in the SPARQL
+// JSON result format
+func checkSelect(t *testing.T, res *Result, file string, ordered bool) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exp jsonResult
+	if err = json.Unmarshal(data, &exp); err != nil {
+		t.Fatalf("%s: %v", file, err)
+	}
+	if exp.Boolean != nil {
+		if res.Form != FormAsk || res.Boolean != *exp.Boolean {
+			t.Errorf("got %s result %v, want %v", res.Form, res.Boolean, *exp.Boolean)
+		}
+		return
+	}
+	if strings.Join(res.Vars, " ") != strings.Join(exp.Head.Vars, " ") {
+		t.Errorf("got variables %v, want %v", res.Vars, exp.Head.Vars)
+	}
+	var want []Binding
+	for _, jb := range exp.Results.Bindings {
+		sol := Binding{}
+		for name, jt := range jb {
+			sol[name] = fromJSONTerm(jt)
+		}
+		want = append(want, sol)
+	}
+	if !rdf.Isomorphic(resultGraph(res.Bindings, ordered), resultGraph(want, ordered)) {
+		var buf bytes.Buffer
+		res.WriteJSON(&buf)
+		t.Errorf("solutions differ, got:\n%s", buf.String())
+	}
+}
+
+// checkConstruct compares the triples of a construct query with the expected graph
+func checkConstruct(t *testing.T, res *Result, file string) {
+	exp := testsuite.DecodeTTL(t, file, sparqlTests+filepath.Base(file))
+	got, err := rdf.NewGraph(res.Triples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rdf.Isomorphic(&got, exp) {
+		var buf bytes.Buffer
+		rdf.EncodeNTriples(res.Triples, &buf)
+		t.Errorf("graphs differ, got:\n%s", buf.String())
+	}
+}
+
+func TestQueryFixtures(t *testing.T) {
+	dir := "testdata"
+	for _, test := range readManifest(t, dir) {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(filepath.Join(dir, test.query))
+			if err != nil {
+				t.Fatal(err)
+			}
+			query := string(data)
+			switch test.Type {
+			case "PositiveSyntaxTest11":
+				if _, err = Parse(query); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			case "NegativeSyntaxTest11":
+				if _, err = Parse(query); err == nil {
+					t.Errorf("expected error")
+				}
+			case "QueryEvaluationTest":
+				graph := testsuite.DecodeTTL(t, filepath.Join(dir, test.data),
+					sparqlTests+test.data)
+				res, err := Exec(graph, query)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				result := testsuite.File(test.Result, sparqlTests)
+				file := filepath.Join(dir, result)
+				if strings.HasSuffix(result, ".ttl") {
+					checkConstruct(t, res, file)
+				} else {
+					checkSelect(t, res, file, strings.Contains(query, "\nORDER BY"))
+				}
+			default:
+				t.Fatalf("unknown test type %s", test.Type)
+			}
+		})
+	}
+}
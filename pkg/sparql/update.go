@@ -0,0 +1,283 @@
+/*
+Copyright 2020 Institute for Automation of Complex Power Systems,
+E.ON Energy Research Center, RWTH Aachen University
+
+This project is licensed under either of
+- Apache License, Version 2.0
+- MIT License
+at your option.
+
+Apache License, Version 2.0:
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+MIT License:
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sparql
+
+import (
+	"git.rwth-aachen.de/acs/public/ontology/owl/owl2go/pkg/rdf"
+)
+
+// Update is a parsed SPARQL update request (a sequence of operations)
+type Update struct {
+	Prefixes map[string]string // prefixes declared in the request
+	Base     string            // base iri of the request
+
+	ops []*updateOp // operations in the order of the request
+}
+
+// updateOp is an update operation; INSERT DATA and DELETE DATA have no where clause and are
+// instantiated with a single empty solution
+type updateOp struct {
+	delete []triple // delete template
+	insert []triple // insert template
+	where  pattern  // where clause (nil for data operations)
+	clear  bool     // remove all triples (CLEAR and DROP)
+}
+
+// UpdateResult is the result of an update request
+type UpdateResult struct {
+	Added   int // number of triples that were added to the graph
+	Removed int // number of triples that were removed from the graph
+}
+
+// ExecUpdate parses an update request and applies it to the graph
+func ExecUpdate(graph *rdf.Graph, update string) (res UpdateResult, err error) {
+	u, err := ParseUpdate(update)
+	if err != nil {
+		return
+	}
+	res, err = u.Exec(graph)
+	return
+}
+
+// ParseUpdate parses a SPARQL update request; INSERT DATA, DELETE DATA, DELETE WHERE,
+// DELETE/INSERT ... WHERE, CLEAR and DROP are supported (operations are separated by ';')
+func ParseUpdate(update string) (u *Update, err error) {
+	tok, lines, err := tokenize(update)
+	if err != nil {
+		return
+	}
+	p := &parser{tok: tok, runes: []rune(update), lines: lines, prefixes: make(map[string]string)}
+	u, err = p.parseUpdate()
+	if err != nil {
+		u = nil
+	}
+	return
+}
+
+// Exec applies the operations of the update request to the graph; the where clause of an
+// operation is evaluated before any triple is removed or added, and triples that are not in the
+// graph (delete) or already in the graph (insert) are not counted
+func (u *Update) Exec(graph *rdf.Graph) (res UpdateResult, err error) {
+	for _, op := range u.ops {
+		if op.clear {
+			res.Removed += len(graph.Edges)
+			graph.Nodes = make(map[string]*rdf.Node)
+			graph.Edges = nil
+			continue
+		}
+		e := newEvaluator(graph)
+		e.base = u.Base
+		sols := []Binding{{}}
+		if op.where != nil {
+			if sols, err = e.eval(op.where, Binding{}); err != nil {
+				return
+			}
+		}
+		del := e.construct(op.delete, sols)
+		ins := e.construct(op.insert, sols)
+		res.Removed += graph.RemoveTriple(del...)
+		for _, t := range ins {
+			if _, added := graph.AddTriple(t); added {
+				res.Added++
+			}
+		}
+	}
+	return
+}
+
+// parseUpdate parses the operations of an update request
+func (p *parser) parseUpdate() (u *Update, err error) {
+	u = &Update{}
+	for {
+		if err = p.parsePrologue(); err != nil {
+			return
+		}
+		if p.peek().kind == tokEOF {
+			break
+		}
+		var op *updateOp
+		if op, err = p.parseUpdateOp(); err != nil {
+			return
+		}
+		u.ops = append(u.ops, op)
+		if !p.isPunct(";") {
+			break
+		}
+		p.next()
+	}
+	if p.peek().kind != tokEOF {
+		err = p.unexpected(";")
+		return
+	}
+	u.Prefixes, u.Base = p.prefixes, p.base
+	return
+}
+
+// parseUpdateOp parses a single update operation
+func (p *parser) parseUpdateOp() (op *updateOp, err error) {
+	op = &updateOp{}
+	switch {
+	case p.isWord("INSERT"):
+		p.next()
+		if p.isWord("DATA") {
+			p.next()
+			op.insert, err = p.parseQuadData("INSERT DATA", true)
+			return
+		}
+		if op.insert, err = p.parseQuadPattern("INSERT", true); err != nil {
+			return
+		}
+		op.where, err = p.parseUpdateWhere()
+	case p.isWord("DELETE"):
+		p.next()
+		switch {
+		case p.isWord("DATA"):
+			p.next()
+			op.delete, err = p.parseQuadData("DELETE DATA", false)
+		case p.isWord("WHERE"):
+			p.next()
+			if op.delete, err = p.parseQuadPattern("DELETE WHERE", false); err != nil {
+				return
+			}
+			op.where = &bgp{triples: op.delete}
+		default:
+			if op.delete, err = p.parseQuadPattern("DELETE", false); err != nil {
+				return
+			}
+			if p.isWord("INSERT") {
+				p.next()
+				if op.insert, err = p.parseQuadPattern("INSERT", true); err != nil {
+					return
+				}
+			}
+			op.where, err = p.parseUpdateWhere()
+		}
+	case p.isWord("CLEAR") || p.isWord("DROP"):
+		p.next()
+		if p.isWord("SILENT") {
+			p.next()
+		}
+		if !p.isWord("DEFAULT") && !p.isWord("ALL") {
+			err = p.syntaxError("DEFAULT", "named graphs are not supported (updates are applied "+
+				"to a single graph)")
+			return
+		}
+		p.next()
+		op.clear = true
+	case p.isWord("WITH"):
+		err = p.syntaxError("", "WITH is not supported (updates are applied to a single graph)")
+	default:
+		for _, word := range []string{"LOAD", "CREATE", "ADD", "MOVE", "COPY"} {
+			if p.isWord(word) {
+				err = p.syntaxError("", word+" is not supported (updates are "+
+					"applied to a single graph)")
+				return
+			}
+		}
+		err = p.unexpected("INSERT")
+	}
+	return
+}
+
+// parseUpdateWhere parses the where clause of a DELETE/INSERT operation
+func (p *parser) parseUpdateWhere() (pat pattern, err error) {
+	if p.isWord("USING") {
+		err = p.syntaxError("", "USING is not supported (updates are applied to a single graph)")
+		return
+	}
+	if err = p.expectWord("WHERE"); err != nil {
+		return
+	}
+	pat, err = p.parseGroupGraphPattern()
+	return
+}
+
+// parseQuadData parses the triples of INSERT DATA or DELETE DATA; variables are not allowed
+func (p *parser) parseQuadData(op string, blanks bool) (trip []triple, err error) {
+	if trip, err = p.parseQuadPattern(op, blanks); err != nil {
+		return
+	}
+	for _, t := range trip {
+		if t.s.term == nil || t.p.term == nil || t.o.term == nil {
+			err = p.syntaxError("", "variables are not allowed in "+op)
+			return
+		}
+	}
+	return
+}
+
+// parseQuadPattern parses a triples template in curly brackets; blank nodes are terms that are
+// replaced by new blank nodes (insert) or are not allowed (delete)
+func (p *parser) parseQuadPattern(op string, blanks bool) (trip []triple, err error) {
+	if err = p.expectPunct("{"); err != nil {
+		return
+	}
+	if p.isWord("GRAPH") {
+		err = p.syntaxError("", "GRAPH is not supported (updates are applied to a single graph)")
+		return
+	}
+	p.template = true
+	trip, err = p.parseTriplesTemplate()
+	p.template = false
+	if err != nil {
+		return
+	}
+	if !blanks {
+		for _, t := range trip {
+			if isBlankNode(t.s) || isBlankNode(t.o) {
+				err = p.syntaxError("", "blank nodes are not allowed in "+op)
+				return
+			}
+		}
+	}
+	err = p.expectPunct("}")
+	return
+}
+
+// isBlankNode checks if the node of a template is a blank node
+func isBlankNode(n node) (ok bool) {
+	ok = n.term != nil && n.term.Type() == rdf.TermBlankNode
+	return
+}